@@ -0,0 +1,284 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	ConfirmTypeValset         = "valset"
+	ConfirmTypeDataCommitment = "data_commitment"
+)
+
+// Confirm a confirm archived by the relayer, along with the attestation it signs.
+type Confirm struct {
+	Type       string `json:"type"`
+	Nonce      uint64 `json:"nonce"`
+	EVMAddress string `json:"evm_address"`
+	// Digest the signed digest: the valset sign bytes or the data root tuple root.
+	Digest    string `json:"digest"`
+	Signature string `json:"signature"`
+}
+
+// Filter selects the archived confirms. The zero value selects all of them.
+type Filter struct {
+	// FromNonce the lowest nonce, inclusive.
+	FromNonce uint64
+	// ToNonce the highest nonce, inclusive. Zero means no upper bound.
+	ToNonce uint64
+	// EVMAddress the signer EVM address. Empty means any signer.
+	EVMAddress string
+	// Digest the signed digest. Empty means any digest.
+	Digest string
+	// Type the confirm type, ConfirmTypeValset or ConfirmTypeDataCommitment. Empty means both.
+	Type string
+	// Limit the maximum number of returned confirms. Zero means no limit.
+	Limit int
+}
+
+// Validate checks that the filter is valid.
+func (f Filter) Validate() error {
+	if f.ToNonce != 0 && f.ToNonce < f.FromNonce {
+		return fmt.Errorf("%w: from %d to %d", ErrInvalidNonceRange, f.FromNonce, f.ToNonce)
+	}
+	if f.EVMAddress != "" && !ethcmn.IsHexAddress(f.EVMAddress) {
+		return fmt.Errorf("%w: %s", ErrInvalidEVMAddress, f.EVMAddress)
+	}
+	if f.Type != "" && f.Type != ConfirmTypeValset && f.Type != ConfirmTypeDataCommitment {
+		return fmt.Errorf("%w: %s", ErrUnknownType, f.Type)
+	}
+	if f.Limit < 0 {
+		return fmt.Errorf("the limit cannot be negative: %d", f.Limit)
+	}
+	return nil
+}
+
+// matches returns true if the confirm key fields are selected by the filter.
+func (f Filter) matches(nonce uint64, evmAddr string, digest string) bool {
+	if nonce < f.FromNonce || (f.ToNonce != 0 && nonce > f.ToNonce) {
+		return false
+	}
+	if f.EVMAddress != "" && !strings.EqualFold(evmAddr, f.EVMAddress) {
+		return false
+	}
+	if f.Digest != "" && !strings.EqualFold(digest, f.Digest) {
+		return false
+	}
+	return true
+}
+
+// Archive reads the confirms archived by the relayer in its signature store.
+type Archive struct {
+	store  ds.Read
+	logger tmlog.Logger
+}
+
+// New creates an archive reading from the provided relayer signature store.
+func New(store ds.Read) *Archive {
+	return &Archive{store: store, logger: tmlog.NewNopLogger()}
+}
+
+// WithLogger sets the logger used to report the invalid archived confirms, which are skipped.
+func (a *Archive) WithLogger(logger tmlog.Logger) *Archive {
+	a.logger = logger
+	return a
+}
+
+// archivedKey an archived confirm key along with its parsed fields.
+type archivedKey struct {
+	key         string
+	confirmType string
+	nonce       uint64
+	evmAddress  string
+}
+
+// List returns the archived confirms selected by the filter, ordered by nonce, then by type and
+// signer EVM address.
+// As all the filter fields are part of the confirms keys, the confirms are selected using a keys only
+// query of their namespaces, and only the values of the returned confirms are read.
+// The invalid archived confirms are logged and skipped.
+func (a *Archive) List(ctx context.Context, filter Filter) ([]Confirm, error) {
+	keys, err := a.selectSortedKeys(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	confirms := make([]Confirm, 0)
+	for _, key := range keys {
+		if filter.Limit != 0 && len(confirms) == filter.Limit {
+			break
+		}
+		confirm, ok, err := a.read(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			confirms = append(confirms, confirm)
+		}
+	}
+	return confirms, nil
+}
+
+// ListPage returns a page of the archived confirms selected by the filter, ordered like List, along
+// with the nonce starting the next page, zero if it's the last one.
+// The pages end at a nonce boundary so that listing from the next nonce doesn't skip or repeat confirms.
+// Thus, a page contains fewer confirms than the limit when it would end in the middle of a nonce, or all
+// the confirms of its nonce when they are more than the limit.
+func (a *Archive) ListPage(ctx context.Context, filter Filter) (ListResponse, error) {
+	keys, err := a.selectSortedKeys(ctx, filter)
+	if err != nil {
+		return ListResponse{}, err
+	}
+	confirms := make([]Confirm, 0)
+	for _, key := range keys {
+		if filter.Limit != 0 && len(confirms) >= filter.Limit {
+			lastNonce := confirms[len(confirms)-1].Nonce
+			if key.nonce != lastNonce {
+				return ListResponse{Confirms: confirms, NextNonce: key.nonce}, nil
+			}
+			// the page would end in the middle of a nonce, which is left to the next page, unless
+			// the page only contains this nonce
+			if confirms[0].Nonce != lastNonce {
+				end := len(confirms)
+				for end > 0 && confirms[end-1].Nonce == lastNonce {
+					end--
+				}
+				return ListResponse{Confirms: confirms[:end], NextNonce: lastNonce}, nil
+			}
+		}
+		confirm, ok, err := a.read(ctx, key)
+		if err != nil {
+			return ListResponse{}, err
+		}
+		if ok {
+			confirms = append(confirms, confirm)
+		}
+	}
+	return ListResponse{Confirms: confirms}, nil
+}
+
+// selectSortedKeys returns the keys of the confirms selected by the filter, ordered by nonce, then by type
+// and signer EVM address.
+func (a *Archive) selectSortedKeys(ctx context.Context, filter Filter) ([]archivedKey, error) {
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+	namespaces := []string{p2p.ValsetConfirmNamespace, p2p.DataCommitmentConfirmNamespace}
+	switch filter.Type {
+	case ConfirmTypeValset:
+		namespaces = []string{p2p.ValsetConfirmNamespace}
+	case ConfirmTypeDataCommitment:
+		namespaces = []string{p2p.DataCommitmentConfirmNamespace}
+	}
+
+	keys := make([]archivedKey, 0)
+	for _, namespace := range namespaces {
+		namespaceKeys, err := a.selectKeys(ctx, namespace, filter)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, namespaceKeys...)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].nonce != keys[j].nonce {
+			return keys[i].nonce < keys[j].nonce
+		}
+		if keys[i].confirmType != keys[j].confirmType {
+			return keys[i].confirmType < keys[j].confirmType
+		}
+		return keys[i].evmAddress < keys[j].evmAddress
+	})
+	return keys, nil
+}
+
+// read reads the archived confirm under the provided key. Returns false if it was pruned since
+// selected, or if it's invalid, in which case it's logged.
+func (a *Archive) read(ctx context.Context, key archivedKey) (Confirm, bool, error) {
+	value, err := a.store.Get(ctx, ds.RawKey(key.key))
+	if errors.Is(err, ds.ErrNotFound) {
+		return Confirm{}, false, nil
+	}
+	if err != nil {
+		return Confirm{}, false, err
+	}
+	confirm, err := decodeConfirm(key.key, value)
+	if err != nil {
+		a.logger.Error("skipping invalid archived confirm", "key", key.key, "err", err.Error())
+		return Confirm{}, false, nil
+	}
+	return confirm, true, nil
+}
+
+// selectKeys returns the keys of the confirms, under the provided namespace, selected by the filter.
+func (a *Archive) selectKeys(ctx context.Context, namespace string, filter Filter) ([]archivedKey, error) {
+	typ := ConfirmTypeValset
+	if namespace == p2p.DataCommitmentConfirmNamespace {
+		typ = ConfirmTypeDataCommitment
+	}
+	results, err := a.store.Query(ctx, query.Query{Prefix: "/" + namespace, KeysOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close()
+	keys := make([]archivedKey, 0)
+	for result := range results.Next() {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		_, nonce, evmAddr, digest, err := p2p.ParseKey(result.Key)
+		if err != nil {
+			a.logger.Error("skipping invalid archived confirm key", "key", result.Key, "err", err.Error())
+			continue
+		}
+		if filter.matches(nonce, evmAddr, digest) {
+			keys = append(keys, archivedKey{key: result.Key, confirmType: typ, nonce: nonce, evmAddress: evmAddr})
+		}
+	}
+	return keys, nil
+}
+
+// decodeConfirm decodes an archived confirm from its signature store key and value.
+func decodeConfirm(key string, value []byte) (Confirm, error) {
+	namespace, nonce, evmAddr, digest, err := p2p.ParseKey(key)
+	if err != nil {
+		return Confirm{}, fmt.Errorf("invalid archived confirm key %s: %w", key, err)
+	}
+	switch namespace {
+	case p2p.ValsetConfirmNamespace:
+		vsConfirm, err := types.UnmarshalValsetConfirm(value)
+		if err != nil {
+			return Confirm{}, fmt.Errorf("invalid archived valset confirm %s: %w", key, err)
+		}
+		return Confirm{
+			Type:       ConfirmTypeValset,
+			Nonce:      nonce,
+			EVMAddress: evmAddr,
+			Digest:     digest,
+			Signature:  vsConfirm.Signature,
+		}, nil
+	case p2p.DataCommitmentConfirmNamespace:
+		dcConfirm, err := types.UnmarshalDataCommitmentConfirm(value)
+		if err != nil {
+			return Confirm{}, fmt.Errorf("invalid archived data commitment confirm %s: %w", key, err)
+		}
+		return Confirm{
+			Type:       ConfirmTypeDataCommitment,
+			Nonce:      nonce,
+			EVMAddress: evmAddr,
+			Digest:     digest,
+			Signature:  dcConfirm.Signature,
+		}, nil
+	default:
+		return Confirm{}, fmt.Errorf("%w: %s", p2p.ErrInvalidConfirmNamespace, namespace)
+	}
+}
@@ -0,0 +1,219 @@
+package archive_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/archive"
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	evmAddress1 = "0x966e6f22781EF6a6A82BBB4DB3df8E225DfD9488"
+	evmAddress2 = "0x91DEd26b5f38B065FC0204c7929Da1b2A21877Ad"
+	digest1     = "0x1111111111111111111111111111111111111111111111111111111111111111"
+	digest2     = "0x2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+// newSignatureStore creates a signature store containing the confirms as archived by the relayer.
+func newSignatureStore(t *testing.T) ds.Batching {
+	ctx := context.Background()
+	store := ds.NewMapDatastore()
+	putValsetConfirm := func(nonce uint64, evmAddr string, signBytes string) {
+		value, err := types.MarshalValsetConfirm(types.ValsetConfirm{EthAddress: evmAddr, Signature: "0xvs"})
+		require.NoError(t, err)
+		require.NoError(t, store.Put(ctx, ds.NewKey(p2p.GetValsetConfirmKey(nonce, evmAddr, signBytes)), value))
+	}
+	putDataCommitmentConfirm := func(nonce uint64, evmAddr string, dataRootTupleRoot string) {
+		value, err := types.MarshalDataCommitmentConfirm(types.DataCommitmentConfirm{EthAddress: evmAddr, Signature: "0xdc"})
+		require.NoError(t, err)
+		require.NoError(t, store.Put(ctx, ds.NewKey(p2p.GetDataCommitmentConfirmKey(nonce, evmAddr, dataRootTupleRoot)), value))
+	}
+	putValsetConfirm(1, evmAddress1, digest1)
+	putValsetConfirm(1, evmAddress2, digest1)
+	putDataCommitmentConfirm(2, evmAddress1, digest2)
+	putDataCommitmentConfirm(20, evmAddress2, digest2)
+	return store
+}
+
+func TestArchiveList(t *testing.T) {
+	a := archive.New(newSignatureStore(t))
+
+	tests := []struct {
+		name     string
+		filter   archive.Filter
+		expected []archive.Confirm
+		err      error
+	}{
+		{
+			name:   "all",
+			filter: archive.Filter{},
+			expected: []archive.Confirm{
+				{Type: archive.ConfirmTypeValset, Nonce: 1, EVMAddress: evmAddress2, Digest: digest1, Signature: "0xvs"},
+				{Type: archive.ConfirmTypeValset, Nonce: 1, EVMAddress: evmAddress1, Digest: digest1, Signature: "0xvs"},
+				{Type: archive.ConfirmTypeDataCommitment, Nonce: 2, EVMAddress: evmAddress1, Digest: digest2, Signature: "0xdc"},
+				{Type: archive.ConfirmTypeDataCommitment, Nonce: 20, EVMAddress: evmAddress2, Digest: digest2, Signature: "0xdc"},
+			},
+		},
+		{
+			name:   "nonce range",
+			filter: archive.Filter{FromNonce: 2, ToNonce: 19},
+			expected: []archive.Confirm{
+				{Type: archive.ConfirmTypeDataCommitment, Nonce: 2, EVMAddress: evmAddress1, Digest: digest2, Signature: "0xdc"},
+			},
+		},
+		{
+			name:   "evm address, case insensitive",
+			filter: archive.Filter{EVMAddress: "0x91ded26b5f38b065fc0204c7929da1b2a21877ad"},
+			expected: []archive.Confirm{
+				{Type: archive.ConfirmTypeValset, Nonce: 1, EVMAddress: evmAddress2, Digest: digest1, Signature: "0xvs"},
+				{Type: archive.ConfirmTypeDataCommitment, Nonce: 20, EVMAddress: evmAddress2, Digest: digest2, Signature: "0xdc"},
+			},
+		},
+		{
+			name:   "digest and limit",
+			filter: archive.Filter{Digest: digest1, Limit: 1},
+			expected: []archive.Confirm{
+				{Type: archive.ConfirmTypeValset, Nonce: 1, EVMAddress: evmAddress2, Digest: digest1, Signature: "0xvs"},
+			},
+		},
+		{
+			name:     "type",
+			filter:   archive.Filter{Type: archive.ConfirmTypeDataCommitment, FromNonce: 10},
+			expected: []archive.Confirm{{Type: archive.ConfirmTypeDataCommitment, Nonce: 20, EVMAddress: evmAddress2, Digest: digest2, Signature: "0xdc"}},
+		},
+		{
+			name:   "invalid range",
+			filter: archive.Filter{FromNonce: 10, ToNonce: 2},
+			err:    archive.ErrInvalidNonceRange,
+		},
+		{
+			name:   "invalid evm address",
+			filter: archive.Filter{EVMAddress: "0x123"},
+			err:    archive.ErrInvalidEVMAddress,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			confirms, err := a.List(context.Background(), tt.filter)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, confirms)
+		})
+	}
+}
+
+func TestArchiveListPage(t *testing.T) {
+	ctx := context.Background()
+	store := newSignatureStore(t)
+	value, err := types.MarshalDataCommitmentConfirm(types.DataCommitmentConfirm{EthAddress: evmAddress1, Signature: "0xdc"})
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, ds.NewKey(p2p.GetDataCommitmentConfirmKey(20, evmAddress1, digest2)), value))
+	a := archive.New(store)
+	all, err := a.List(ctx, archive.Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+
+	// the page doesn't end in the middle of a nonce
+	page, err := a.ListPage(ctx, archive.Filter{FromNonce: 2, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, archive.ListResponse{Confirms: all[2:3], NextNonce: 20}, page)
+	// unless the page only contains this nonce
+	page, err = a.ListPage(ctx, archive.Filter{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, archive.ListResponse{Confirms: all[:2], NextNonce: 2}, page)
+
+	// listing the pages from the next nonce returns all the confirms
+	for limit := 1; limit <= 5; limit++ {
+		listed := make([]archive.Confirm, 0)
+		filter := archive.Filter{Limit: limit}
+		for {
+			page, err := a.ListPage(ctx, filter)
+			require.NoError(t, err)
+			listed = append(listed, page.Confirms...)
+			if page.NextNonce == 0 {
+				break
+			}
+			filter.FromNonce = page.NextNonce
+		}
+		assert.Equal(t, all, listed, limit)
+	}
+}
+
+func TestParseFilterLimit(t *testing.T) {
+	filter, err := archive.ParseFilter(url.Values{})
+	require.NoError(t, err)
+	assert.Equal(t, archive.DefaultListLimit, filter.Limit)
+	filter, err = archive.ParseFilter(url.Values{archive.ParamLimit: {"0"}})
+	require.NoError(t, err)
+	assert.Equal(t, archive.DefaultListLimit, filter.Limit)
+	filter, err = archive.ParseFilter(url.Values{archive.ParamLimit: {strconv.Itoa(archive.MaxListLimit)}})
+	require.NoError(t, err)
+	assert.Equal(t, archive.MaxListLimit, filter.Limit)
+	_, err = archive.ParseFilter(url.Values{archive.ParamLimit: {strconv.Itoa(archive.MaxListLimit + 1)}})
+	assert.ErrorIs(t, err, archive.ErrLimitTooHigh)
+}
+
+func TestServerAndClient(t *testing.T) {
+	server := archive.NewServer(archive.New(newSignatureStore(t)), tmlog.NewNopLogger())
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	client := archive.NewClient(httpServer.URL)
+	confirms, err := client.List(context.Background(), archive.Filter{FromNonce: 2, EVMAddress: evmAddress2})
+	require.NoError(t, err)
+	assert.Equal(t, []archive.Confirm{
+		{Type: archive.ConfirmTypeDataCommitment, Nonce: 20, EVMAddress: evmAddress2, Digest: digest2, Signature: "0xdc"},
+	}, confirms)
+
+	// the next page nonce is returned
+	page, err := client.ListPage(context.Background(), archive.Filter{Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, page.Confirms, 2)
+	assert.Equal(t, uint64(2), page.NextNonce)
+
+	// invalid parameters are rejected
+	resp, err := http.Get(httpServer.URL + archive.ConfirmsPath + "?from_nonce=abc")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// the api is read-only
+	resp, err = http.Post(httpServer.URL+archive.ConfirmsPath, "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestArchiveListSkipsInvalidConfirms(t *testing.T) {
+	ctx := context.Background()
+	store := newSignatureStore(t)
+	// a confirm whose value can't be decoded, and a key that is not a confirm key
+	require.NoError(t, store.Put(ctx, ds.NewKey(p2p.GetValsetConfirmKey(3, evmAddress1, digest1)), []byte("invalid")))
+	require.NoError(t, store.Put(ctx, ds.NewKey("/"+p2p.DataCommitmentConfirmNamespace+"/invalid"), []byte("invalid")))
+	a := archive.New(store).WithLogger(tmlog.NewNopLogger())
+
+	confirms, err := a.List(ctx, archive.Filter{FromNonce: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []archive.Confirm{
+		{Type: archive.ConfirmTypeDataCommitment, Nonce: 2, EVMAddress: evmAddress1, Digest: digest2, Signature: "0xdc"},
+		{Type: archive.ConfirmTypeDataCommitment, Nonce: 20, EVMAddress: evmAddress2, Digest: digest2, Signature: "0xdc"},
+	}, confirms)
+
+	// the skipped confirms don't count towards the limit
+	confirms, err = a.List(ctx, archive.Filter{FromNonce: 2, Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, confirms, 2)
+}
@@ -0,0 +1,70 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Client queries a remote archive API.
+type Client struct {
+	// URL the archive API base URL, e.g. http://localhost:8080.
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new archive API client.
+func NewClient(apiURL string) *Client {
+	return &Client{
+		URL:        strings.TrimSuffix(apiURL, "/"),
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// List returns the first page of the archived confirms selected by the filter.
+func (c *Client) List(ctx context.Context, filter Filter) ([]Confirm, error) {
+	page, err := c.ListPage(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return page.Confirms, nil
+}
+
+// ListPage returns a page of the archived confirms selected by the filter, along with the nonce
+// starting the next page. The API limits the page size if the filter has no limit.
+func (c *Client) ListPage(ctx context.Context, filter Filter) (ListResponse, error) {
+	err := filter.Validate()
+	if err != nil {
+		return ListResponse{}, err
+	}
+	url := c.URL + ConfirmsPath
+	if values := filter.Values(); len(values) != 0 {
+		url += "?" + values.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return ListResponse{}, err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return ListResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp ErrorResponse
+		err = json.NewDecoder(resp.Body).Decode(&errResp)
+		if err != nil {
+			return ListResponse{}, fmt.Errorf("%w: status %s", ErrAPIError, resp.Status)
+		}
+		return ListResponse{}, fmt.Errorf("%w: %s", ErrAPIError, errResp.Error)
+	}
+	var listResp ListResponse
+	err = json.NewDecoder(resp.Body).Decode(&listResp)
+	if err != nil {
+		return ListResponse{}, err
+	}
+	return listResp, nil
+}
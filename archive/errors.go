@@ -0,0 +1,11 @@
+package archive
+
+import "errors"
+
+var (
+	ErrInvalidNonceRange = errors.New("invalid nonce range")
+	ErrInvalidEVMAddress = errors.New("invalid evm address")
+	ErrUnknownType       = errors.New("unknown confirm type")
+	ErrAPIError          = errors.New("archive api error")
+	ErrLimitTooHigh      = errors.New("limit too high")
+)
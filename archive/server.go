@@ -0,0 +1,180 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	// ConfirmsPath the API path listing the archived confirms.
+	ConfirmsPath = "/confirms"
+	// DefaultListLimit the number of confirms listed by the API when no limit is requested.
+	DefaultListLimit = 100
+	// MaxListLimit the maximum number of confirms that can be listed by the API in a single request.
+	MaxListLimit = 1000
+	// serverShutdownTimeout the time given to the in-flight requests to complete when stopping the server.
+	serverShutdownTimeout = 5 * time.Second
+)
+
+// The query parameters of the confirms listing.
+const (
+	ParamFromNonce  = "from_nonce"
+	ParamToNonce    = "to_nonce"
+	ParamEVMAddress = "evm_address"
+	ParamDigest     = "digest"
+	ParamType       = "type"
+	ParamLimit      = "limit"
+)
+
+// ListResponse the confirms listing response.
+type ListResponse struct {
+	Confirms []Confirm `json:"confirms"`
+	// NextNonce the from nonce listing the next page of confirms. Zero if it's the last page.
+	NextNonce uint64 `json:"next_nonce,omitempty"`
+}
+
+// ErrorResponse the response of the failed requests.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server a read-only HTTP/JSON API over the archive.
+type Server struct {
+	archive *Archive
+	logger  tmlog.Logger
+}
+
+// NewServer creates a new archive API server.
+func NewServer(archive *Archive, logger tmlog.Logger) *Server {
+	return &Server{archive: archive, logger: logger}
+}
+
+// Handler returns the API handler.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ConfirmsPath, s.handleConfirms)
+	return mux
+}
+
+// Serve serves the API on the provided address until the context is done.
+// This is a non-blocking call. Returns an error if the address can't be listened on.
+func (s *Server) Serve(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		err := server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("archive api server stopped", "err", err.Error())
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+		defer cancel()
+		err := server.Shutdown(shutdownCtx)
+		if err != nil {
+			s.logger.Error("couldn't stop the archive api server", "err", err.Error())
+		}
+	}()
+	s.logger.Info("serving the signature archive api", "addr", listener.Addr().String())
+	return nil
+}
+
+func (s *Server) handleConfirms(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "only GET is supported"})
+		return
+	}
+	filter, err := ParseFilter(r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	page, err := s.archive.ListPage(r.Context(), filter)
+	if err != nil {
+		s.logger.Error("couldn't list the archived confirms", "err", err.Error())
+		writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// ParseFilter parses the filter from the confirms listing query parameters.
+// The limit defaults to DefaultListLimit, and can't exceed MaxListLimit.
+func ParseFilter(values url.Values) (Filter, error) {
+	filter := Filter{Limit: DefaultListLimit}
+	var err error
+	if v := values.Get(ParamFromNonce); v != "" {
+		filter.FromNonce, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid %s: %w", ParamFromNonce, err)
+		}
+	}
+	if v := values.Get(ParamToNonce); v != "" {
+		filter.ToNonce, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid %s: %w", ParamToNonce, err)
+		}
+	}
+	if v := values.Get(ParamLimit); v != "" {
+		filter.Limit, err = strconv.Atoi(v)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid %s: %w", ParamLimit, err)
+		}
+		if filter.Limit == 0 {
+			filter.Limit = DefaultListLimit
+		}
+		if filter.Limit > MaxListLimit {
+			return Filter{}, fmt.Errorf("%w: %d, max %d", ErrLimitTooHigh, filter.Limit, MaxListLimit)
+		}
+	}
+	filter.EVMAddress = values.Get(ParamEVMAddress)
+	filter.Digest = values.Get(ParamDigest)
+	filter.Type = values.Get(ParamType)
+	return filter, filter.Validate()
+}
+
+// Values returns the confirms listing query parameters corresponding to the filter.
+func (f Filter) Values() url.Values {
+	values := url.Values{}
+	if f.FromNonce != 0 {
+		values.Set(ParamFromNonce, strconv.FormatUint(f.FromNonce, 10))
+	}
+	if f.ToNonce != 0 {
+		values.Set(ParamToNonce, strconv.FormatUint(f.ToNonce, 10))
+	}
+	if f.EVMAddress != "" {
+		values.Set(ParamEVMAddress, f.EVMAddress)
+	}
+	if f.Digest != "" {
+		values.Set(ParamDigest, f.Digest)
+	}
+	if f.Type != "" {
+		values.Set(ParamType, f.Type)
+	}
+	if f.Limit != 0 {
+		values.Set(ParamLimit, strconv.Itoa(f.Limit))
+	}
+	return values
+}
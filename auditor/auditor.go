@@ -0,0 +1,292 @@
+package auditor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	celestiatypes "github.com/celestiaorg/celestia-app/x/qgb/types"
+	"github.com/celestiaorg/orchestrator-relayer/evm"
+	"github.com/celestiaorg/orchestrator-relayer/rpc"
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	wrapper "github.com/celestiaorg/quantum-gravity-bridge/wrappers/QuantumGravityBridge.sol"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	MismatchMissingEvent      = "missing_event"
+	MismatchAttestationType   = "attestation_type"
+	MismatchDataRootTupleRoot = "data_root_tuple_root"
+	MismatchValsetHash        = "valset_hash"
+	MismatchPowerThreshold    = "power_threshold"
+	MismatchQueryFailed       = "query_failed"
+)
+
+const (
+	AttestationTypeValset         = "valset"
+	AttestationTypeDataCommitment = "data_commitment"
+)
+
+// Mismatch describes a difference between an attestation committed to the QGB contract
+// and the one that is registered in Celestia state.
+type Mismatch struct {
+	Nonce       uint64 `json:"nonce"`
+	Type        string `json:"type"`
+	Reason      string `json:"reason"`
+	Expected    string `json:"expected,omitempty"`
+	Actual      string `json:"actual,omitempty"`
+	EVMTxHash   string `json:"evm_tx_hash,omitempty"`
+	EVMBlock    uint64 `json:"evm_block,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Report the result of auditing a range of nonces.
+type Report struct {
+	FromNonce  uint64     `json:"from_nonce"`
+	ToNonce    uint64     `json:"to_nonce"`
+	Checked    uint64     `json:"checked"`
+	Mismatches []Mismatch `json:"mismatches"`
+}
+
+// HasMismatches returns true if the audit found any mismatch.
+func (r Report) HasMismatches() bool {
+	return len(r.Mismatches) != 0
+}
+
+// Auditor walks the events emitted by the QGB contract and reconciles them
+// with the attestations in Celestia state.
+type Auditor struct {
+	TmQuerier  *rpc.TmQuerier
+	AppQuerier *rpc.AppQuerier
+	EVMClient  *evm.Client
+	// EVMBackend used to get the latest EVM block, up to which the contract events are filtered.
+	EVMBackend bind.ContractBackend
+	logger     tmlog.Logger
+}
+
+func NewAuditor(
+	tmQuerier *rpc.TmQuerier,
+	appQuerier *rpc.AppQuerier,
+	evmClient *evm.Client,
+	evmBackend bind.ContractBackend,
+	logger tmlog.Logger,
+) *Auditor {
+	return &Auditor{
+		TmQuerier:  tmQuerier,
+		AppQuerier: appQuerier,
+		EVMClient:  evmClient,
+		EVMBackend: evmBackend,
+		logger:     logger,
+	}
+}
+
+// Audit reconciles the attestations in the [fromNonce, toNonce] range with the events
+// emitted by the QGB contract starting from the startBlock EVM height. The events are filtered by windows
+// of evm.DefaultEventsFilterWindow blocks, up to the latest EVM block.
+// If fromNonce is 0, the audit will start from the lowest nonce found in the contract events, i.e.
+// the contract initial nonce when the start block is lower than the deployment block.
+// If toNonce is 0, the audit will go up to the latest nonce relayed to the contract.
+func (a *Auditor) Audit(ctx context.Context, fromNonce uint64, toNonce uint64, startBlock uint64) (Report, error) {
+	lastContractNonce, err := a.EVMClient.StateLastEventNonce(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return Report{}, err
+	}
+	if toNonce == 0 || toNonce > lastContractNonce {
+		toNonce = lastContractNonce
+	}
+
+	endBlock, err := evm.LatestBlockNumber(ctx, a.EVMBackend)
+	if err != nil {
+		return Report{}, err
+	}
+	dcEvents, err := a.EVMClient.FilterDataRootTupleRootEventsInRange(ctx, startBlock, endBlock, nil)
+	if err != nil {
+		return Report{}, err
+	}
+	vsEvents, err := a.EVMClient.FilterValidatorSetUpdatedEventsInRange(ctx, startBlock, endBlock, nil)
+	if err != nil {
+		return Report{}, err
+	}
+
+	if fromNonce == 0 {
+		fromNonce = lowestEventNonce(dcEvents, vsEvents)
+	}
+	if fromNonce == 0 || fromNonce > toNonce {
+		return Report{}, fmt.Errorf("%w: from %d to %d", ErrInvalidNonceRange, fromNonce, toNonce)
+	}
+
+	a.logger.Info("auditing the QGB contract", "from_nonce", fromNonce, "to_nonce", toNonce)
+
+	report := Report{
+		FromNonce:  fromNonce,
+		ToNonce:    toNonce,
+		Mismatches: make([]Mismatch, 0),
+	}
+	seen := make(map[uint64]bool)
+
+	for _, event := range dcEvents {
+		nonce := event.Nonce.Uint64()
+		if nonce < fromNonce || nonce > toNonce {
+			continue
+		}
+		seen[nonce] = true
+		report.Checked++
+		mismatch := a.auditDataRootTupleRoot(ctx, nonce, event.DataRootTupleRoot)
+		if mismatch != nil {
+			mismatch.EVMTxHash = event.Raw.TxHash.Hex()
+			mismatch.EVMBlock = event.Raw.BlockNumber
+			report.Mismatches = append(report.Mismatches, *mismatch)
+		}
+	}
+
+	for _, event := range vsEvents {
+		nonce := event.Nonce.Uint64()
+		if nonce < fromNonce || nonce > toNonce {
+			continue
+		}
+		seen[nonce] = true
+		report.Checked++
+		mismatch := a.auditValset(ctx, nonce, event.ValidatorSetHash, event.PowerThreshold)
+		if mismatch != nil {
+			mismatch.EVMTxHash = event.Raw.TxHash.Hex()
+			mismatch.EVMBlock = event.Raw.BlockNumber
+			report.Mismatches = append(report.Mismatches, *mismatch)
+		}
+	}
+
+	// the contract nonces are consecutive, so any nonce without an event in the audited range
+	// means that the events were emitted before the start block or that the contract is broken.
+	for nonce := fromNonce; nonce <= toNonce; nonce++ {
+		if !seen[nonce] {
+			report.Mismatches = append(report.Mismatches, Mismatch{
+				Nonce:       nonce,
+				Reason:      MismatchMissingEvent,
+				Description: "no event found for nonce in the provided EVM blocks range",
+			})
+		}
+	}
+
+	sort.SliceStable(report.Mismatches, func(i, j int) bool {
+		return report.Mismatches[i].Nonce < report.Mismatches[j].Nonce
+	})
+
+	return report, nil
+}
+
+// auditDataRootTupleRoot checks if the data root tuple root committed to the contract
+// matches the one recomputed from the Celestia data commitment having the same nonce.
+func (a *Auditor) auditDataRootTupleRoot(ctx context.Context, nonce uint64, tupleRoot [32]byte) *Mismatch {
+	dc, err := a.AppQuerier.QueryDataCommitmentByNonce(ctx, nonce)
+	if errors.Is(err, types.ErrAttestationNotDataCommitmentRequest) {
+		return &Mismatch{
+			Nonce:    nonce,
+			Type:     AttestationTypeDataCommitment,
+			Reason:   MismatchAttestationType,
+			Expected: AttestationTypeValset,
+			Actual:   AttestationTypeDataCommitment,
+		}
+	}
+	if err != nil {
+		return queryFailed(nonce, AttestationTypeDataCommitment, err)
+	}
+	commitment, err := a.TmQuerier.QueryCommitment(ctx, dc.BeginBlock, dc.EndBlock)
+	if err != nil {
+		return queryFailed(nonce, AttestationTypeDataCommitment, err)
+	}
+	if !bytes.Equal(commitment, tupleRoot[:]) {
+		return &Mismatch{
+			Nonce:    nonce,
+			Type:     AttestationTypeDataCommitment,
+			Reason:   MismatchDataRootTupleRoot,
+			Expected: ethcmn.BytesToHash(commitment).Hex(),
+			Actual:   ethcmn.BytesToHash(tupleRoot[:]).Hex(),
+		}
+	}
+	return nil
+}
+
+// auditValset checks if the validator set hash and power threshold committed to the contract
+// match the ones computed from the Celestia valset having the same nonce.
+func (a *Auditor) auditValset(ctx context.Context, nonce uint64, vsHash [32]byte, powerThreshold *big.Int) *Mismatch {
+	att, err := a.AppQuerier.QueryAttestationByNonce(ctx, nonce)
+	if err != nil {
+		return queryFailed(nonce, AttestationTypeValset, err)
+	}
+	if att == nil {
+		return queryFailed(nonce, AttestationTypeValset, ErrAttestationNotFound)
+	}
+	if _, ok := att.(*celestiatypes.Valset); !ok {
+		return &Mismatch{
+			Nonce:    nonce,
+			Type:     AttestationTypeValset,
+			Reason:   MismatchAttestationType,
+			Expected: AttestationTypeDataCommitment,
+			Actual:   AttestationTypeValset,
+		}
+	}
+	vs, err := a.AppQuerier.QueryValsetByNonce(ctx, nonce)
+	if err != nil {
+		return queryFailed(nonce, AttestationTypeValset, err)
+	}
+	expectedHash, err := vs.Hash()
+	if err != nil {
+		return queryFailed(nonce, AttestationTypeValset, err)
+	}
+	if expectedHash != ethcmn.Hash(vsHash) {
+		return &Mismatch{
+			Nonce:    nonce,
+			Type:     AttestationTypeValset,
+			Reason:   MismatchValsetHash,
+			Expected: expectedHash.Hex(),
+			Actual:   ethcmn.Hash(vsHash).Hex(),
+		}
+	}
+	expectedThreshold := vs.TwoThirdsThreshold()
+	if powerThreshold == nil || powerThreshold.Cmp(new(big.Int).SetUint64(expectedThreshold)) != 0 {
+		actual := ""
+		if powerThreshold != nil {
+			actual = powerThreshold.String()
+		}
+		return &Mismatch{
+			Nonce:    nonce,
+			Type:     AttestationTypeValset,
+			Reason:   MismatchPowerThreshold,
+			Expected: fmt.Sprintf("%d", expectedThreshold),
+			Actual:   actual,
+		}
+	}
+	return nil
+}
+
+// lowestEventNonce returns the lowest nonce in the provided events. Returns 0 if no event is provided.
+func lowestEventNonce(
+	dcEvents []wrapper.QuantumGravityBridgeDataRootTupleRootEvent,
+	vsEvents []wrapper.QuantumGravityBridgeValidatorSetUpdatedEvent,
+) uint64 {
+	lowest := uint64(0)
+	for _, event := range dcEvents {
+		if lowest == 0 || event.Nonce.Uint64() < lowest {
+			lowest = event.Nonce.Uint64()
+		}
+	}
+	for _, event := range vsEvents {
+		if lowest == 0 || event.Nonce.Uint64() < lowest {
+			lowest = event.Nonce.Uint64()
+		}
+	}
+	return lowest
+}
+
+func queryFailed(nonce uint64, attType string, err error) *Mismatch {
+	return &Mismatch{
+		Nonce:       nonce,
+		Type:        attType,
+		Reason:      MismatchQueryFailed,
+		Description: err.Error(),
+	}
+}
@@ -0,0 +1,128 @@
+package auditor_test
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/celestiaorg/orchestrator-relayer/auditor"
+	"github.com/celestiaorg/orchestrator-relayer/evm"
+	qgbtesting "github.com/celestiaorg/orchestrator-relayer/testing"
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	wrapper "github.com/celestiaorg/quantum-gravity-bridge/wrappers/QuantumGravityBridge.sol"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *AuditorTestSuite) TestAudit() {
+	t := s.T()
+	ctx := context.Background()
+
+	initVs, err := s.Relayer.AppQuerier.QueryLatestValset(ctx)
+	require.NoError(t, err)
+
+	// deploying a contract that is consistent with the Celestia state
+	_, tx, _, err := s.Relayer.EVMClient.DeployQGBContract(s.Node.EVMChain.Auth, s.Node.EVMChain.Backend, *initVs, initVs.Nonce, true)
+	require.NoError(t, err)
+	_, err = s.Relayer.EVMClient.WaitForTransaction(ctx, s.Node.EVMChain.Backend, tx)
+	require.NoError(t, err)
+
+	report, err := s.Auditor.Audit(ctx, 0, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, initVs.Nonce, report.ToNonce)
+	assert.Equal(t, uint64(1), report.Checked)
+	assert.False(t, report.HasMismatches())
+
+	// deploying a contract with a validator set that differs from the Celestia one
+	tamperedVs := *initVs
+	tamperedVs.Members = append(tamperedVs.Members[:0:0], tamperedVs.Members...)
+	tamperedVs.Members[0].Power++
+	_, tx, _, err = s.Relayer.EVMClient.DeployQGBContract(s.Node.EVMChain.Auth, s.Node.EVMChain.Backend, tamperedVs, initVs.Nonce, true)
+	require.NoError(t, err)
+	_, err = s.Relayer.EVMClient.WaitForTransaction(ctx, s.Node.EVMChain.Backend, tx)
+	require.NoError(t, err)
+	receipt, err := s.Node.EVMChain.Backend.TransactionReceipt(ctx, tx.Hash())
+	require.NoError(t, err)
+
+	report, err = s.Auditor.Audit(ctx, 0, 0, receipt.BlockNumber.Uint64())
+	require.NoError(t, err)
+	require.Len(t, report.Mismatches, 1)
+	assert.Equal(t, initVs.Nonce, report.Mismatches[0].Nonce)
+	assert.Equal(t, auditor.AttestationTypeValset, report.Mismatches[0].Type)
+	assert.Equal(t, auditor.MismatchValsetHash, report.Mismatches[0].Reason)
+	assert.Equal(t, tx.Hash().Hex(), report.Mismatches[0].EVMTxHash)
+}
+
+func (s *AuditorTestSuite) TestAuditInvalidRange() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	initVs, err := s.Relayer.AppQuerier.QueryLatestValset(ctx)
+	require.NoError(t, err)
+	_, tx, _, err := s.Relayer.EVMClient.DeployQGBContract(s.Node.EVMChain.Auth, s.Node.EVMChain.Backend, *initVs, initVs.Nonce, true)
+	require.NoError(t, err)
+	_, err = s.Relayer.EVMClient.WaitForTransaction(ctx, s.Node.EVMChain.Backend, tx)
+	require.NoError(t, err)
+
+	_, err = s.Auditor.Audit(ctx, initVs.Nonce+1, 0, 0)
+	assert.ErrorIs(t, err, auditor.ErrInvalidNonceRange)
+}
+
+func (s *AuditorTestSuite) TestAuditDataRootTupleRootMismatch() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	_, err := s.Node.CelestiaNetwork.WaitForHeightWithTimeout(120, time.Minute)
+	require.NoError(t, err)
+	latestDC, err := s.Relayer.AppQuerier.QueryLatestDataCommitment(ctx)
+	require.NoError(t, err)
+	vs, err := s.Relayer.AppQuerier.QueryLastValsetBeforeNonce(ctx, latestDC.Nonce)
+	require.NoError(t, err)
+	// the attestations following the last valset before a data commitment are data commitments
+	dc, err := s.Relayer.AppQuerier.QueryDataCommitmentByNonce(ctx, vs.Nonce+1)
+	require.NoError(t, err)
+
+	// deploying a contract with the valset preceding the data commitment, then committing a tuple root that
+	// differs from the Celestia one
+	_, tx, _, err := s.Relayer.EVMClient.DeployQGBContract(s.Node.EVMChain.Auth, s.Node.EVMChain.Backend, *vs, vs.Nonce, true)
+	require.NoError(t, err)
+	_, err = s.Relayer.EVMClient.WaitForTransaction(ctx, s.Node.EVMChain.Backend, tx)
+	require.NoError(t, err)
+	receipt, err := s.Node.EVMChain.Backend.TransactionReceipt(ctx, tx.Hash())
+	require.NoError(t, err)
+
+	tamperedRoot := ethcmn.HexToHash("0x12345")
+	signBytes := types.DataCommitmentTupleRootSignBytes(big.NewInt(int64(dc.Nonce)), tamperedRoot[:])
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	acc, err := ks.ImportECDSA(qgbtesting.NodeEVMPrivateKey, "123")
+	require.NoError(t, err)
+	require.NoError(t, ks.Unlock(acc, "123"))
+	signature, err := evm.NewEthereumSignature(signBytes.Bytes(), ks, acc)
+	require.NoError(t, err)
+	v, r, ss, err := evm.SigToVRS(ethcmn.Bytes2Hex(signature))
+	require.NoError(t, err)
+	tx, err = s.Relayer.EVMClient.SubmitDataRootTupleRoot(
+		s.Node.EVMChain.Auth,
+		tamperedRoot,
+		dc.Nonce,
+		*vs,
+		[]wrapper.Signature{{V: v, R: r, S: ss}},
+	)
+	require.NoError(t, err)
+	receipt, err = s.Relayer.EVMClient.WaitForTransaction(ctx, s.Node.EVMChain.Backend, tx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), receipt.Status)
+
+	report, err := s.Auditor.Audit(ctx, dc.Nonce, 0, receipt.BlockNumber.Uint64())
+	require.NoError(t, err)
+	assert.Equal(t, dc.Nonce, report.ToNonce)
+	require.Len(t, report.Mismatches, 1)
+	assert.Equal(t, dc.Nonce, report.Mismatches[0].Nonce)
+	assert.Equal(t, auditor.AttestationTypeDataCommitment, report.Mismatches[0].Type)
+	assert.Equal(t, auditor.MismatchDataRootTupleRoot, report.Mismatches[0].Reason)
+	assert.Equal(t, tx.Hash().Hex(), report.Mismatches[0].EVMTxHash)
+}
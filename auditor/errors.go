@@ -0,0 +1,9 @@
+package auditor
+
+import "errors"
+
+var (
+	ErrInvalidNonceRange   = errors.New("invalid nonce range")
+	ErrMismatchesFound     = errors.New("the contract state doesn't match the Celestia state")
+	ErrAttestationNotFound = errors.New("attestation not found")
+)
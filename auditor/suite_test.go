@@ -0,0 +1,44 @@
+package auditor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/celestiaorg/orchestrator-relayer/auditor"
+	"github.com/celestiaorg/orchestrator-relayer/relayer"
+	qgbtesting "github.com/celestiaorg/orchestrator-relayer/testing"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditorTestSuite struct {
+	suite.Suite
+	Node    *qgbtesting.TestNode
+	Relayer *relayer.Relayer
+	Auditor *auditor.Auditor
+}
+
+func (s *AuditorTestSuite) SetupSuite() {
+	t := s.T()
+	if testing.Short() {
+		t.Skip("skipping auditor tests in short mode.")
+	}
+	ctx := context.Background()
+	s.Node = qgbtesting.NewTestNode(ctx, t)
+	_, err := s.Node.CelestiaNetwork.WaitForHeight(2)
+	require.NoError(t, err)
+	s.Relayer = qgbtesting.NewRelayer(t, s.Node)
+	s.Auditor = auditor.NewAuditor(s.Relayer.TmQuerier, s.Relayer.AppQuerier, s.Relayer.EVMClient, s.Node.EVMChain.Backend, tmlog.NewNopLogger())
+	go s.Node.EVMChain.PeriodicCommit(ctx, time.Millisecond)
+}
+
+func (s *AuditorTestSuite) TearDownSuite() {
+	s.Node.Close()
+}
+
+func TestAuditor(t *testing.T) {
+	suite.Run(t, new(AuditorTestSuite))
+}
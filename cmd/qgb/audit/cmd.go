@@ -0,0 +1,131 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/celestiaorg/orchestrator-relayer/auditor"
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/common"
+	"github.com/celestiaorg/orchestrator-relayer/evm"
+	wrapper "github.com/celestiaorg/quantum-gravity-bridge/wrappers/QuantumGravityBridge.sol"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+func Command() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "audit <flags>",
+		Short: "Reconciles the attestations committed to the QGB contract with the Celestia state",
+		Long: "Reconciles the attestations committed to the QGB contract with the Celestia state. For every " +
+			"DataRootTupleRootEvent in the nonces range, the data root tuple root is recomputed from Celestia and compared " +
+			"to the committed one. For every ValidatorSetUpdatedEvent, the validator set hash and power threshold are " +
+			"compared to the Celestia valset having the same nonce. The mismatches are output as a JSON report.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			// creating the logger
+			logger, err := base.NewLogger(os.Stderr, config.log)
+			if err != nil {
+				return err
+			}
+			logger.Debug("initializing auditor")
+
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			stopFuncs := make([]func() error, 0)
+			defer func() {
+				for _, f := range stopFuncs {
+					err := f()
+					if err != nil {
+						logger.Error(err.Error())
+					}
+				}
+			}()
+
+			tmQuerier, appQuerier, stops, err := common.NewTmAndAppQuerier(logger, config.coreRPC, config.coreGRPC)
+			stopFuncs = append(stopFuncs, stops...)
+			if err != nil {
+				return err
+			}
+
+			// connecting to a QGB contract
+			ethClient, err := ethclient.Dial(config.evmRPC)
+			if err != nil {
+				return err
+			}
+			defer ethClient.Close()
+			qgbWrapper, err := wrapper.NewQuantumGravityBridge(config.contractAddr, ethClient)
+			if err != nil {
+				return err
+			}
+
+			evmClient := evm.NewClient(
+				logger,
+				qgbWrapper,
+				nil,
+				nil,
+				config.evmRPC,
+				evm.DefaultEVMGasLimit,
+			)
+
+			audit := auditor.NewAuditor(tmQuerier, appQuerier, evmClient, ethClient, logger)
+			report, err := audit.Audit(ctx, config.fromNonce, config.toNonce, config.evmStartBlock)
+			if err != nil {
+				return err
+			}
+
+			err = writeReport(logger, report, config.outputFile)
+			if err != nil {
+				return err
+			}
+
+			if report.HasMismatches() {
+				logger.Error("audit found mismatches", "count", len(report.Mismatches))
+				return auditor.ErrMismatchesFound
+			}
+			logger.Info("audit finished successfully", "checked", report.Checked)
+			return nil
+		},
+	}
+	return addFlags(command)
+}
+
+// writeReport writes the audit report as JSON to the output file, or to stdout if the output
+// file is not specified.
+func writeReport(logger tmlog.Logger, report auditor.Report, outputFile string) error {
+	if outputFile == "" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	logger.Info("writing audit report json file", "path", outputFile)
+	file, err := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) {
+		err := file.Close()
+		if err != nil {
+			logger.Error("failed to close file", "err", err.Error())
+		}
+	}(file)
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(report)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("report written to file successfully", "path", outputFile)
+	return nil
+}
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"fmt"
+
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/relayer"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+const (
+	FlagFromNonce     = "from-nonce"
+	FlagToNonce       = "to-nonce"
+	FlagEVMStartBlock = "evm.start-block"
+	FlagOutputFile    = "output-file"
+)
+
+func addFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Uint64(FlagFromNonce, 0, "Specify the first nonce to audit. Leaving it as 0 will audit starting from the lowest nonce emitted by the contract after the start block")
+	cmd.Flags().Uint64(FlagToNonce, 0, "Specify the last nonce to audit. Leaving it as 0 will audit up to the latest nonce relayed to the contract")
+	cmd.Flags().String(relayer.FlagCoreGRPCHost, "localhost", "Specify the grpc address host")
+	cmd.Flags().Uint(relayer.FlagCoreGRPCPort, 9090, "Specify the grpc address port")
+	cmd.Flags().String(relayer.FlagCoreRPCHost, "localhost", "Specify the rest rpc address host")
+	cmd.Flags().Uint(relayer.FlagCoreRPCPort, 26657, "Specify the rest rpc address port")
+	cmd.Flags().String(relayer.FlagEVMRPC, "http://localhost:8545", "Specify the ethereum rpc address")
+	cmd.Flags().String(relayer.FlagContractAddress, "", "Specify the contract at which the qgb is deployed")
+	cmd.Flags().Uint64(FlagEVMStartBlock, 0, "Specify the EVM block from which the contract events will be filtered (usually, the contract deployment block)")
+	cmd.Flags().String(FlagOutputFile, "", "Path to an output file path if the report needs to be written to a json file. Leaving it as empty will result in printing the report to stdout")
+	base.AddLogFlags(cmd)
+
+	return cmd
+}
+
+type Config struct {
+	fromNonce, toNonce uint64
+	coreGRPC, coreRPC  string
+	evmRPC             string
+	contractAddr       ethcmn.Address
+	evmStartBlock      uint64
+	outputFile         string
+	log                base.LogConfig
+}
+
+func parseFlags(cmd *cobra.Command) (Config, error) {
+	fromNonce, err := cmd.Flags().GetUint64(FlagFromNonce)
+	if err != nil {
+		return Config{}, err
+	}
+	toNonce, err := cmd.Flags().GetUint64(FlagToNonce)
+	if err != nil {
+		return Config{}, err
+	}
+	if toNonce != 0 && fromNonce > toNonce {
+		return Config{}, fmt.Errorf("the %s flag should be lower or equal to the %s flag", FlagFromNonce, FlagToNonce)
+	}
+	coreRPCHost, err := cmd.Flags().GetString(relayer.FlagCoreRPCHost)
+	if err != nil {
+		return Config{}, err
+	}
+	coreRPCPort, err := cmd.Flags().GetUint(relayer.FlagCoreRPCPort)
+	if err != nil {
+		return Config{}, err
+	}
+	coreGRPCHost, err := cmd.Flags().GetString(relayer.FlagCoreGRPCHost)
+	if err != nil {
+		return Config{}, err
+	}
+	coreGRPCPort, err := cmd.Flags().GetUint(relayer.FlagCoreGRPCPort)
+	if err != nil {
+		return Config{}, err
+	}
+	contractAddr, err := cmd.Flags().GetString(relayer.FlagContractAddress)
+	if err != nil {
+		return Config{}, err
+	}
+	if contractAddr == "" {
+		return Config{}, fmt.Errorf("contract address flag is required: %s", relayer.FlagContractAddress)
+	}
+	if !ethcmn.IsHexAddress(contractAddr) {
+		return Config{}, fmt.Errorf("valid contract address flag is required: %s", relayer.FlagContractAddress)
+	}
+	evmRPC, err := cmd.Flags().GetString(relayer.FlagEVMRPC)
+	if err != nil {
+		return Config{}, err
+	}
+	evmStartBlock, err := cmd.Flags().GetUint64(FlagEVMStartBlock)
+	if err != nil {
+		return Config{}, err
+	}
+	outputFile, err := cmd.Flags().GetString(FlagOutputFile)
+	if err != nil {
+		return Config{}, err
+	}
+
+	logConfig, err := base.ParseLogFlags(cmd)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return Config{
+		fromNonce:     fromNonce,
+		toNonce:       toNonce,
+		coreGRPC:      fmt.Sprintf("%s:%d", coreGRPCHost, coreGRPCPort),
+		coreRPC:       fmt.Sprintf("tcp://%s:%d", coreRPCHost, coreRPCPort),
+		evmRPC:        evmRPC,
+		contractAddr:  ethcmn.HexToAddress(contractAddr),
+		evmStartBlock: evmStartBlock,
+		outputFile:    outputFile,
+		log:           logConfig,
+	}, nil
+}
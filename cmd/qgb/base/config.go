@@ -4,7 +4,12 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	"github.com/celestiaorg/orchestrator-relayer/store"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
 	"github.com/spf13/cobra"
 
 	"github.com/pkg/errors"
@@ -15,13 +20,15 @@ import (
 const (
 	FlagHome          = cli.HomeFlag
 	FlagEVMPassphrase = "evm.passphrase"
+	FlagP2PPassphrase = "p2p.passphrase"
 )
 
 // Config contains the base config that all commands should have.
-// Logger related configuration will be added later.
 type Config struct {
 	Home          string
 	EVMPassphrase string
+	P2PPassphrase string
+	Log           LogConfig
 }
 
 // DefaultServicePath constructs the default qgb store path for
@@ -59,3 +66,275 @@ func AddP2PListenAddressFlag(cmd *cobra.Command) {
 func AddBootstrappersFlag(cmd *cobra.Command) {
 	cmd.Flags().String(FlagBootstrappers, "", "Comma-separated multiaddresses of p2p peers to connect to")
 }
+
+const (
+	FlagP2PAuthenticate = "p2p.authenticate"
+	FlagP2PAllowlist    = "p2p.allowlist"
+)
+
+func AddP2PAuthFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(FlagP2PAuthenticate, false, "Restrict the DHT servers to the peers that prove control of an EVM address in the current validator set, the bootstrappers and the allowlisted peers")
+	cmd.Flags().String(FlagP2PAllowlist, "", "Comma-separated peer IDs, e.g. of relayers, allowed to participate in the DHT when authentication is enabled")
+}
+
+const FlagP2PSwarmKey = "p2p.swarm-key"
+
+func AddP2PSwarmKeyFlag(cmd *cobra.Command) {
+	cmd.Flags().String(FlagP2PSwarmKey, "", "Path to the private network pre-shared key file. If not specified, the key managed using the 'keys swarm' subcommand is used if it exists. Peers without the same key can't connect")
+}
+
+const (
+	FlagP2PRelayClient     = "p2p.relay-client"
+	FlagP2PRelayService    = "p2p.relay-service"
+	FlagP2PHolePunching    = "p2p.hole-punching"
+	FlagP2PStaticRelays    = "p2p.static-relays"
+	FlagP2PAnnounceAddrs   = "p2p.announce-addrs"
+	FlagP2PNoAnnounceAddrs = "p2p.no-announce-addrs"
+	FlagP2PMaxMemory       = "p2p.max-memory"
+	FlagP2PMaxFDs          = "p2p.max-fds"
+	FlagP2PConnLowWater    = "p2p.conn-low-water"
+	FlagP2PConnHighWater   = "p2p.conn-high-water"
+	FlagP2PMDNS            = "p2p.mdns"
+)
+
+func AddP2PHostFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(FlagP2PRelayClient, true, "Allow connecting to, and being reached by, the other peers through circuit relays")
+	cmd.Flags().Bool(FlagP2PRelayService, false, "Act as a circuit relay v2 for the peers that are not publicly reachable. Should only be enabled on publicly reachable nodes")
+	cmd.Flags().Bool(FlagP2PHolePunching, true, "Upgrade the relayed connections to direct ones using hole punching (DCUtR)")
+	cmd.Flags().String(FlagP2PStaticRelays, "", "Comma-separated multiaddresses of circuit relays to use, through AutoRelay, when the node is not publicly reachable")
+	cmd.Flags().String(FlagP2PAnnounceAddrs, "", "Comma-separated multiaddresses to announce to the other peers instead of the listen addresses, e.g. the public address of a node behind a NAT")
+	cmd.Flags().String(FlagP2PNoAnnounceAddrs, "", "Comma-separated multiaddresses to never announce to the other peers")
+	cmd.Flags().Int64(FlagP2PMaxMemory, 0, "The memory, in MiB, that the P2P host is allowed to use. Should be set along with --"+FlagP2PMaxFDs+", otherwise the limits are scaled to the system resources")
+	cmd.Flags().Int(FlagP2PMaxFDs, 0, "The number of file descriptors that the P2P host is allowed to use. Should be set along with --"+FlagP2PMaxMemory+", otherwise the limits are scaled to the system resources")
+	cmd.Flags().Int(FlagP2PConnLowWater, p2p.DefaultConnMgrLowWater, "The number of connections that the connection manager prunes down to. The bootstrappers, static relays, allowlisted peers and authenticated validators are never pruned")
+	cmd.Flags().Int(FlagP2PConnHighWater, p2p.DefaultConnMgrHighWater, "The number of connections above which the connection manager starts pruning")
+	cmd.Flags().Bool(FlagP2PMDNS, false, "Discover the peers on the local network, e.g. the same LAN or docker network, using mDNS. Allows running local networks without bootstrappers")
+}
+
+// ParseP2PHostFlags parses the P2P host flags added using `AddP2PHostFlags`.
+// The swarm key is not parsed, it should be loaded separately.
+func ParseP2PHostFlags(cmd *cobra.Command) (p2p.HostConfig, error) {
+	relayClient, err := cmd.Flags().GetBool(FlagP2PRelayClient)
+	if err != nil {
+		return p2p.HostConfig{}, err
+	}
+	relayService, err := cmd.Flags().GetBool(FlagP2PRelayService)
+	if err != nil {
+		return p2p.HostConfig{}, err
+	}
+	holePunching, err := cmd.Flags().GetBool(FlagP2PHolePunching)
+	if err != nil {
+		return p2p.HostConfig{}, err
+	}
+	staticRelaysFlag, err := cmd.Flags().GetString(FlagP2PStaticRelays)
+	if err != nil {
+		return p2p.HostConfig{}, err
+	}
+	staticRelays := make([]peer.AddrInfo, 0)
+	for _, relay := range splitList(staticRelaysFlag) {
+		addrInfo, err := peer.AddrInfoFromString(relay)
+		if err != nil {
+			return p2p.HostConfig{}, errors.Wrap(err, "invalid static relay")
+		}
+		staticRelays = append(staticRelays, *addrInfo)
+	}
+	announceAddrsFlag, err := cmd.Flags().GetString(FlagP2PAnnounceAddrs)
+	if err != nil {
+		return p2p.HostConfig{}, err
+	}
+	announceAddrs, err := parseMultiaddrs(announceAddrsFlag)
+	if err != nil {
+		return p2p.HostConfig{}, err
+	}
+	noAnnounceAddrsFlag, err := cmd.Flags().GetString(FlagP2PNoAnnounceAddrs)
+	if err != nil {
+		return p2p.HostConfig{}, err
+	}
+	noAnnounceAddrs, err := parseMultiaddrs(noAnnounceAddrsFlag)
+	if err != nil {
+		return p2p.HostConfig{}, err
+	}
+	maxMemory, err := cmd.Flags().GetInt64(FlagP2PMaxMemory)
+	if err != nil {
+		return p2p.HostConfig{}, err
+	}
+	maxFDs, err := cmd.Flags().GetInt(FlagP2PMaxFDs)
+	if err != nil {
+		return p2p.HostConfig{}, err
+	}
+	connLowWater, err := cmd.Flags().GetInt(FlagP2PConnLowWater)
+	if err != nil {
+		return p2p.HostConfig{}, err
+	}
+	connHighWater, err := cmd.Flags().GetInt(FlagP2PConnHighWater)
+	if err != nil {
+		return p2p.HostConfig{}, err
+	}
+	mdns, err := cmd.Flags().GetBool(FlagP2PMDNS)
+	if err != nil {
+		return p2p.HostConfig{}, err
+	}
+	if maxMemory < 0 || maxFDs < 0 {
+		return p2p.HostConfig{}, errors.New("the P2P host resource limits cannot be negative")
+	}
+
+	return p2p.HostConfig{
+		DisableRelayClient: !relayClient,
+		EnableRelayService: relayService,
+		EnableHolePunching: holePunching && relayClient,
+		StaticRelays:       staticRelays,
+		AnnounceAddrs:      announceAddrs,
+		NoAnnounceAddrs:    noAnnounceAddrs,
+		MaxMemory:          maxMemory << 20,
+		MaxFileDescriptors: maxFDs,
+		ConnMgrLowWater:    connLowWater,
+		ConnMgrHighWater:   connHighWater,
+		EnableMDNS:         mdns,
+	}, nil
+}
+
+func parseMultiaddrs(list string) ([]multiaddr.Multiaddr, error) {
+	addrs := make([]multiaddr.Multiaddr, 0)
+	for _, addr := range splitList(list) {
+		maddr, err := multiaddr.NewMultiaddr(addr)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("invalid multiaddress %s", addr))
+		}
+		addrs = append(addrs, maddr)
+	}
+	return addrs, nil
+}
+
+// splitList splits a comma-separated list, ignoring the empty values.
+func splitList(list string) []string {
+	values := make([]string, 0)
+	for _, value := range strings.Split(list, ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+const (
+	FlagP2PProtocolVersion          = "p2p.protocol-version"
+	FlagP2PFallbackProtocolVersions = "p2p.fallback-protocol-versions"
+)
+
+func AddP2PProtocolVersionFlags(cmd *cobra.Command) {
+	cmd.Flags().String(FlagP2PProtocolVersion, p2p.DefaultProtocolVersion.Name, "The preferred DHT protocol version, used first to put and get the confirms")
+	cmd.Flags().String(FlagP2PFallbackProtocolVersions, "", "Comma-separated DHT protocol versions also spoken, by order of preference, to stay compatible with the peers not upgraded yet, e.g. 0.1.0")
+}
+
+// ParseP2PProtocolVersions parses the DHT protocol versions flags added using `AddP2PProtocolVersionFlags`.
+func ParseP2PProtocolVersions(cmd *cobra.Command) (p2p.ProtocolVersions, error) {
+	preferredName, err := cmd.Flags().GetString(FlagP2PProtocolVersion)
+	if err != nil {
+		return p2p.ProtocolVersions{}, err
+	}
+	preferred, err := p2p.ParseProtocolVersion(preferredName)
+	if err != nil {
+		return p2p.ProtocolVersions{}, err
+	}
+	fallbackNames, err := cmd.Flags().GetString(FlagP2PFallbackProtocolVersions)
+	if err != nil {
+		return p2p.ProtocolVersions{}, err
+	}
+	fallbacks := make([]p2p.ProtocolVersion, 0)
+	for _, name := range splitList(fallbackNames) {
+		fallback, err := p2p.ParseProtocolVersion(name)
+		if err != nil {
+			return p2p.ProtocolVersions{}, err
+		}
+		fallbacks = append(fallbacks, fallback)
+	}
+	return p2p.ProtocolVersions{Preferred: preferred, Fallbacks: fallbacks}, nil
+}
+
+const FlagP2PChainValidation = "p2p.chain-validation"
+
+func AddP2PChainValidationFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(FlagP2PChainValidation, false, "Reject the confirms whose signer is not part of the valset that should sign their nonce, or whose digest does not match the attestation. Requires querying Celestia for every new nonce")
+}
+
+const FlagP2PConfirmEncoding = "p2p.confirm-encoding"
+
+func AddP2PConfirmEncodingFlag(cmd *cobra.Command) {
+	cmd.Flags().String(FlagP2PConfirmEncoding, "json", "Encoding used when propagating confirms: 'json' (legacy) or 'binary' (versioned). Both encodings are always accepted. Switch to 'binary' once the whole network supports it")
+}
+
+const FlagStoreBackend = "store.backend"
+
+func AddStoreBackendFlag(cmd *cobra.Command) {
+	cmd.Flags().String(FlagStoreBackend, string(store.DefaultBackend), fmt.Sprintf("The backend used for the data and signature stores: %v. The 'memory' backend loses the data on shutdown", store.Backends))
+}
+
+// ParseStoreBackendFlag parses the store backend flag added using `AddStoreBackendFlag`.
+func ParseStoreBackendFlag(cmd *cobra.Command) (store.Backend, error) {
+	name, err := cmd.Flags().GetString(FlagStoreBackend)
+	if err != nil {
+		return "", err
+	}
+	return store.ParseBackend(name)
+}
+
+const (
+	FlagStoreGCInterval     = "store.gc-interval"
+	FlagStoreGCDiscardRatio = "store.gc-discard-ratio"
+)
+
+func AddStoreGCFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration(FlagStoreGCInterval, store.DefaultGCInterval, "The interval between two badger value log garbage collections, reclaiming the space of the deleted confirms. Zero disables the garbage collection")
+	cmd.Flags().Float64(FlagStoreGCDiscardRatio, store.DefaultGCDiscardRatio, "The fraction of a value log file that should be stale for it to be rewritten by the garbage collection")
+}
+
+// ParseStoreGCFlags parses the store garbage collection flags added using `AddStoreGCFlags`.
+func ParseStoreGCFlags(cmd *cobra.Command) (store.GCConfig, error) {
+	interval, err := cmd.Flags().GetDuration(FlagStoreGCInterval)
+	if err != nil {
+		return store.GCConfig{}, err
+	}
+	if interval < 0 {
+		return store.GCConfig{}, fmt.Errorf("the %s flag cannot be negative", FlagStoreGCInterval)
+	}
+	discardRatio, err := cmd.Flags().GetFloat64(FlagStoreGCDiscardRatio)
+	if err != nil {
+		return store.GCConfig{}, err
+	}
+	if discardRatio <= 0 || discardRatio >= 1 {
+		return store.GCConfig{}, fmt.Errorf("the %s flag should be between 0 and 1 exclusive", FlagStoreGCDiscardRatio)
+	}
+	return store.GCConfig{
+		Interval:     interval,
+		DiscardRatio: discardRatio,
+		Sleep:        store.DefaultGCSleep,
+	}, nil
+}
+
+const (
+	FlagStorePruneWindow   = "store.prune-window"
+	FlagStorePruneInterval = "store.prune-interval"
+)
+
+func AddStorePruneFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64(FlagStorePruneWindow, 0, "Prune the confirms whose nonces are older than the latest attestation nonce minus this window. Zero keeps all the confirms. The confirms inside the unbonding period are always kept")
+	cmd.Flags().Duration(FlagStorePruneInterval, p2p.DefaultPruneInterval, "The interval between two confirms pruning")
+}
+
+// ParseStorePruneFlags parses the store pruning flags added using `AddStorePruneFlags`,
+// and returns the nonce window and the pruning interval.
+func ParseStorePruneFlags(cmd *cobra.Command) (uint64, time.Duration, error) {
+	window, err := cmd.Flags().GetUint64(FlagStorePruneWindow)
+	if err != nil {
+		return 0, 0, err
+	}
+	interval, err := cmd.Flags().GetDuration(FlagStorePruneInterval)
+	if err != nil {
+		return 0, 0, err
+	}
+	if interval <= 0 {
+		return 0, 0, fmt.Errorf("the %s flag should be positive", FlagStorePruneInterval)
+	}
+	return window, interval, nil
+}
@@ -0,0 +1,206 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// ConfigFileName the name of the TOML configuration file, under the home directory.
+	ConfigFileName = "config.toml"
+	// EnvPrefix the prefix of the environment variables overriding the configuration.
+	EnvPrefix = "QGB"
+
+	// configFilePerms the configuration file permissions.
+	configFilePerms = 0o600
+	// redacted the value printed instead of the secrets.
+	redacted = "<redacted>"
+)
+
+var ErrConfigFileExists = errors.New("config file already exists")
+
+// ConfigFilePath returns the path of the configuration file under the provided home directory.
+func ConfigFilePath(home string) string {
+	return filepath.Join(home, ConfigFileName)
+}
+
+// EnvVarName returns the name of the environment variable overriding the provided flag,
+// e.g. QGB_CORE_GRPC_HOST for the `core.grpc.host` flag.
+func EnvVarName(flagName string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(flagName))
+}
+
+// LoadConfig sets the command flags that were not passed from the `QGB_*` environment variables, then from
+// the configuration file under the home directory, if it exists.
+// Thus, the flags take precedence over the environment variables, which take precedence over the
+// configuration file. It should be called before parsing the flags, and is called by the root command
+// before running any command.
+// The home directory itself can't be configured this way, and the commands without a home directory
+// flag are not configured.
+func LoadConfig(cmd *cobra.Command) error {
+	if cmd.Flags().Lookup(FlagHome) == nil {
+		return nil
+	}
+	home, err := cmd.Flags().GetString(FlagHome)
+	if err != nil {
+		return err
+	}
+	var file *toml.Tree
+	path := ConfigFilePath(home)
+	if _, err := os.Stat(path); err == nil {
+		file, err = toml.LoadFile(path)
+		if err != nil {
+			return fmt.Errorf("loading the config file %s: %w", path, err)
+		}
+	}
+
+	var loadErr error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if loadErr != nil || flag.Changed || !isConfigFlag(flag.Name) {
+			return
+		}
+		if value, ok := os.LookupEnv(EnvVarName(flag.Name)); ok {
+			err := cmd.Flags().Set(flag.Name, value)
+			if err != nil {
+				loadErr = fmt.Errorf("invalid %s environment variable: %w", EnvVarName(flag.Name), err)
+			}
+			return
+		}
+		if file == nil {
+			return
+		}
+		value := file.GetPath(strings.Split(flag.Name, "."))
+		if value == nil {
+			return
+		}
+		if _, ok := value.(*toml.Tree); ok {
+			loadErr = fmt.Errorf("invalid %s value in the config file %s: expected a value, found a table", flag.Name, path)
+			return
+		}
+		err := cmd.Flags().Set(flag.Name, fmt.Sprint(value))
+		if err != nil {
+			loadErr = fmt.Errorf("invalid %s value in the config file %s: %w", flag.Name, path, err)
+		}
+	})
+	return loadErr
+}
+
+// WriteConfigFile writes the command flags values, passed or default, to the configuration file under
+// the provided home directory. The secrets, i.e. the passphrases, are not written.
+// Returns ErrConfigFileExists if the configuration file already exists.
+func WriteConfigFile(cmd *cobra.Command, home string) error {
+	tree, err := configTree(cmd, false)
+	if err != nil {
+		return err
+	}
+	encoded, err := tree.Marshal()
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(ConfigFilePath(home), os.O_CREATE|os.O_WRONLY|os.O_EXCL, configFilePerms)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%w: %s", ErrConfigFileExists, ConfigFilePath(home))
+		}
+		return err
+	}
+	_, err = file.Write(encoded)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ConfigTOML returns the command flags values encoded in TOML, with the secrets redacted.
+func ConfigTOML(cmd *cobra.Command) (string, error) {
+	tree, err := configTree(cmd, true)
+	if err != nil {
+		return "", err
+	}
+	encoded, err := tree.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// configTree returns the command flags values as a TOML tree, documented using the flags usages.
+// The secrets are redacted if redactSecrets is true, otherwise, they're omitted.
+func configTree(cmd *cobra.Command, redactSecrets bool) (*toml.Tree, error) {
+	tree, err := toml.TreeFromMap(map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	var treeErr error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if treeErr != nil || !isConfigFlag(flag.Name) {
+			return
+		}
+		var value interface{}
+		if isSecretFlag(flag.Name) {
+			if !redactSecrets {
+				return
+			}
+			value = ""
+			if flag.Value.String() != "" {
+				value = redacted
+			}
+		} else {
+			value, treeErr = typedFlagValue(flag)
+			if treeErr != nil {
+				return
+			}
+		}
+		tree.SetPathWithComment(strings.Split(flag.Name, "."), flag.Usage, false, value)
+	})
+	return tree, treeErr
+}
+
+// typedFlagValue returns the flag value using the TOML type corresponding to the flag type.
+func typedFlagValue(flag *pflag.Flag) (interface{}, error) {
+	value := flag.Value.String()
+	switch flag.Value.Type() {
+	case "bool":
+		return strconv.ParseBool(value)
+	case "int", "int8", "int16", "int32", "int64":
+		return strconv.ParseInt(value, 10, 64)
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return strconv.ParseUint(value, 10, 64)
+	case "float32", "float64":
+		return strconv.ParseFloat(value, 64)
+	default:
+		return value, nil
+	}
+}
+
+// commandLineOnlyFlags the flags that can only be passed on the command line: the ones confirming or
+// targeting a one-shot action, e.g. exporting a key or repairing the store, so that the action isn't
+// performed unintentionally on every run, and the mnemonic, which is a secret.
+var commandLineOnlyFlags = map[string]bool{
+	FlagHome:       true,
+	"help":         true,
+	"yes":          true,
+	"output":       true,
+	"out":          true,
+	"repair":       true,
+	"evm.mnemonic": true,
+}
+
+// isConfigFlag returns true if the flag can be set using the configuration file or the environment variables.
+func isConfigFlag(name string) bool {
+	return !commandLineOnlyFlags[name]
+}
+
+// isSecretFlag returns true if the flag holds a secret, which shouldn't be written or printed.
+func isSecretFlag(name string) bool {
+	return strings.HasSuffix(name, "passphrase")
+}
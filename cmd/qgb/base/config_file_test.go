@@ -0,0 +1,105 @@
+package base_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newConfigTestCommand(home string) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String(base.FlagHome, home, "")
+	cmd.Flags().String("core.grpc.host", "localhost", "the grpc host")
+	cmd.Flags().Uint("core.grpc.port", 9090, "the grpc port")
+	cmd.Flags().Bool("p2p.mdns", false, "enable mdns")
+	cmd.Flags().Duration("store.prune-interval", time.Minute, "the prune interval")
+	base.AddEVMPassphraseFlags(cmd)
+	return cmd
+}
+
+func TestLoadConfig(t *testing.T) {
+	home := t.TempDir()
+
+	// init writes the passed flags and the defaults, but not the secrets
+	initCmd := newConfigTestCommand(home)
+	require.NoError(t, initCmd.Flags().Set("core.grpc.port", "9999"))
+	require.NoError(t, initCmd.Flags().Set("p2p.mdns", "true"))
+	require.NoError(t, initCmd.Flags().Set(base.FlagEVMPassphrase, "hunter2"))
+	require.NoError(t, base.WriteConfigFile(initCmd, home))
+	err := base.WriteConfigFile(initCmd, home)
+	assert.ErrorIs(t, err, base.ErrConfigFileExists)
+	file, err := os.ReadFile(base.ConfigFilePath(home))
+	require.NoError(t, err)
+	assert.NotContains(t, string(file), "hunter2")
+
+	// the values are loaded from the file
+	cmd := newConfigTestCommand(home)
+	require.NoError(t, base.LoadConfig(cmd))
+	port, err := cmd.Flags().GetUint("core.grpc.port")
+	require.NoError(t, err)
+	assert.Equal(t, uint(9999), port)
+	mdns, err := cmd.Flags().GetBool("p2p.mdns")
+	require.NoError(t, err)
+	assert.True(t, mdns)
+	interval, err := cmd.Flags().GetDuration("store.prune-interval")
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, interval)
+	passphrase, err := base.ParseEVMPassphrase(cmd)
+	require.NoError(t, err)
+	assert.Empty(t, passphrase)
+
+	// the environment variables take precedence over the file, and the flags over both
+	t.Setenv(base.EnvVarName("core.grpc.port"), "1")
+	t.Setenv(base.EnvVarName("core.grpc.host"), "env")
+	cmd = newConfigTestCommand(home)
+	require.NoError(t, cmd.Flags().Set("core.grpc.port", "2"))
+	require.NoError(t, base.LoadConfig(cmd))
+	port, err = cmd.Flags().GetUint("core.grpc.port")
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), port)
+	host, err := cmd.Flags().GetString("core.grpc.host")
+	require.NoError(t, err)
+	assert.Equal(t, "env", host)
+
+	// the invalid values are rejected
+	t.Setenv(base.EnvVarName("core.grpc.port"), "abc")
+	assert.Error(t, base.LoadConfig(newConfigTestCommand(home)))
+}
+
+func TestLoadConfigCommandLineOnlyFlags(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, os.WriteFile(base.ConfigFilePath(home), []byte("yes = true\nrepair = true\n"), 0o600))
+	t.Setenv(base.EnvVarName("out"), "backup.tar.gz")
+	t.Setenv(base.EnvVarName("evm.mnemonic"), "secret")
+
+	// the one-shot action flags and the mnemonic are only read from the command line
+	cmd := newConfigTestCommand(home)
+	cmd.Flags().Bool("yes", false, "")
+	cmd.Flags().Bool("repair", false, "")
+	cmd.Flags().String("out", "", "")
+	cmd.Flags().String("evm.mnemonic", "", "")
+	require.NoError(t, base.LoadConfig(cmd))
+	for _, name := range []string{"yes", "repair", "out", "evm.mnemonic"} {
+		assert.Equal(t, cmd.Flags().Lookup(name).DefValue, cmd.Flags().Lookup(name).Value.String(), name)
+	}
+}
+
+func TestConfigTOML(t *testing.T) {
+	cmd := newConfigTestCommand(t.TempDir())
+	require.NoError(t, cmd.Flags().Set(base.FlagEVMPassphrase, "hunter2"))
+	config, err := base.ConfigTOML(cmd)
+	require.NoError(t, err)
+	assert.NotContains(t, config, "hunter2")
+	assert.Contains(t, config, `passphrase = "<redacted>"`)
+	assert.Contains(t, config, "port = 9090")
+	assert.NotContains(t, config, base.FlagHome)
+}
+
+func TestEnvVarName(t *testing.T) {
+	assert.Equal(t, "QGB_P2P_LISTEN_ADDR", base.EnvVarName("p2p.listen-addr"))
+}
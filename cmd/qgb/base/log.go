@@ -0,0 +1,136 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	tmflags "github.com/tendermint/tendermint/libs/cli/flags"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	FlagLogLevel  = "log.level"
+	FlagLogFormat = "log.format"
+)
+
+const (
+	// LogFormatPlain the human-readable, `key=value`, log format.
+	LogFormatPlain = "plain"
+	// LogFormatJSON the log format where every log line is a JSON object.
+	LogFormatJSON = "json"
+
+	// DefaultLogLevel the level of the modules whose level is not specified.
+	DefaultLogLevel = "info"
+
+	// LogModuleKey the key holding the module of a log line, used to filter the logs per module.
+	LogModuleKey = "module"
+)
+
+// The modules whose log level can be set separately using `--log.level=<module>:<level>`.
+const (
+	LogModuleOrchestrator = "orchestrator"
+	LogModuleRelayer      = "relayer"
+	LogModuleP2P          = "p2p"
+	LogModuleRPC          = "rpc"
+	LogModuleEVM          = "evm"
+)
+
+var LogModules = []string{LogModuleOrchestrator, LogModuleRelayer, LogModuleP2P, LogModuleRPC, LogModuleEVM}
+
+var (
+	ErrInvalidLogLevel  = errors.New("invalid log level")
+	ErrInvalidLogFormat = errors.New("invalid log format")
+)
+
+// LogConfig the logger configuration.
+type LogConfig struct {
+	// Level the default log level, optionally followed by per module levels,
+	// e.g. `info,p2p:debug,rpc:error`.
+	Level string
+	// Format either LogFormatPlain or LogFormatJSON.
+	Format string
+}
+
+func AddLogFlags(cmd *cobra.Command) {
+	cmd.Flags().String(
+		FlagLogLevel,
+		DefaultLogLevel,
+		fmt.Sprintf(
+			"The log level: 'debug', 'info', 'error' or 'none'. Can be followed by comma-separated '<module>:<level>' pairs to set the level of specific modules, e.g. 'info,p2p:debug'. The modules are: %s",
+			strings.Join(LogModules, ", "),
+		),
+	)
+	cmd.Flags().String(FlagLogFormat, LogFormatPlain, "The log format: 'plain' or 'json'")
+}
+
+// ParseLogFlags parses and validates the log flags added using `AddLogFlags`.
+func ParseLogFlags(cmd *cobra.Command) (LogConfig, error) {
+	level, err := cmd.Flags().GetString(FlagLogLevel)
+	if err != nil {
+		return LogConfig{}, err
+	}
+	format, err := cmd.Flags().GetString(FlagLogFormat)
+	if err != nil {
+		return LogConfig{}, err
+	}
+	config := LogConfig{Level: level, Format: format}
+	_, err = NewLogger(io.Discard, config)
+	if err != nil {
+		return LogConfig{}, err
+	}
+	return config, nil
+}
+
+// NewLogger creates a logger writing to the provided writer using the provided configuration.
+// The per module levels apply to the loggers derived using `ModuleLogger`.
+func NewLogger(w io.Writer, config LogConfig) (tmlog.Logger, error) {
+	var logger tmlog.Logger
+	switch config.Format {
+	case LogFormatPlain, "":
+		logger = tmlog.NewTMLogger(tmlog.NewSyncWriter(w))
+	case LogFormatJSON:
+		logger = tmlog.NewTMJSONLogger(tmlog.NewSyncWriter(w))
+	default:
+		return nil, fmt.Errorf("%w: %q. expected '%s' or '%s'", ErrInvalidLogFormat, config.Format, LogFormatPlain, LogFormatJSON)
+	}
+
+	level := config.Level
+	if level == "" {
+		level = DefaultLogLevel
+	}
+	// the levels without a module, e.g. `info` in `info,p2p:debug`, are the default level
+	items := strings.Split(level, ",")
+	for i, item := range items {
+		module, _, ok := strings.Cut(item, ":")
+		if !ok {
+			items[i] = "*:" + item
+			continue
+		}
+		if module != "*" && !isLogModule(module) {
+			return nil, fmt.Errorf("%w: unknown module %q. expected one of: %s", ErrInvalidLogLevel, module, strings.Join(LogModules, ", "))
+		}
+	}
+	logger, err := tmflags.ParseLogLevel(strings.Join(items, ","), logger, DefaultLogLevel)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLogLevel, err.Error())
+	}
+	return logger, nil
+}
+
+// ModuleLogger returns a logger tagging its log lines with the provided module,
+// and filtering them using the module level, if any.
+func ModuleLogger(logger tmlog.Logger, module string) tmlog.Logger {
+	return logger.With(LogModuleKey, module)
+}
+
+func isLogModule(name string) bool {
+	for _, module := range LogModules {
+		if module == name {
+			return true
+		}
+	}
+	return false
+}
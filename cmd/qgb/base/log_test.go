@@ -0,0 +1,51 @@
+package base_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := base.NewLogger(&buf, base.LogConfig{Level: "info,p2p:debug,rpc:error", Format: base.LogFormatJSON})
+	require.NoError(t, err)
+
+	logger.Debug("main debug")
+	logger.Info("main info", "nonce", 10)
+	base.ModuleLogger(logger, base.LogModuleP2P).Debug("p2p debug")
+	base.ModuleLogger(logger, base.LogModuleRPC).Info("rpc info")
+	base.ModuleLogger(logger, base.LogModuleRPC).Error("rpc error")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	messages := make([]string, 0, len(lines))
+	for _, line := range lines {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		messages = append(messages, entry["_msg"].(string))
+		if entry["_msg"] == "main info" {
+			assert.Equal(t, float64(10), entry["nonce"])
+		}
+		if entry["_msg"] == "p2p debug" {
+			assert.Equal(t, base.LogModuleP2P, entry[base.LogModuleKey])
+		}
+	}
+	assert.Equal(t, []string{"main info", "p2p debug", "rpc error"}, messages)
+}
+
+func TestNewLoggerInvalidConfig(t *testing.T) {
+	_, err := base.NewLogger(&bytes.Buffer{}, base.LogConfig{Level: "info", Format: "xml"})
+	assert.ErrorIs(t, err, base.ErrInvalidLogFormat)
+	_, err = base.NewLogger(&bytes.Buffer{}, base.LogConfig{Level: "verbose"})
+	assert.ErrorIs(t, err, base.ErrInvalidLogLevel)
+	_, err = base.NewLogger(&bytes.Buffer{}, base.LogConfig{Level: "info,consensus:debug"})
+	assert.ErrorIs(t, err, base.ErrInvalidLogLevel)
+	_, err = base.NewLogger(&bytes.Buffer{}, base.LogConfig{Level: "relayer:verbose"})
+	assert.ErrorIs(t, err, base.ErrInvalidLogLevel)
+}
@@ -0,0 +1,215 @@
+package base
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	FlagEVMPassphraseSource = "evm.passphrase-source"
+	FlagP2PPassphraseSource = "p2p.passphrase-source"
+)
+
+const (
+	// PassphraseSourceFile reads the passphrase from a file: `file:<path>`.
+	PassphraseSourceFile = "file"
+	// PassphraseSourceEnv reads the passphrase from an environment variable: `env:<variable name>`.
+	PassphraseSourceEnv = "env"
+	// PassphraseSourceCmd runs a helper command, using `sh -c`, and uses its stdout as the passphrase: `cmd:<command>`.
+	PassphraseSourceCmd = "cmd"
+	// PassphraseSourceVault reads the passphrase from a HashiCorp Vault KV secret, using its HTTP API:
+	// `vault:<secret url>#<field>`, e.g. `vault:https://vault:8200/v1/secret/data/qgb#evm`.
+	// The Vault token is read from the VAULT_TOKEN environment variable.
+	PassphraseSourceVault = "vault"
+
+	// VaultTokenEnv the environment variable containing the Vault token.
+	VaultTokenEnv = "VAULT_TOKEN"
+	// VaultNamespaceEnv the environment variable containing the optional Vault namespace.
+	VaultNamespaceEnv = "VAULT_NAMESPACE"
+	// DefaultVaultField the secret field read if the vault source doesn't specify one.
+	DefaultVaultField = "passphrase"
+
+	// passphraseSourceTimeout the timeout of the helper commands and the Vault requests.
+	passphraseSourceTimeout = 30 * time.Second
+)
+
+var (
+	ErrInvalidPassphraseSource   = errors.New("invalid passphrase source")
+	ErrEmptyPassphrase           = errors.New("the passphrase source returned an empty passphrase")
+	ErrConflictingPassphraseFlag = errors.New("the passphrase and its source cannot be both specified")
+)
+
+func AddEVMPassphraseFlags(cmd *cobra.Command) {
+	cmd.Flags().String(FlagEVMPassphrase, "", "the evm account passphrase (if not specified as a flag or using --"+FlagEVMPassphraseSource+", it will be asked interactively)")
+	cmd.Flags().String(
+		FlagEVMPassphraseSource,
+		"",
+		"where to read the evm account passphrase from, instead of passing it as a flag: 'file:<path>', 'env:<variable>', 'cmd:<helper command>' or 'vault:<secret url>#<field>'",
+	)
+}
+
+// ParseEVMPassphrase returns the EVM passphrase specified using the flags added by `AddEVMPassphraseFlags`,
+// reading it from its source if needed. Returns an empty passphrase if none is specified.
+func ParseEVMPassphrase(cmd *cobra.Command) (string, error) {
+	return ParsePassphraseFlags(cmd, FlagEVMPassphrase, FlagEVMPassphraseSource)
+}
+
+func AddP2PPassphraseFlags(cmd *cobra.Command) {
+	cmd.Flags().String(FlagP2PPassphrase, "", "the p2p key passphrase (if not specified as a flag or using --"+FlagP2PPassphraseSource+", it will be asked interactively)")
+	cmd.Flags().String(
+		FlagP2PPassphraseSource,
+		"",
+		"where to read the p2p key passphrase from, instead of passing it as a flag: 'file:<path>', 'env:<variable>', 'cmd:<helper command>' or 'vault:<secret url>#<field>'",
+	)
+}
+
+// ParseP2PPassphrase returns the P2P passphrase specified using the flags added by `AddP2PPassphraseFlags`,
+// reading it from its source if needed. Returns an empty passphrase if none is specified.
+func ParseP2PPassphrase(cmd *cobra.Command) (string, error) {
+	return ParsePassphraseFlags(cmd, FlagP2PPassphrase, FlagP2PPassphraseSource)
+}
+
+// ParsePassphraseFlags returns the passphrase specified using the provided passphrase flag, or read from
+// the source specified using the provided source flag. Returns an empty passphrase if none is specified.
+func ParsePassphraseFlags(cmd *cobra.Command, passphraseFlag string, sourceFlag string) (string, error) {
+	passphrase, err := cmd.Flags().GetString(passphraseFlag)
+	if err != nil {
+		return "", err
+	}
+	source, err := cmd.Flags().GetString(sourceFlag)
+	if err != nil {
+		return "", err
+	}
+	if source == "" {
+		return passphrase, nil
+	}
+	if passphrase != "" {
+		return "", fmt.Errorf("%w: --%s and --%s", ErrConflictingPassphraseFlag, passphraseFlag, sourceFlag)
+	}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, passphraseSourceTimeout)
+	defer cancel()
+	return ReadPassphrase(ctx, source)
+}
+
+// ReadPassphrase reads the passphrase from the provided source, which is one of:
+//   - `file:<path>`: the file contents.
+//   - `env:<variable name>`: the environment variable value.
+//   - `cmd:<command>`: the stdout of the command, executed using `sh -c`.
+//   - `vault:<secret url>#<field>`: the field of a HashiCorp Vault KV secret, version 1 or 2.
+//
+// The trailing new lines are trimmed. Returns ErrEmptyPassphrase if the passphrase is empty.
+func ReadPassphrase(ctx context.Context, source string) (string, error) {
+	kind, value, ok := strings.Cut(source, ":")
+	if !ok || value == "" {
+		return "", fmt.Errorf("%w: %q. expected '<file|env|cmd|vault>:<value>'", ErrInvalidPassphraseSource, source)
+	}
+
+	var passphrase string
+	var err error
+	switch kind {
+	case PassphraseSourceFile:
+		var bz []byte
+		bz, err = os.ReadFile(value)
+		passphrase = string(bz)
+	case PassphraseSourceEnv:
+		passphrase = os.Getenv(value)
+	case PassphraseSourceCmd:
+		passphrase, err = readCmdPassphrase(ctx, value)
+	case PassphraseSourceVault:
+		passphrase, err = readVaultPassphrase(ctx, value)
+	default:
+		return "", fmt.Errorf("%w: unknown kind %q. expected 'file', 'env', 'cmd' or 'vault'", ErrInvalidPassphraseSource, kind)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading the passphrase from %s: %w", kind, err)
+	}
+
+	passphrase = strings.TrimRight(passphrase, "\r\n")
+	if passphrase == "" {
+		return "", fmt.Errorf("%w: %s", ErrEmptyPassphrase, kind)
+	}
+	return passphrase, nil
+}
+
+// readCmdPassphrase runs the helper command and returns its stdout. Its stderr is forwarded, so that
+// the helper can interact with the user if needed.
+func readCmdPassphrase(ctx context.Context, command string) (string, error) {
+	var stdout bytes.Buffer
+	helper := exec.CommandContext(ctx, "sh", "-c", command)
+	helper.Stdout = &stdout
+	helper.Stderr = os.Stderr
+	err := helper.Run()
+	if err != nil {
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// readVaultPassphrase reads the field of the Vault KV secret referenced by `<secret url>#<field>`.
+func readVaultPassphrase(ctx context.Context, reference string) (string, error) {
+	url, field, _ := strings.Cut(reference, "#")
+	if field == "" {
+		field = DefaultVaultField
+	}
+	token := os.Getenv(VaultTokenEnv)
+	if token == "" {
+		return "", fmt.Errorf("the %s environment variable is not set", VaultTokenEnv)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv(VaultNamespaceEnv); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected vault response status %s", resp.Status)
+	}
+
+	// the KV version 1 secrets are returned under `data`, and the version 2 ones under `data.data`
+	var secret struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&secret)
+	if err != nil {
+		return "", err
+	}
+	fields := secret.Data
+	if nested, ok := secret.Data["data"]; ok {
+		var data map[string]json.RawMessage
+		if json.Unmarshal(nested, &data) == nil {
+			fields = data
+		}
+	}
+	raw, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("field %q not found in the vault secret", field)
+	}
+	var value string
+	err = json.Unmarshal(raw, &value)
+	if err != nil {
+		return "", fmt.Errorf("field %q of the vault secret is not a string", field)
+	}
+	return value, nil
+}
@@ -0,0 +1,100 @@
+package base_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPassphrase(t *testing.T) {
+	ctx := context.Background()
+
+	file := filepath.Join(t.TempDir(), "passphrase")
+	require.NoError(t, os.WriteFile(file, []byte("from file\n"), 0o600))
+	passphrase, err := base.ReadPassphrase(ctx, "file:"+file)
+	require.NoError(t, err)
+	assert.Equal(t, "from file", passphrase)
+
+	t.Setenv("QGB_TEST_PASSPHRASE", "from env")
+	passphrase, err = base.ReadPassphrase(ctx, "env:QGB_TEST_PASSPHRASE")
+	require.NoError(t, err)
+	assert.Equal(t, "from env", passphrase)
+
+	_, err = base.ReadPassphrase(ctx, "env:QGB_TEST_UNSET_PASSPHRASE")
+	assert.ErrorIs(t, err, base.ErrEmptyPassphrase)
+
+	passphrase, err = base.ReadPassphrase(ctx, "cmd:echo 'from cmd'")
+	require.NoError(t, err)
+	assert.Equal(t, "from cmd", passphrase)
+
+	_, err = base.ReadPassphrase(ctx, "cmd:exit 1")
+	assert.Error(t, err)
+
+	_, err = base.ReadPassphrase(ctx, "from flag")
+	assert.ErrorIs(t, err, base.ErrInvalidPassphraseSource)
+	_, err = base.ReadPassphrase(ctx, "keyring:qgb")
+	assert.ErrorIs(t, err, base.ErrInvalidPassphraseSource)
+}
+
+func TestReadPassphraseFromVault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/qgb":
+			_, _ = w.Write([]byte(`{"data":{"data":{"evm":"from vault v2"},"metadata":{"version":1}}}`))
+		case "/v1/kv/qgb":
+			_, _ = w.Write([]byte(`{"data":{"passphrase":"from vault v1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	_, err := base.ReadPassphrase(ctx, "vault:"+server.URL+"/v1/kv/qgb")
+	assert.Error(t, err)
+
+	t.Setenv(base.VaultTokenEnv, "token")
+	passphrase, err := base.ReadPassphrase(ctx, "vault:"+server.URL+"/v1/secret/data/qgb#evm")
+	require.NoError(t, err)
+	assert.Equal(t, "from vault v2", passphrase)
+
+	passphrase, err = base.ReadPassphrase(ctx, "vault:"+server.URL+"/v1/kv/qgb")
+	require.NoError(t, err)
+	assert.Equal(t, "from vault v1", passphrase)
+
+	_, err = base.ReadPassphrase(ctx, "vault:"+server.URL+"/v1/secret/data/qgb#p2p")
+	assert.Error(t, err)
+	_, err = base.ReadPassphrase(ctx, "vault:"+server.URL+"/v1/secret/data/missing")
+	assert.Error(t, err)
+}
+
+func TestParseEVMPassphrase(t *testing.T) {
+	t.Setenv("QGB_TEST_PASSPHRASE", "from env")
+	cmd := &cobra.Command{}
+	base.AddEVMPassphraseFlags(cmd)
+
+	passphrase, err := base.ParseEVMPassphrase(cmd)
+	require.NoError(t, err)
+	assert.Empty(t, passphrase)
+
+	require.NoError(t, cmd.Flags().Set(base.FlagEVMPassphraseSource, "env:QGB_TEST_PASSPHRASE"))
+	passphrase, err = base.ParseEVMPassphrase(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, "from env", passphrase)
+
+	require.NoError(t, cmd.Flags().Set(base.FlagEVMPassphrase, "from flag"))
+	_, err = base.ParseEVMPassphrase(cmd)
+	assert.ErrorIs(t, err, base.ErrConflictingPassphraseFlag)
+}
@@ -2,11 +2,17 @@ package bootstrapper
 
 import (
 	"context"
+	"errors"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/common"
+	configcmd "github.com/celestiaorg/orchestrator-relayer/cmd/qgb/config"
 	p2pcmd "github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/p2p"
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/swarm"
+	storecmd "github.com/celestiaorg/orchestrator-relayer/cmd/qgb/store"
 	"github.com/celestiaorg/orchestrator-relayer/helpers"
 	"github.com/celestiaorg/orchestrator-relayer/p2p"
 	"github.com/celestiaorg/orchestrator-relayer/store"
@@ -14,7 +20,6 @@ import (
 	dssync "github.com/ipfs/go-datastore/sync"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/spf13/cobra"
-	tmlog "github.com/tendermint/tendermint/libs/log"
 )
 
 func Command() *cobra.Command {
@@ -29,6 +34,9 @@ func Command() *cobra.Command {
 		Start(),
 		Init(),
 		p2pcmd.Root(ServiceNameBootstrapper),
+		configcmd.Command(addStartFlags),
+		swarm.Root(ServiceNameBootstrapper),
+		storecmd.Command(ServiceNameBootstrapper),
 	)
 
 	bsCmd.SetHelpCommand(&cobra.Command{})
@@ -48,7 +56,11 @@ func Start() *cobra.Command {
 			}
 
 			// creating the logger
-			logger := tmlog.NewTMLogger(os.Stdout)
+			logger, err := base.NewLogger(os.Stdout, config.logConfig)
+			if err != nil {
+				return err
+			}
+			p2pLogger := base.ModuleLogger(logger, base.LogModuleP2P)
 			logger.Debug("starting bootstrapper node")
 
 			ctx, cancel := context.WithCancel(cmd.Context())
@@ -76,21 +88,32 @@ func Start() *cobra.Command {
 			}
 
 			// get the p2p private key or generate a new one
-			privKey, err := p2pcmd.GetP2PKeyOrGenerateNewOne(s.P2PKeyStore, config.p2pNickname)
+			privKey, err := p2pcmd.GetP2PKeyOrGenerateNewOne(s.P2PKeyStore, config.p2pNickname, config.p2pPassphrase)
+			if err != nil {
+				return err
+			}
+
+			swarmKey, err := common.LoadSwarmKey(logger, s, config.p2pSwarmKey)
+			if err != nil {
+				return err
+			}
+			hostConfig := config.p2pHostConfig
+			hostConfig.SwarmKey = swarmKey
+			hostOpts, err := hostConfig.Options()
 			if err != nil {
 				return err
 			}
 
 			// creating the host
-			h, err := p2p.CreateHost(config.p2pListenAddr, privKey)
+			h, err := p2p.CreateHost(config.p2pListenAddr, privKey, hostOpts...)
 			if err != nil {
 				return err
 			}
 			logger.Info(
 				"created host",
-				"ID",
+				"peer",
 				h.ID().String(),
-				"Addresses",
+				"addresses",
 				h.Addrs(),
 			)
 
@@ -108,13 +131,39 @@ func Start() *cobra.Command {
 					return err
 				}
 			}
+			p2p.ProtectPeers(h, p2p.BootstrapperProtectionTag, p2p.AddrInfosIDs(aIBootstrappers)...)
+			p2p.ProtectPeers(h, p2p.RelayProtectionTag, p2p.AddrInfosIDs(hostConfig.StaticRelays)...)
 
 			// creating the dht
-			dht, err := p2p.NewQgbDHT(ctx, h, dataStore, aIBootstrappers, logger)
+			dht, err := p2p.NewVersionedQgbDHT(ctx, h, dataStore, aIBootstrappers, p2pLogger, config.p2pVersions)
+			if err != nil {
+				return err
+			}
+
+			// relaying the confirms, so that they reach the peers connected to the network through the bootstrapper
+			ps, err := p2p.NewQgbPubSub(ctx, h, p2pLogger)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				err := ps.Close()
+				if err != nil {
+					logger.Error(err.Error())
+				}
+			}()
+			err = ps.Relay()
 			if err != nil {
 				return err
 			}
 
+			if hostConfig.EnableMDNS {
+				err = p2p.StartMDNSDiscovery(ctx, h, p2pLogger)
+				if err != nil {
+					return err
+				}
+				logger.Info("discovering the local network peers using mDNS")
+			}
+
 			// Listen for and trap any OS signal to graceful shutdown and exit
 			go helpers.TrapSignal(logger, cancel)
 
@@ -126,7 +175,7 @@ func Start() *cobra.Command {
 				case <-ctx.Done():
 					return nil
 				case <-ticker.C:
-					logger.Info("listening in bootstrapping mode", "peers_connected", dht.RoutingTable().Size())
+					logger.Info("listening in bootstrapping mode", "peers_connected", len(dht.ListPeers()), "protocol_versions", dht.PeerProtocolVersions())
 				}
 			}
 		},
@@ -137,14 +186,17 @@ func Start() *cobra.Command {
 func Init() *cobra.Command {
 	cmd := cobra.Command{
 		Use:   "init",
-		Short: "Initialize the QGB bootstrapper store. Passed flags have persisted effect.",
+		Short: "Initialize the QGB bootstrapper store, and write the passed flags, along with the defaults, to the config file loaded on start",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			config, err := parseInitFlags(cmd)
 			if err != nil {
 				return err
 			}
 
-			logger := tmlog.NewTMLogger(os.Stdout)
+			logger, err := base.NewLogger(os.Stdout, config.logConfig)
+			if err != nil {
+				return err
+			}
 
 			initOptions := store.InitOptions{
 				NeedDataStore:   false,
@@ -154,14 +206,23 @@ func Init() *cobra.Command {
 			isInit := store.IsInit(logger, config.home, initOptions)
 			if isInit {
 				logger.Info("provided path is already initiated", "path", config.home)
-				return nil
+			} else {
+				err = store.Init(logger, config.home, initOptions)
+				if err != nil {
+					return err
+				}
 			}
 
-			err = store.Init(logger, config.home, initOptions)
+			// persist the passed flags, along with the defaults, so that they're loaded on start
+			err = base.WriteConfigFile(cmd, config.home)
 			if err != nil {
+				if errors.Is(err, base.ErrConfigFileExists) {
+					logger.Info("config file already exists, not overwriting it", "path", base.ConfigFilePath(config.home))
+					return nil
+				}
 				return err
 			}
-
+			logger.Info("config file written", "path", base.ConfigFilePath(config.home))
 			return nil
 		},
 	}
@@ -2,6 +2,7 @@ package bootstrapper
 
 import (
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
 	"github.com/spf13/cobra"
 )
 
@@ -15,16 +16,26 @@ func addStartFlags(cmd *cobra.Command) *cobra.Command {
 		panic(err)
 	}
 	cmd.Flags().String(base.FlagHome, homeDir, "The qgb bootstrappers home directory")
+	base.AddP2PPassphraseFlags(cmd)
 	base.AddP2PNicknameFlag(cmd)
 	base.AddP2PListenAddressFlag(cmd)
 	base.AddBootstrappersFlag(cmd)
+	base.AddP2PSwarmKeyFlag(cmd)
+	base.AddP2PHostFlags(cmd)
+	base.AddP2PProtocolVersionFlags(cmd)
+	base.AddLogFlags(cmd)
 	return cmd
 }
 
 type StartConfig struct {
 	home                       string
 	p2pListenAddr, p2pNickname string
+	p2pPassphrase              string
 	bootstrappers              string
+	p2pSwarmKey                string
+	p2pHostConfig              p2p.HostConfig
+	p2pVersions                p2p.ProtocolVersions
+	logConfig                  base.LogConfig
 }
 
 func parseStartFlags(cmd *cobra.Command) (StartConfig, error) {
@@ -36,6 +47,10 @@ func parseStartFlags(cmd *cobra.Command) (StartConfig, error) {
 	if err != nil {
 		return StartConfig{}, err
 	}
+	p2pPassphrase, err := base.ParseP2PPassphrase(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
 	homeDir, err := cmd.Flags().GetString(base.FlagHome)
 	if err != nil {
 		return StartConfig{}, err
@@ -51,26 +66,44 @@ func parseStartFlags(cmd *cobra.Command) (StartConfig, error) {
 	if err != nil {
 		return StartConfig{}, err
 	}
+	p2pSwarmKey, err := cmd.Flags().GetString(base.FlagP2PSwarmKey)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	p2pHostConfig, err := base.ParseP2PHostFlags(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	p2pVersions, err := base.ParseP2PProtocolVersions(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	logConfig, err := base.ParseLogFlags(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
 
 	return StartConfig{
 		p2pNickname:   p2pNickname,
 		p2pListenAddr: p2pListenAddress,
+		p2pPassphrase: p2pPassphrase,
 		home:          homeDir,
 		bootstrappers: bootstrappers,
+		p2pSwarmKey:   p2pSwarmKey,
+		p2pHostConfig: p2pHostConfig,
+		p2pVersions:   p2pVersions,
+		logConfig:     logConfig,
 	}, nil
 }
 
+// addInitFlags adds the start flags to the init command, so that they're written to the config file.
 func addInitFlags(cmd *cobra.Command) *cobra.Command {
-	homeDir, err := base.DefaultServicePath(ServiceNameBootstrapper)
-	if err != nil {
-		panic(err)
-	}
-	cmd.Flags().String(base.FlagHome, homeDir, "The qgb bootstrappers home directory")
-	return cmd
+	return addStartFlags(cmd)
 }
 
 type InitConfig struct {
-	home string
+	home      string
+	logConfig base.LogConfig
 }
 
 func parseInitFlags(cmd *cobra.Command) (InitConfig, error) {
@@ -86,7 +119,13 @@ func parseInitFlags(cmd *cobra.Command) (InitConfig, error) {
 		}
 	}
 
+	logConfig, err := base.ParseLogFlags(cmd)
+	if err != nil {
+		return InitConfig{}, err
+	}
+
 	return InitConfig{
-		home: homeDir,
+		home:      homeDir,
+		logConfig: logConfig,
 	}, nil
 }
@@ -8,6 +8,10 @@ import (
 
 	"github.com/celestiaorg/orchestrator-relayer/store"
 
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/libp2p/go-libp2p"
+	dht "github.com/libp2p/go-libp2p-kad-dht"
 	"github.com/libp2p/go-libp2p/core/host"
 
 	"github.com/celestiaorg/celestia-app/app"
@@ -16,9 +20,9 @@ import (
 	"github.com/celestiaorg/orchestrator-relayer/helpers"
 	"github.com/celestiaorg/orchestrator-relayer/p2p"
 	"github.com/celestiaorg/orchestrator-relayer/rpc"
-	keystore2 "github.com/ipfs/boxo/keystore"
 	ds "github.com/ipfs/go-datastore"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/pnet"
 	tmlog "github.com/tendermint/tendermint/libs/log"
 )
 
@@ -60,55 +64,159 @@ func NewTmAndAppQuerier(logger tmlog.Logger, tendermintRPC string, celesGRPC str
 	return tmQuerier, appQuerier, stopFuncs, nil
 }
 
+// P2PAuthOptions the options used to authenticate the QGB peers.
+// The zero value disables the authentication.
+type P2PAuthOptions struct {
+	// Gater if set, restricts the DHT participation to the authenticated validators, the bootstrappers
+	// and the allowlisted peers.
+	Gater *p2p.ConnectionGater
+	// EVMKeyStore and EVMAccount if set, are used to prove control of the EVM address to the
+	// other peers. The account should be unlocked.
+	EVMKeyStore *keystore.KeyStore
+	EVMAccount  *accounts.Account
+	// ChainView if set, the confirms are validated against the Celestia valsets and attestations
+	// in addition to the stateless checks.
+	ChainView *p2p.ChainView
+}
+
+// LoadSwarmKey helper function that loads the private network pre-shared key from the provided file.
+// If the file is not specified, the store swarm key is loaded if it exists.
+// Returns a nil key if no swarm key is found, i.e. the host will join the public network.
+func LoadSwarmKey(logger tmlog.Logger, s *store.Store, swarmKeyFile string) (pnet.PSK, error) {
+	if swarmKeyFile == "" {
+		if !store.Exists(s.SwarmKeyPath()) {
+			return nil, nil
+		}
+		swarmKeyFile = s.SwarmKeyPath()
+	}
+	psk, err := p2p.LoadSwarmKey(swarmKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("joining the private network defined by the swarm key", "path", swarmKeyFile)
+	return psk, nil
+}
+
 // CreateDHTAndWaitForPeers helper function that creates a new QGB DHT and waits for some peers to connect to it.
 func CreateDHTAndWaitForPeers(
 	ctx context.Context,
 	logger tmlog.Logger,
-	p2pKeyStore *keystore2.FSKeystore,
+	p2pKeyStore *store.P2PKeyStore,
 	p2pNickname string,
+	p2pPassphrase string,
 	p2pListenAddr string,
 	bootstrappers string,
 	dataStore ds.Batching,
+	hostConfig p2p.HostConfig,
+	versions p2p.ProtocolVersions,
+	authOpts P2PAuthOptions,
 ) (*p2p.QgbDHT, error) {
 	// get the p2p private key or generate a new one
-	privKey, err := common2.GetP2PKeyOrGenerateNewOne(p2pKeyStore, p2pNickname)
+	privKey, err := common2.GetP2PKeyOrGenerateNewOne(p2pKeyStore, p2pNickname, p2pPassphrase)
 	if err != nil {
 		return nil, err
 	}
 
+	// get the bootstrappers
+	var aIBootstrappers []peer.AddrInfo
+	if bootstrappers == "" {
+		aIBootstrappers = nil
+	} else {
+		bs := strings.Split(bootstrappers, ",")
+		aIBootstrappers, err = helpers.ParseAddrInfos(logger, bs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the known peers are persisted in the data store so that they can be redialed after a restart
+	if hostConfig.Peerstore == nil {
+		hostConfig.Peerstore, err = p2p.NewPeerstore(ctx, dataStore)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// creating the host
-	h, err := p2p.CreateHost(p2pListenAddr, privKey)
+	libp2pOpts, err := hostConfig.Options()
+	if err != nil {
+		return nil, err
+	}
+	if authOpts.Gater != nil {
+		libp2pOpts = append(libp2pOpts, libp2p.ConnectionGater(authOpts.Gater))
+	}
+	h, err := p2p.CreateHost(p2pListenAddr, privKey, libp2pOpts...)
 	if err != nil {
 		return nil, err
 	}
 	logger.Info("created P2P host")
+	p2p.ProtectPeers(h, p2p.BootstrapperProtectionTag, p2p.AddrInfosIDs(aIBootstrappers)...)
+	p2p.ProtectPeers(h, p2p.RelayProtectionTag, p2p.AddrInfosIDs(hostConfig.StaticRelays)...)
 
 	prettyPrintHost(h)
 
-	// get the bootstrappers
-	var aIBootstrappers []peer.AddrInfo
-	if bootstrappers == "" {
-		aIBootstrappers = nil
-	} else {
-		bs := strings.Split(bootstrappers, ",")
-		aIBootstrappers, err = helpers.ParseAddrInfos(logger, bs)
+	if authOpts.EVMKeyStore != nil && authOpts.EVMAccount != nil {
+		auth, err := p2p.NewPeerAuthentication(authOpts.EVMKeyStore, *authOpts.EVMAccount, h.ID())
 		if err != nil {
 			return nil, err
 		}
+		err = p2p.SetAuthenticationHandler(h, *auth)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("authenticating the P2P host using EVM address", "evm_address", auth.EVMAddress)
 	}
 
 	// creating the dht
-	dht, err := p2p.NewQgbDHT(ctx, h, dataStore, aIBootstrappers, logger)
+	dhtOpts := make([]dht.Option, 0)
+	if authOpts.Gater != nil {
+		for _, bootstrapper := range aIBootstrappers {
+			authOpts.Gater.Allow(bootstrapper.ID)
+		}
+		dhtOpts = append(dhtOpts, dht.RoutingTableFilter(authOpts.Gater.RoutingTableFilter))
+	}
+	if authOpts.ChainView != nil {
+		dhtOpts = append(dhtOpts, authOpts.ChainView.DHTOptions()...)
+		logger.Info("validating the confirms against the Celestia attestations")
+	}
+	dhtHost := h
+	if authOpts.Gater != nil {
+		// the non allowed peers can query the DHT, but can't put values into it
+		dhtHost = authOpts.Gater.GateStreams(h)
+	}
+	qgbDHT, err := p2p.NewVersionedQgbDHT(ctx, dhtHost, dataStore, aIBootstrappers, logger, versions, dhtOpts...)
 	if err != nil {
 		return nil, err
 	}
 
+	if authOpts.Gater != nil {
+		err = authOpts.Gater.Start(ctx, qgbDHT, p2p.DefaultValsetRefreshInterval)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("restricting the DHT servers to the authenticated peers")
+	}
+
+	if hostConfig.EnableMDNS {
+		err = p2p.StartMDNSDiscovery(ctx, h, logger)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("discovering the local network peers using mDNS")
+	}
+
+	// redial the peers known from the previous runs, so that the network can be rejoined
+	// even if the bootstrappers are unreachable
+	p2p.RememberConnectedPeers(ctx, h, p2p.DefaultRememberPeersInterval)
+	knownPeers := p2p.ConnectToKnownPeers(ctx, h, p2p.DefaultKnownPeersDialTimeout)
+	logger.Info("connected to known peers", "count", knownPeers)
+
 	// wait for the dht to have some peers
-	err = dht.WaitForPeers(ctx, 5*time.Minute, 10*time.Second, 1)
+	err = qgbDHT.WaitForPeers(ctx, 5*time.Minute, 10*time.Second, 1)
 	if err != nil {
 		return nil, err
 	}
-	return dht, nil
+	return qgbDHT, nil
 }
 
 func OpenStore(logger tmlog.Logger, home string, openOptions store.OpenOptions) (*store.Store, []func() error, error) {
@@ -120,6 +228,7 @@ func OpenStore(logger tmlog.Logger, home string, openOptions store.OpenOptions)
 		NeedEVMKeyStore:    openOptions.HasEVMKeyStore,
 		NeedP2PKeyStore:    openOptions.HasP2PKeyStore,
 		NeedSignatureStore: openOptions.HasSignatureStore,
+		Backend:            openOptions.Backend,
 	})
 	if !isInit {
 		return nil, stopFuncs, store.ErrNotInited
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/spf13/cobra"
+)
+
+// Command returns the config command of a service, whose configuration is defined by the flags added
+// using addFlags, i.e. the flags of its start command.
+func Command(addFlags func(cmd *cobra.Command) *cobra.Command) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:          "config",
+		Short:        "QGB configuration manager",
+		SilenceUsage: true,
+	}
+
+	configCmd.AddCommand(
+		Show(addFlags),
+	)
+
+	configCmd.SetHelpCommand(&cobra.Command{})
+
+	return configCmd
+}
+
+func Show(addFlags func(cmd *cobra.Command) *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "print the effective configuration, from the passed flags, the " + base.EnvPrefix + "_* environment variables and the config file, by order of precedence. The passphrases are redacted",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := base.ConfigTOML(cmd)
+			if err != nil {
+				return err
+			}
+			fmt.Print(config)
+			return nil
+		},
+	}
+	return addFlags(cmd)
+}
@@ -7,6 +7,7 @@ import (
 
 	evm2 "github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/evm"
 
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
@@ -32,7 +33,10 @@ func Command() *cobra.Command {
 				return err
 			}
 
-			logger := tmlog.NewTMLogger(os.Stdout)
+			logger, err := base.NewLogger(os.Stdout, config.Log)
+			if err != nil {
+				return err
+			}
 
 			// checking if the provided home is already initiated
 			isInit := store.IsInit(logger, config.Home, store.InitOptions{NeedEVMKeyStore: true})
@@ -43,7 +47,7 @@ func Command() *cobra.Command {
 
 			encCfg := encoding.MakeConfig(app.ModuleEncodingRegisters...)
 
-			querier := rpc.NewAppQuerier(logger, config.coreGRPC, encCfg)
+			querier := rpc.NewAppQuerier(base.ModuleLogger(logger, base.LogModuleRPC), config.coreGRPC, encCfg)
 			err = querier.Start()
 			if err != nil {
 				return err
@@ -76,7 +80,7 @@ func Command() *cobra.Command {
 				}
 			}(s, logger)
 
-			logger.Info("loading EVM account", "address", config.evmAccAddress)
+			logger.Info("loading EVM account", "evm_address", config.evmAccAddress)
 
 			acc, err := evm2.GetAccountFromStoreAndUnlockIt(s.EVMKeyStore, config.evmAccAddress, config.EVMPassphrase)
 			if err != nil {
@@ -91,7 +95,7 @@ func Command() *cobra.Command {
 			}(s.EVMKeyStore, acc.Address)
 
 			evmClient := evm.NewClient(
-				tmlog.NewTMLogger(os.Stdout),
+				base.ModuleLogger(logger, base.LogModuleEVM),
 				nil,
 				s.EVMKeyStore,
 				&acc,
@@ -118,7 +122,7 @@ func Command() *cobra.Command {
 
 			receipt, err := evmClient.WaitForTransaction(cmd.Context(), backend, tx)
 			if err == nil && receipt != nil && receipt.Status == 1 {
-				logger.Info("deployed QGB contract", "address", address.Hex(), "hash", tx.Hash().String())
+				logger.Info("deployed QGB contract", "contract_address", address.Hex(), "tx_hash", tx.Hash().String())
 			}
 
 			return nil
@@ -41,7 +41,8 @@ func addDeployFlags(cmd *cobra.Command) *cobra.Command {
 		panic(err)
 	}
 	cmd.Flags().String(base.FlagHome, homeDir, "The qgb deployer home directory")
-	cmd.Flags().String(base.FlagEVMPassphrase, "", "the evm account passphrase (if not specified as a flag, it will be asked interactively)")
+	base.AddEVMPassphraseFlags(cmd)
+	base.AddLogFlags(cmd)
 
 	return cmd
 }
@@ -98,7 +99,11 @@ func parseDeployFlags(cmd *cobra.Command) (deployConfig, error) {
 			return deployConfig{}, err
 		}
 	}
-	passphrase, err := cmd.Flags().GetString(base.FlagEVMPassphrase)
+	passphrase, err := base.ParseEVMPassphrase(cmd)
+	if err != nil {
+		return deployConfig{}, err
+	}
+	logConfig, err := base.ParseLogFlags(cmd)
 	if err != nil {
 		return deployConfig{}, err
 	}
@@ -113,6 +118,7 @@ func parseDeployFlags(cmd *cobra.Command) (deployConfig, error) {
 		Config: &base.Config{
 			Home:          homeDir,
 			EVMPassphrase: passphrase,
+			Log:           logConfig,
 		},
 	}, nil
 }
@@ -5,9 +5,18 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/spf13/cobra"
 	tmlog "github.com/tendermint/tendermint/libs/log"
 )
 
+const (
+	FlagExportOutput = "output"
+	FlagExportYes    = "yes"
+
+	// exportedKeyPerms the exported key files permissions, only readable by the owner.
+	exportedKeyPerms = 0o600
+)
+
 // ConfirmDeletePrivateKey is used to get a confirmation before deleting a private key
 func ConfirmDeletePrivateKey(logger tmlog.Logger) bool {
 	logger.Info("Are you sure you want to delete your private key? This action cannot be undone and may result in permanent loss of access to your account.")
@@ -19,3 +28,62 @@ func ConfirmDeletePrivateKey(logger tmlog.Logger) bool {
 
 	return input == "yes"
 }
+
+// ConfirmExportPrivateKey is used to get a confirmation before exporting a private key.
+// The prompt is written to stderr, so that it doesn't end up in the exported key when written to stdout.
+func ConfirmExportPrivateKey(logger tmlog.Logger) bool {
+	logger.Info("Are you sure you want to export your private key? Anyone getting access to the exported key may be able to use your account.")
+	fmt.Fprint(os.Stderr, "Please enter 'yes' or 'no' to confirm your decision: ")
+
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Scan()
+	input := scanner.Text()
+
+	return input == "yes"
+}
+
+// AddExportFlags adds the flags of the keys export commands.
+func AddExportFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(FlagExportOutput, "o", "", "the file to write the exported key to. If not specified, the key is written to stdout")
+	cmd.Flags().Bool(FlagExportYes, false, "skip the export confirmation prompt")
+}
+
+// ExportConfig the configuration of the keys export commands.
+type ExportConfig struct {
+	// Output the file to write the exported key to. Empty for stdout.
+	Output string
+	// Confirmed true if the export was confirmed using a flag, so it is not asked interactively.
+	Confirmed bool
+}
+
+// ParseExportFlags parses the flags added by AddExportFlags.
+func ParseExportFlags(cmd *cobra.Command) (ExportConfig, error) {
+	output, err := cmd.Flags().GetString(FlagExportOutput)
+	if err != nil {
+		return ExportConfig{}, err
+	}
+	confirmed, err := cmd.Flags().GetBool(FlagExportYes)
+	if err != nil {
+		return ExportConfig{}, err
+	}
+	return ExportConfig{Output: output, Confirmed: confirmed}, nil
+}
+
+// WriteExportedKey writes the exported key to the provided file, or to stdout if it's empty.
+// An existing file is not overwritten.
+func WriteExportedKey(output string, key []byte) error {
+	if output == "" {
+		_, err := os.Stdout.Write(key)
+		return err
+	}
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_EXCL, exportedKeyPerms)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(key)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -2,12 +2,17 @@ package evm
 
 import (
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	common2 "github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/common"
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/spf13/cobra"
 )
 
 const (
-	FlagNewEVMPassphrase = "evm.new-passphrase"
+	FlagNewEVMPassphrase       = "evm.new-passphrase"
+	FlagNewEVMPassphraseSource = "evm.new-passphrase-source"
+	FlagEVMMnemonic            = "evm.mnemonic"
+	FlagEVMHDPath              = "evm.hd-path"
+	FlagEVMReEncrypt           = "evm.re-encrypt"
 )
 
 func keysConfigFlags(cmd *cobra.Command, service string) *cobra.Command {
@@ -16,7 +21,7 @@ func keysConfigFlags(cmd *cobra.Command, service string) *cobra.Command {
 		panic(err)
 	}
 	cmd.Flags().String(base.FlagHome, homeDir, "The qgb evm keys home directory")
-	cmd.Flags().String(base.FlagEVMPassphrase, "", "the evm account passphrase (if not specified as a flag, it will be asked interactively)")
+	base.AddEVMPassphraseFlags(cmd)
 	return cmd
 }
 
@@ -36,7 +41,7 @@ func parseKeysConfigFlags(cmd *cobra.Command, serviceName string) (KeysConfig, e
 			return KeysConfig{}, err
 		}
 	}
-	passphrase, err := cmd.Flags().GetString(base.FlagEVMPassphrase)
+	passphrase, err := base.ParseEVMPassphrase(cmd)
 	if err != nil {
 		return KeysConfig{}, err
 	}
@@ -55,8 +60,9 @@ func keysNewPassphraseConfigFlags(cmd *cobra.Command, service string) *cobra.Com
 		panic(err)
 	}
 	cmd.Flags().String(base.FlagHome, homeDir, "The qgb evm keys home directory")
-	cmd.Flags().String(base.FlagEVMPassphrase, "", "the evm account passphrase (if not specified as a flag, it will be asked interactively)")
-	cmd.Flags().String(FlagNewEVMPassphrase, "", "the evm account new passphrase (if not specified as a flag, it will be asked interactively)")
+	base.AddEVMPassphraseFlags(cmd)
+	cmd.Flags().String(FlagNewEVMPassphrase, "", "the evm account new passphrase (if not specified as a flag or using --"+FlagNewEVMPassphraseSource+", it will be asked interactively)")
+	cmd.Flags().String(FlagNewEVMPassphraseSource, "", "where to read the evm account new passphrase from, instead of passing it as a flag: 'file:<path>', 'env:<variable>', 'cmd:<helper command>' or 'vault:<secret url>#<field>'")
 	return cmd
 }
 
@@ -77,12 +83,12 @@ func parseKeysNewPassphraseConfigFlags(cmd *cobra.Command, serviceName string) (
 			return KeysNewPassphraseConfig{}, err
 		}
 	}
-	passphrase, err := cmd.Flags().GetString(base.FlagEVMPassphrase)
+	passphrase, err := base.ParseEVMPassphrase(cmd)
 	if err != nil {
 		return KeysNewPassphraseConfig{}, err
 	}
 
-	newPassphrase, err := cmd.Flags().GetString(FlagNewEVMPassphrase)
+	newPassphrase, err := base.ParsePassphraseFlags(cmd, FlagNewEVMPassphrase, FlagNewEVMPassphraseSource)
 	if err != nil {
 		return KeysNewPassphraseConfig{}, err
 	}
@@ -92,3 +98,106 @@ func parseKeysNewPassphraseConfigFlags(cmd *cobra.Command, serviceName string) (
 		newPassphrase: newPassphrase,
 	}, nil
 }
+
+func keysAddConfigFlags(cmd *cobra.Command, service string) *cobra.Command {
+	keysConfigFlags(cmd, service)
+	cmd.Flags().Bool(FlagEVMMnemonic, false, "derive the account from a newly generated BIP-39 mnemonic, which is printed once and should be backed up")
+	cmd.Flags().String(FlagEVMHDPath, DefaultHDPath, "the BIP-44 derivation path of the account, used with a mnemonic")
+	return cmd
+}
+
+type KeysAddConfig struct {
+	*base.Config
+	mnemonic bool
+	hdPath   string
+}
+
+func parseKeysAddConfigFlags(cmd *cobra.Command, serviceName string) (KeysAddConfig, error) {
+	keysConfig, err := parseKeysConfigFlags(cmd, serviceName)
+	if err != nil {
+		return KeysAddConfig{}, err
+	}
+	mnemonic, err := cmd.Flags().GetBool(FlagEVMMnemonic)
+	if err != nil {
+		return KeysAddConfig{}, err
+	}
+	hdPath, err := cmd.Flags().GetString(FlagEVMHDPath)
+	if err != nil {
+		return KeysAddConfig{}, err
+	}
+
+	return KeysAddConfig{
+		Config:   keysConfig.Config,
+		mnemonic: mnemonic,
+		hdPath:   hdPath,
+	}, nil
+}
+
+func keysMnemonicConfigFlags(cmd *cobra.Command, service string) *cobra.Command {
+	keysConfigFlags(cmd, service)
+	cmd.Flags().String(FlagEVMHDPath, DefaultHDPath, "the BIP-44 derivation path of the account")
+	return cmd
+}
+
+type KeysMnemonicConfig struct {
+	*base.Config
+	hdPath string
+}
+
+func parseKeysMnemonicConfigFlags(cmd *cobra.Command, serviceName string) (KeysMnemonicConfig, error) {
+	keysConfig, err := parseKeysConfigFlags(cmd, serviceName)
+	if err != nil {
+		return KeysMnemonicConfig{}, err
+	}
+	hdPath, err := cmd.Flags().GetString(FlagEVMHDPath)
+	if err != nil {
+		return KeysMnemonicConfig{}, err
+	}
+
+	return KeysMnemonicConfig{
+		Config: keysConfig.Config,
+		hdPath: hdPath,
+	}, nil
+}
+
+func keysExportConfigFlags(cmd *cobra.Command, service string) *cobra.Command {
+	keysConfigFlags(cmd, service)
+	cmd.Flags().String(FlagNewEVMPassphrase, "", "the passphrase used to encrypt the exported key. Implies --"+FlagEVMReEncrypt)
+	cmd.Flags().String(FlagNewEVMPassphraseSource, "", "where to read the passphrase used to encrypt the exported key from, instead of passing it as a flag: 'file:<path>', 'env:<variable>', 'cmd:<helper command>' or 'vault:<secret url>#<field>'"+". Implies --"+FlagEVMReEncrypt)
+	cmd.Flags().Bool(FlagEVMReEncrypt, false, "encrypt the exported key using a new passphrase (if not specified as a flag, it will be asked interactively). Otherwise, the account passphrase is kept")
+	common2.AddExportFlags(cmd)
+	return cmd
+}
+
+type KeysExportConfig struct {
+	*base.Config
+	newPassphrase string
+	reEncrypt     bool
+	export        common2.ExportConfig
+}
+
+func parseKeysExportConfigFlags(cmd *cobra.Command, serviceName string) (KeysExportConfig, error) {
+	keysConfig, err := parseKeysConfigFlags(cmd, serviceName)
+	if err != nil {
+		return KeysExportConfig{}, err
+	}
+	newPassphrase, err := base.ParsePassphraseFlags(cmd, FlagNewEVMPassphrase, FlagNewEVMPassphraseSource)
+	if err != nil {
+		return KeysExportConfig{}, err
+	}
+	reEncrypt, err := cmd.Flags().GetBool(FlagEVMReEncrypt)
+	if err != nil {
+		return KeysExportConfig{}, err
+	}
+	export, err := common2.ParseExportFlags(cmd)
+	if err != nil {
+		return KeysExportConfig{}, err
+	}
+
+	return KeysExportConfig{
+		Config:        keysConfig.Config,
+		newPassphrase: newPassphrase,
+		reEncrypt:     reEncrypt || newPassphrase != "",
+		export:        export,
+	}, nil
+}
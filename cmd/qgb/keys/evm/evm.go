@@ -10,6 +10,7 @@ import (
 
 	common2 "github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/common"
 	"github.com/celestiaorg/orchestrator-relayer/store"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	ethcrypto "github.com/ethereum/go-ethereum/crypto"
@@ -30,6 +31,7 @@ func Root(serviceName string) *cobra.Command {
 		List(serviceName),
 		Delete(serviceName),
 		Import(serviceName),
+		Export(serviceName),
 		Update(serviceName),
 	)
 
@@ -43,11 +45,19 @@ func Add(serviceName string) *cobra.Command {
 		Use:   "add",
 		Short: "create a new EVM address",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			config, err := parseKeysConfigFlags(cmd, serviceName)
+			config, err := parseKeysAddConfigFlags(cmd, serviceName)
 			if err != nil {
 				return err
 			}
 
+			if config.mnemonic {
+				// validate the derivation path before creating anything
+				_, err = hd.NewParamsFromPath(config.hdPath)
+				if err != nil {
+					return err
+				}
+			}
+
 			logger := tmlog.NewTMLogger(os.Stdout)
 
 			initOptions := store.InitOptions{NeedEVMKeyStore: true}
@@ -83,15 +93,34 @@ func Add(serviceName string) *cobra.Command {
 				}
 			}
 
-			account, err := s.EVMKeyStore.NewAccount(passphrase)
+			if !config.mnemonic {
+				account, err := s.EVMKeyStore.NewAccount(passphrase)
+				if err != nil {
+					return err
+				}
+				logger.Info("account created successfully", "evm_address", account.Address.String())
+				return nil
+			}
+
+			mnemonic, err := NewMnemonic()
 			if err != nil {
 				return err
 			}
-			logger.Info("account created successfully", "address", account.Address.String())
+			ethPrivKey, err := PrivateKeyFromMnemonic(mnemonic, config.hdPath)
+			if err != nil {
+				return err
+			}
+			account, err := s.EVMKeyStore.ImportECDSA(ethPrivKey, passphrase)
+			if err != nil {
+				return err
+			}
+			logger.Info("account created successfully", "evm_address", account.Address.String(), "hd_path", config.hdPath)
+			fmt.Printf("\n**Important** write this mnemonic phrase in a safe place.\n"+
+				"It is the only way to recover the account, and it will not be shown again.\n\n%s\n\n", mnemonic)
 			return nil
 		},
 	}
-	return keysConfigFlags(&cmd, serviceName)
+	return keysAddConfigFlags(&cmd, serviceName)
 }
 
 func List(serviceName string) *cobra.Command {
@@ -171,7 +200,7 @@ func Delete(serviceName string) *cobra.Command {
 				}
 			}(s, logger)
 
-			logger.Info("deleting account", "address", args[0])
+			logger.Info("deleting account", "evm_address", args[0])
 
 			acc, err := GetAccountFromStore(s.EVMKeyStore, args[0])
 			if err != nil {
@@ -194,7 +223,7 @@ func Delete(serviceName string) *cobra.Command {
 
 			confirm := common2.ConfirmDeletePrivateKey(logger)
 			if !confirm {
-				logger.Info("deletion of private key has been cancelled", "address", acc.Address.String())
+				logger.Info("deletion of private key has been cancelled", "evm_address", acc.Address.String())
 				return nil
 			}
 
@@ -203,7 +232,7 @@ func Delete(serviceName string) *cobra.Command {
 				return err
 			}
 
-			logger.Info("private key has been deleted successfully", "address", acc.Address.String())
+			logger.Info("private key has been deleted successfully", "evm_address", acc.Address.String())
 
 			return nil
 		},
@@ -221,6 +250,7 @@ func Import(serviceName string) *cobra.Command {
 	importCmd.AddCommand(
 		ImportFile(serviceName),
 		ImportECDSA(serviceName),
+		ImportMnemonic(serviceName),
 	)
 
 	importCmd.SetHelpCommand(&cobra.Command{})
@@ -307,7 +337,7 @@ func ImportFile(serviceName string) *cobra.Command {
 				return err
 			}
 
-			logger.Info("successfully imported file", "address", account.Address.String())
+			logger.Info("successfully imported file", "evm_address", account.Address.String())
 			return nil
 		},
 	}
@@ -372,13 +402,170 @@ func ImportECDSA(serviceName string) *cobra.Command {
 				return err
 			}
 
-			logger.Info("successfully imported file", "address", account.Address.String())
+			logger.Info("successfully imported file", "evm_address", account.Address.String())
 			return nil
 		},
 	}
 	return keysConfigFlags(&cmd, serviceName)
 }
 
+func ImportMnemonic(serviceName string) *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "mnemonic",
+		Args:  cobra.NoArgs,
+		Short: "import an EVM address from a BIP-39 mnemonic",
+		Long: "Import an EVM address from a BIP-39 mnemonic, derived using the BIP-44 path specified by --" + FlagEVMHDPath + ".\n" +
+			"The mnemonic is asked interactively, or read from the standard input if it is not a terminal.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseKeysMnemonicConfigFlags(cmd, serviceName)
+			if err != nil {
+				return err
+			}
+
+			mnemonic, err := GetMnemonic()
+			if err != nil {
+				return err
+			}
+			ethPrivKey, err := PrivateKeyFromMnemonic(mnemonic, config.hdPath)
+			if err != nil {
+				return err
+			}
+
+			logger := tmlog.NewTMLogger(os.Stdout)
+
+			initOptions := store.InitOptions{NeedEVMKeyStore: true}
+			isInit := store.IsInit(logger, config.Home, initOptions)
+
+			// initialize the store if not initialized
+			if !isInit {
+				err := store.Init(logger, config.Home, initOptions)
+				if err != nil {
+					return err
+				}
+			}
+
+			// open store
+			openOptions := store.OpenOptions{HasEVMKeyStore: true}
+			s, err := store.OpenStore(logger, config.Home, openOptions)
+			if err != nil {
+				return err
+			}
+			defer func(s *store.Store, log tmlog.Logger) {
+				err := s.Close(log, openOptions)
+				if err != nil {
+					logger.Error(err.Error())
+				}
+			}(s, logger)
+
+			logger.Info("importing account", "hd_path", config.hdPath)
+
+			passphrase := config.EVMPassphrase
+			// if the passphrase is not specified as a flag, ask for it.
+			if passphrase == "" {
+				passphrase, err = GetNewPassphrase()
+				if err != nil {
+					return err
+				}
+			}
+
+			account, err := s.EVMKeyStore.ImportECDSA(ethPrivKey, passphrase)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("successfully imported mnemonic", "evm_address", account.Address.String())
+			return nil
+		},
+	}
+	return keysMnemonicConfigFlags(&cmd, serviceName)
+}
+
+func Export(serviceName string) *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "export <account address in hex>",
+		Args:  cobra.ExactArgs(1),
+		Short: "export an EVM private key as an encrypted JSON key file",
+		Long: "Export an EVM private key as an encrypted V3 JSON key file, which can be imported using the `import file` subcommand or by geth.\n" +
+			"The key file is written to stdout, unless the --" + common2.FlagExportOutput + " flag is specified.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseKeysExportConfigFlags(cmd, serviceName)
+			if err != nil {
+				return err
+			}
+
+			// the logs are written to stderr, so that they don't end up in the exported key
+			logger := tmlog.NewTMLogger(os.Stderr)
+
+			isInit := store.IsInit(logger, config.Home, store.InitOptions{NeedEVMKeyStore: true})
+
+			// check if not initialized
+			if !isInit {
+				return store.ErrNotInited
+			}
+
+			// open store
+			openOptions := store.OpenOptions{HasEVMKeyStore: true}
+			s, err := store.OpenStore(logger, config.Home, openOptions)
+			if err != nil {
+				return err
+			}
+			defer func(s *store.Store, log tmlog.Logger) {
+				err := s.Close(log, openOptions)
+				if err != nil {
+					logger.Error(err.Error())
+				}
+			}(s, logger)
+
+			logger.Info("exporting account", "evm_address", args[0])
+
+			acc, err := GetAccountFromStore(s.EVMKeyStore, args[0])
+			if err != nil {
+				return err
+			}
+
+			passphrase := config.EVMPassphrase
+			// if the passphrase is not specified as a flag, ask for it.
+			if passphrase == "" {
+				passphrase, err = GetPassphrase()
+				if err != nil {
+					return err
+				}
+			}
+
+			newPassphrase := passphrase
+			if config.reEncrypt {
+				newPassphrase = config.newPassphrase
+				// if the new passphrase is not specified as a flag, ask for it.
+				if newPassphrase == "" {
+					newPassphrase, err = GetNewPassphrase()
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			keyJSON, err := s.EVMKeyStore.Export(acc, passphrase, newPassphrase)
+			if err != nil {
+				return err
+			}
+
+			if !config.export.Confirmed && !common2.ConfirmExportPrivateKey(logger) {
+				logger.Info("export of private key has been cancelled", "evm_address", acc.Address.String())
+				return nil
+			}
+
+			err = common2.WriteExportedKey(config.export.Output, keyJSON)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("private key has been exported successfully", "evm_address", acc.Address.String())
+			return nil
+		},
+	}
+	return keysExportConfigFlags(&cmd, serviceName)
+}
+
 func Update(serviceName string) *cobra.Command {
 	cmd := cobra.Command{
 		Use:   "update <account address in hex>",
@@ -413,7 +600,7 @@ func Update(serviceName string) *cobra.Command {
 				}
 			}(s, logger)
 
-			logger.Info("updating account", "address", args[0])
+			logger.Info("updating account", "evm_address", args[0])
 
 			acc, err := GetAccountFromStore(s.EVMKeyStore, args[0])
 			if err != nil {
@@ -443,7 +630,7 @@ func Update(serviceName string) *cobra.Command {
 				return err
 			}
 
-			logger.Info("successfully updated the passphrase", "address", acc.Address.String())
+			logger.Info("successfully updated the passphrase", "evm_address", acc.Address.String())
 			return nil
 		},
 	}
@@ -497,7 +684,7 @@ func GetAccountFromStore(ks *keystore.KeyStore, evmAddr string) (accounts.Accoun
 }
 
 func GetPassphrase() (string, error) {
-	fmt.Print("please provide the account passphrase: ")
+	fmt.Fprint(os.Stderr, "please provide the account passphrase: ")
 	bzPassphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
 	if err != nil {
 		return "", err
@@ -509,12 +696,12 @@ func GetNewPassphrase() (string, error) {
 	var err error
 	var bzPassphrase []byte
 	for {
-		fmt.Print("please provide the account new passphrase: ")
+		fmt.Fprint(os.Stderr, "please provide the account new passphrase: ")
 		bzPassphrase, err = term.ReadPassword(int(os.Stdin.Fd()))
 		if err != nil {
 			return "", err
 		}
-		fmt.Print("\nenter the same passphrase again: ")
+		fmt.Fprint(os.Stderr, "\nenter the same passphrase again: ")
 		bzPassphraseConfirm, err := term.ReadPassword(int(os.Stdin.Fd()))
 		if err != nil {
 			return "", err
@@ -522,7 +709,7 @@ func GetNewPassphrase() (string, error) {
 		if bytes.Equal(bzPassphrase, bzPassphraseConfirm) {
 			break
 		}
-		fmt.Print("\npassphrase and confirmation mismatch.\n")
+		fmt.Fprint(os.Stderr, "\npassphrase and confirmation mismatch.\n")
 	}
 	return string(bzPassphrase), nil
 }
@@ -0,0 +1,69 @@
+package evm
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/go-bip39"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/term"
+)
+
+// DefaultHDPath the BIP-44 derivation path of the first Ethereum account.
+const DefaultHDPath = "m/44'/60'/0'/0/0"
+
+// mnemonicEntropySize the entropy size, in bits, of the generated mnemonics. Corresponds to 24 words.
+const mnemonicEntropySize = 256
+
+var ErrInvalidMnemonic = errors.New("invalid BIP-39 mnemonic")
+
+// NewMnemonic generates a new 24 words BIP-39 mnemonic.
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropySize)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// PrivateKeyFromMnemonic derives the EVM private key corresponding to the provided BIP-39 mnemonic
+// and BIP-44 derivation path.
+func PrivateKeyFromMnemonic(mnemonic string, hdPath string) (*ecdsa.PrivateKey, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, ErrInvalidMnemonic
+	}
+	_, err := hd.NewParamsFromPath(hdPath)
+	if err != nil {
+		return nil, err
+	}
+	derivedKey, err := hd.Secp256k1.Derive()(mnemonic, "", hdPath)
+	if err != nil {
+		return nil, err
+	}
+	return ethcrypto.ToECDSA(derivedKey)
+}
+
+// GetMnemonic asks for the BIP-39 mnemonic. The input is hidden if the standard input is a terminal,
+// otherwise, the mnemonic is read from the first line of the standard input.
+func GetMnemonic() (string, error) {
+	fmt.Fprint(os.Stderr, "please provide the BIP-39 mnemonic: ")
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		bzMnemonic, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		return string(bzMnemonic), nil
+	}
+	mnemonic, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && mnemonic == "" {
+		return "", err
+	}
+	return strings.TrimSpace(mnemonic), nil
+}
@@ -0,0 +1,60 @@
+package evm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/evm"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestPrivateKeyFromMnemonic(t *testing.T) {
+	tests := []struct {
+		name    string
+		hdPath  string
+		address string
+	}{
+		{
+			name:    "default path",
+			hdPath:  evm.DefaultHDPath,
+			address: "0x9858EfFD232B4033E47d90003D41EC34EcaEda94",
+		},
+		{
+			name:    "second address index",
+			hdPath:  "m/44'/60'/0'/0/1",
+			address: "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privKey, err := evm.PrivateKeyFromMnemonic(testMnemonic, tt.hdPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.address, ethcrypto.PubkeyToAddress(privKey.PublicKey).Hex())
+		})
+	}
+}
+
+func TestPrivateKeyFromMnemonicErrors(t *testing.T) {
+	_, err := evm.PrivateKeyFromMnemonic("abandon abandon abandon", evm.DefaultHDPath)
+	assert.ErrorIs(t, err, evm.ErrInvalidMnemonic)
+
+	_, err = evm.PrivateKeyFromMnemonic(testMnemonic, "m/44'/60'/0'")
+	assert.Error(t, err)
+}
+
+func TestNewMnemonic(t *testing.T) {
+	mnemonic, err := evm.NewMnemonic()
+	require.NoError(t, err)
+	assert.Len(t, strings.Fields(mnemonic), 24)
+
+	// the extra whitespaces are ignored
+	privKey, err := evm.PrivateKeyFromMnemonic(" "+strings.ReplaceAll(mnemonic, " ", "  ")+"\n", evm.DefaultHDPath)
+	require.NoError(t, err)
+	expected, err := evm.PrivateKeyFromMnemonic(mnemonic, evm.DefaultHDPath)
+	require.NoError(t, err)
+	assert.Equal(t, expected.D, privKey.D)
+}
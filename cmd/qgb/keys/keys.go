@@ -3,6 +3,7 @@ package keys
 import (
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/evm"
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/p2p"
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/swarm"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +17,7 @@ func Command(serviceName string) *cobra.Command {
 	keysCmd.AddCommand(
 		evm.Root(serviceName),
 		p2p.Root(serviceName),
+		swarm.Root(serviceName),
 	)
 
 	keysCmd.SetHelpCommand(&cobra.Command{})
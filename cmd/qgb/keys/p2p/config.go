@@ -1,11 +1,45 @@
 package p2p
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/common"
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/spf13/cobra"
 )
 
+const (
+	FlagNewP2PPassphrase       = "p2p.new-passphrase"
+	FlagNewP2PPassphraseSource = "p2p.new-passphrase-source"
+	FlagP2PReEncrypt           = "p2p.re-encrypt"
+	FlagExportFormat           = "format"
+	FlagImportFormat           = "format"
+)
+
+// ExportFormat the format of the exported p2p keys.
+type ExportFormat string
+
+const (
+	// ExportFormatEncrypted the encrypted key file format, as stored in the p2p keystore.
+	ExportFormatEncrypted ExportFormat = "encrypted"
+	// ExportFormatProtobuf the hex encoded plaintext libp2p protobuf encoding of the key.
+	ExportFormatProtobuf ExportFormat = "protobuf"
+)
+
+// ImportFormat the format of the imported p2p keys.
+type ImportFormat string
+
+const (
+	// ImportFormatEd25519 the hex encoded raw Ed25519 private key.
+	ImportFormatEd25519 ImportFormat = "ed25519"
+	// ImportFormatEncrypted the encrypted key file written by the export command.
+	ImportFormatEncrypted = ImportFormat(ExportFormatEncrypted)
+	// ImportFormatProtobuf the hex encoded libp2p protobuf encoding of the key written by the export command.
+	ImportFormatProtobuf = ImportFormat(ExportFormatProtobuf)
+)
+
 func keysConfigFlags(cmd *cobra.Command, service string) *cobra.Command {
 	homeDir, err := base.DefaultServicePath(service)
 	if err != nil {
@@ -35,3 +69,144 @@ func parseKeysConfigFlags(cmd *cobra.Command, serviceName string) (KeysConfig, e
 		home: homeDir,
 	}, nil
 }
+
+func keysPassphraseConfigFlags(cmd *cobra.Command, service string) *cobra.Command {
+	keysConfigFlags(cmd, service)
+	base.AddP2PPassphraseFlags(cmd)
+	return cmd
+}
+
+type KeysPassphraseConfig struct {
+	*base.Config
+}
+
+func parseKeysPassphraseConfigFlags(cmd *cobra.Command, serviceName string) (KeysPassphraseConfig, error) {
+	config, err := parseKeysConfigFlags(cmd, serviceName)
+	if err != nil {
+		return KeysPassphraseConfig{}, err
+	}
+	passphrase, err := base.ParseP2PPassphrase(cmd)
+	if err != nil {
+		return KeysPassphraseConfig{}, err
+	}
+	return KeysPassphraseConfig{
+		Config: &base.Config{
+			Home:          config.home,
+			P2PPassphrase: passphrase,
+		},
+	}, nil
+}
+
+func keysExportConfigFlags(cmd *cobra.Command, service string) *cobra.Command {
+	keysPassphraseConfigFlags(cmd, service)
+	cmd.Flags().String(FlagNewP2PPassphrase, "", "the passphrase used to encrypt the exported key. Implies --"+FlagP2PReEncrypt)
+	cmd.Flags().String(FlagNewP2PPassphraseSource, "", "where to read the passphrase used to encrypt the exported key from, instead of passing it as a flag: 'file:<path>', 'env:<variable>', 'cmd:<helper command>' or 'vault:<secret url>#<field>'"+". Implies --"+FlagP2PReEncrypt)
+	cmd.Flags().Bool(FlagP2PReEncrypt, false, "encrypt the exported key using a new passphrase (if not specified as a flag, it will be asked interactively). Otherwise, the key passphrase is kept")
+	cmd.Flags().String(
+		FlagExportFormat,
+		string(ExportFormatEncrypted),
+		fmt.Sprintf("the exported key format: %q for an encrypted key file, or %q for the hex encoded plaintext libp2p protobuf encoded key", ExportFormatEncrypted, ExportFormatProtobuf),
+	)
+	common.AddExportFlags(cmd)
+	return cmd
+}
+
+type KeysExportConfig struct {
+	*base.Config
+	newPassphrase string
+	reEncrypt     bool
+	format        ExportFormat
+	export        common.ExportConfig
+}
+
+func parseKeysExportConfigFlags(cmd *cobra.Command, serviceName string) (KeysExportConfig, error) {
+	config, err := parseKeysPassphraseConfigFlags(cmd, serviceName)
+	if err != nil {
+		return KeysExportConfig{}, err
+	}
+	newPassphrase, err := base.ParsePassphraseFlags(cmd, FlagNewP2PPassphrase, FlagNewP2PPassphraseSource)
+	if err != nil {
+		return KeysExportConfig{}, err
+	}
+	reEncrypt, err := cmd.Flags().GetBool(FlagP2PReEncrypt)
+	if err != nil {
+		return KeysExportConfig{}, err
+	}
+	format, err := cmd.Flags().GetString(FlagExportFormat)
+	if err != nil {
+		return KeysExportConfig{}, err
+	}
+	switch ExportFormat(strings.ToLower(format)) {
+	case ExportFormatEncrypted, ExportFormatProtobuf:
+	default:
+		return KeysExportConfig{}, fmt.Errorf("unknown export format %q. expected %q or %q", format, ExportFormatEncrypted, ExportFormatProtobuf)
+	}
+	export, err := common.ParseExportFlags(cmd)
+	if err != nil {
+		return KeysExportConfig{}, err
+	}
+	return KeysExportConfig{
+		Config:        config.Config,
+		newPassphrase: newPassphrase,
+		reEncrypt:     reEncrypt || newPassphrase != "",
+		format:        ExportFormat(strings.ToLower(format)),
+		export:        export,
+	}, nil
+}
+
+func keysImportConfigFlags(cmd *cobra.Command, service string) *cobra.Command {
+	keysPassphraseConfigFlags(cmd, service)
+	cmd.Flags().String(FlagNewP2PPassphrase, "", "the passphrase used to encrypt the key imported from an encrypted key file. Implies --"+FlagP2PReEncrypt)
+	cmd.Flags().String(FlagNewP2PPassphraseSource, "", "where to read the passphrase used to encrypt the key imported from an encrypted key file from, instead of passing it as a flag: 'file:<path>', 'env:<variable>', 'cmd:<helper command>' or 'vault:<secret url>#<field>'"+". Implies --"+FlagP2PReEncrypt)
+	cmd.Flags().Bool(FlagP2PReEncrypt, false, "encrypt the key imported from an encrypted key file using a new passphrase (if not specified as a flag, it will be asked interactively). Otherwise, the key file passphrase is kept")
+	cmd.Flags().String(
+		FlagImportFormat,
+		string(ImportFormatEd25519),
+		fmt.Sprintf(
+			"the imported key format: %q for a hex encoded raw Ed25519 private key, %q for a key file exported using the %q format, or %q for a key exported using the %q format",
+			ImportFormatEd25519,
+			ImportFormatEncrypted,
+			ExportFormatEncrypted,
+			ImportFormatProtobuf,
+			ExportFormatProtobuf,
+		),
+	)
+	return cmd
+}
+
+type KeysImportConfig struct {
+	*base.Config
+	newPassphrase string
+	reEncrypt     bool
+	format        ImportFormat
+}
+
+func parseKeysImportConfigFlags(cmd *cobra.Command, serviceName string) (KeysImportConfig, error) {
+	config, err := parseKeysPassphraseConfigFlags(cmd, serviceName)
+	if err != nil {
+		return KeysImportConfig{}, err
+	}
+	newPassphrase, err := base.ParsePassphraseFlags(cmd, FlagNewP2PPassphrase, FlagNewP2PPassphraseSource)
+	if err != nil {
+		return KeysImportConfig{}, err
+	}
+	reEncrypt, err := cmd.Flags().GetBool(FlagP2PReEncrypt)
+	if err != nil {
+		return KeysImportConfig{}, err
+	}
+	format, err := cmd.Flags().GetString(FlagImportFormat)
+	if err != nil {
+		return KeysImportConfig{}, err
+	}
+	switch ImportFormat(strings.ToLower(format)) {
+	case ImportFormatEd25519, ImportFormatEncrypted, ImportFormatProtobuf:
+	default:
+		return KeysImportConfig{}, fmt.Errorf("unknown import format %q. expected %q, %q or %q", format, ImportFormatEd25519, ImportFormatEncrypted, ImportFormatProtobuf)
+	}
+	return KeysImportConfig{
+		Config:        config.Config,
+		newPassphrase: newPassphrase,
+		reEncrypt:     reEncrypt || newPassphrase != "",
+		format:        ImportFormat(strings.ToLower(format)),
+	}, nil
+}
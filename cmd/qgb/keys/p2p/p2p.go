@@ -1,11 +1,13 @@
 package p2p
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"os"
+	"strings"
 
-	"github.com/ipfs/boxo/keystore"
+	"golang.org/x/term"
 
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/common"
 	"github.com/celestiaorg/orchestrator-relayer/store"
@@ -28,6 +30,8 @@ func Root(serviceName string) *cobra.Command {
 		List(serviceName),
 		Import(serviceName),
 		Delete(serviceName),
+		Export(serviceName),
+		Migrate(serviceName),
 	)
 
 	return p2pCmd
@@ -39,7 +43,7 @@ func Add(serviceName string) *cobra.Command {
 		Short: "create a new Ed25519 P2P address",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			config, err := parseKeysConfigFlags(cmd, serviceName)
+			config, err := parseKeysPassphraseConfigFlags(cmd, serviceName)
 			if err != nil {
 				return err
 			}
@@ -47,11 +51,11 @@ func Add(serviceName string) *cobra.Command {
 			logger := tmlog.NewTMLogger(os.Stdout)
 
 			initOptions := store.InitOptions{NeedP2PKeyStore: true}
-			isInit := store.IsInit(logger, config.home, initOptions)
+			isInit := store.IsInit(logger, config.Home, initOptions)
 
 			// initialize the store if not initialized
 			if !isInit {
-				err := store.Init(logger, config.home, initOptions)
+				err := store.Init(logger, config.Home, initOptions)
 				if err != nil {
 					return err
 				}
@@ -59,7 +63,7 @@ func Add(serviceName string) *cobra.Command {
 
 			// open store
 			openOptions := store.OpenOptions{HasP2PKeyStore: true}
-			s, err := store.OpenStore(logger, config.home, openOptions)
+			s, err := store.OpenStore(logger, config.Home, openOptions)
 			if err != nil {
 				return err
 			}
@@ -88,7 +92,16 @@ func Add(serviceName string) *cobra.Command {
 				return err
 			}
 
-			err = s.P2PKeyStore.Put(nickname, priv)
+			passphrase := config.P2PPassphrase
+			// if the passphrase is not specified as a flag, ask for it.
+			if passphrase == "" {
+				passphrase, err = GetNewPassphrase()
+				if err != nil {
+					return err
+				}
+			}
+
+			err = s.P2PKeyStore.Put(nickname, priv, passphrase)
 			if err != nil {
 				return err
 			}
@@ -97,7 +110,7 @@ func Add(serviceName string) *cobra.Command {
 			return nil
 		},
 	}
-	return keysConfigFlags(&cmd, serviceName)
+	return keysPassphraseConfigFlags(&cmd, serviceName)
 }
 
 func GenerateNewEd25519() (crypto.PrivKey, error) {
@@ -155,6 +168,14 @@ func List(serviceName string) *cobra.Command {
 				logger.Info(k)
 			}
 
+			plaintext, err := s.P2PKeyStore.ListPlaintext()
+			if err != nil {
+				return err
+			}
+			if len(plaintext) != 0 {
+				logger.Info("the following p2p keys are not encrypted. run the `keys p2p migrate` command to encrypt them", "nicknames", plaintext)
+			}
+
 			return nil
 		},
 	}
@@ -163,11 +184,16 @@ func List(serviceName string) *cobra.Command {
 
 func Import(serviceName string) *cobra.Command {
 	cmd := cobra.Command{
-		Use:   "import <nickname> <private_key_in_hex_without_0x>",
+		Use:   "import <nickname> <private_key>",
 		Short: "import an existing p2p private key",
-		Args:  cobra.ExactArgs(2),
+		Long: "Import an existing p2p private key, in the format specified by --" + FlagImportFormat + ":\n" +
+			"- " + string(ImportFormatEd25519) + ": the raw Ed25519 private key in hex format without 0x\n" +
+			"- " + string(ImportFormatProtobuf) + ": the hex encoded libp2p protobuf encoded key, as exported using the " + string(ExportFormatProtobuf) + " format\n" +
+			"- " + string(ImportFormatEncrypted) + ": the path to a key file exported using the " + string(ExportFormatEncrypted) + " format. " +
+			"It's decrypted using the p2p passphrase, and stored encrypted using the same passphrase, unless --" + FlagP2PReEncrypt + " is specified",
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			config, err := parseKeysConfigFlags(cmd, serviceName)
+			config, err := parseKeysImportConfigFlags(cmd, serviceName)
 			if err != nil {
 				return err
 			}
@@ -175,11 +201,11 @@ func Import(serviceName string) *cobra.Command {
 			logger := tmlog.NewTMLogger(os.Stdout)
 
 			initOptions := store.InitOptions{NeedP2PKeyStore: true}
-			isInit := store.IsInit(logger, config.home, initOptions)
+			isInit := store.IsInit(logger, config.Home, initOptions)
 
 			// initialize if not initialized
 			if !isInit {
-				err := store.Init(logger, config.home, initOptions)
+				err := store.Init(logger, config.Home, initOptions)
 				if err != nil {
 					return err
 				}
@@ -187,7 +213,7 @@ func Import(serviceName string) *cobra.Command {
 
 			// open store
 			openOptions := store.OpenOptions{HasP2PKeyStore: true}
-			s, err := store.OpenStore(logger, config.home, openOptions)
+			s, err := store.OpenStore(logger, config.Home, openOptions)
 			if err != nil {
 				return err
 			}
@@ -198,16 +224,65 @@ func Import(serviceName string) *cobra.Command {
 				}
 			}(s, logger)
 
-			bIdentity, err := hex.DecodeString(args[1])
+			if config.format == ImportFormatEncrypted {
+				keyFile, err := os.ReadFile(args[1])
+				if err != nil {
+					return err
+				}
+
+				passphrase := config.P2PPassphrase
+				// if the passphrase is not specified as a flag, ask for it.
+				if passphrase == "" {
+					passphrase, err = GetPassphrase()
+					if err != nil {
+						return err
+					}
+				}
+				newPassphrase := passphrase
+				if config.reEncrypt {
+					newPassphrase = config.newPassphrase
+					// if the new passphrase is not specified as a flag, ask for it.
+					if newPassphrase == "" {
+						newPassphrase, err = GetNewPassphrase()
+						if err != nil {
+							return err
+						}
+					}
+				}
+
+				_, err = s.P2PKeyStore.Import(args[0], keyFile, passphrase, newPassphrase)
+				if err != nil {
+					return err
+				}
+
+				logger.Info("p2p key added successfully", "nickname", args[0])
+				return nil
+			}
+
+			bIdentity, err := hex.DecodeString(strings.TrimSpace(args[1]))
 			if err != nil {
 				return err
 			}
-			pKey, err := crypto.UnmarshalEd25519PrivateKey(bIdentity)
+			var pKey crypto.PrivKey
+			if config.format == ImportFormatProtobuf {
+				pKey, err = crypto.UnmarshalPrivateKey(bIdentity)
+			} else {
+				pKey, err = crypto.UnmarshalEd25519PrivateKey(bIdentity)
+			}
 			if err != nil {
 				return err
 			}
 
-			err = s.P2PKeyStore.Put(args[0], pKey)
+			passphrase := config.P2PPassphrase
+			// if the passphrase is not specified as a flag, ask for it.
+			if passphrase == "" {
+				passphrase, err = GetNewPassphrase()
+				if err != nil {
+					return err
+				}
+			}
+
+			err = s.P2PKeyStore.Put(args[0], pKey, passphrase)
 			if err != nil {
 				return err
 			}
@@ -217,7 +292,7 @@ func Import(serviceName string) *cobra.Command {
 			return nil
 		},
 	}
-	return keysConfigFlags(&cmd, serviceName)
+	return keysImportConfigFlags(&cmd, serviceName)
 }
 
 func Delete(serviceName string) *cobra.Command {
@@ -277,14 +352,178 @@ func Delete(serviceName string) *cobra.Command {
 	return keysConfigFlags(&cmd, serviceName)
 }
 
+func Export(serviceName string) *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "export <nickname>",
+		Short: "export an Ed25519 P2P private key",
+		Long: "Export an Ed25519 P2P private key, either as an encrypted key file, or as the hex encoded plaintext libp2p protobuf encoded key.\n" +
+			"The exported key can be imported using the import command with the same --" + FlagImportFormat + ".\n" +
+			"The key is written to stdout, unless the --" + common.FlagExportOutput + " flag is specified.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseKeysExportConfigFlags(cmd, serviceName)
+			if err != nil {
+				return err
+			}
+
+			// the logs are written to stderr, so that they don't end up in the exported key
+			logger := tmlog.NewTMLogger(os.Stderr)
+
+			initOptions := store.InitOptions{NeedP2PKeyStore: true}
+			isInit := store.IsInit(logger, config.Home, initOptions)
+
+			// check if not initialized
+			if !isInit {
+				logger.Info("p2p store not initialized", "path", config.Home)
+				return store.ErrNotInited
+			}
+
+			// open store
+			openOptions := store.OpenOptions{HasP2PKeyStore: true}
+			s, err := store.OpenStore(logger, config.Home, openOptions)
+			if err != nil {
+				return err
+			}
+			defer func(s *store.Store, log tmlog.Logger) {
+				err := s.Close(log, openOptions)
+				if err != nil {
+					logger.Error(err.Error())
+				}
+			}(s, logger)
+
+			logger.Info("exporting Ed25519 private key", "nickname", args[0], "format", config.format)
+
+			passphrase := config.P2PPassphrase
+			// if the passphrase is not specified as a flag, ask for it.
+			if passphrase == "" {
+				passphrase, err = GetPassphrase()
+				if err != nil {
+					return err
+				}
+			}
+
+			var exported []byte
+			if config.format == ExportFormatProtobuf {
+				priv, err := s.P2PKeyStore.Get(args[0], passphrase)
+				if err != nil {
+					return err
+				}
+				encoded, err := crypto.MarshalPrivateKey(priv)
+				if err != nil {
+					return err
+				}
+				// hex encoded, so that it can be passed to the import command
+				exported = []byte(hex.EncodeToString(encoded))
+			} else {
+				newPassphrase := passphrase
+				if config.reEncrypt {
+					newPassphrase = config.newPassphrase
+					// if the new passphrase is not specified as a flag, ask for it.
+					if newPassphrase == "" {
+						newPassphrase, err = GetNewPassphrase()
+						if err != nil {
+							return err
+						}
+					}
+				}
+				exported, err = s.P2PKeyStore.Export(args[0], passphrase, newPassphrase)
+				if err != nil {
+					return err
+				}
+			}
+
+			if !config.export.Confirmed && !common.ConfirmExportPrivateKey(logger) {
+				logger.Info("export of private key has been cancelled", "nickname", args[0])
+				return nil
+			}
+
+			err = common.WriteExportedKey(config.export.Output, exported)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("key exported successfully", "nickname", args[0])
+			return nil
+		},
+	}
+	return keysExportConfigFlags(&cmd, serviceName)
+}
+
+func Migrate(serviceName string) *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "migrate",
+		Short: "encrypt the plaintext p2p private keys created by older versions",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseKeysPassphraseConfigFlags(cmd, serviceName)
+			if err != nil {
+				return err
+			}
+
+			logger := tmlog.NewTMLogger(os.Stdout)
+
+			initOptions := store.InitOptions{NeedP2PKeyStore: true}
+			isInit := store.IsInit(logger, config.Home, initOptions)
+
+			// check if not initialized
+			if !isInit {
+				logger.Info("p2p store not initialized", "path", config.Home)
+				return store.ErrNotInited
+			}
+
+			// open store
+			openOptions := store.OpenOptions{HasP2PKeyStore: true}
+			s, err := store.OpenStore(logger, config.Home, openOptions)
+			if err != nil {
+				return err
+			}
+			defer func(s *store.Store, log tmlog.Logger) {
+				err := s.Close(log, openOptions)
+				if err != nil {
+					logger.Error(err.Error())
+				}
+			}(s, logger)
+
+			plaintext, err := s.P2PKeyStore.ListPlaintext()
+			if err != nil {
+				return err
+			}
+			if len(plaintext) == 0 {
+				logger.Info("no plaintext p2p keys to migrate")
+				return nil
+			}
+
+			passphrase := config.P2PPassphrase
+			// if the passphrase is not specified as a flag, ask for it.
+			if passphrase == "" {
+				passphrase, err = GetNewPassphrase()
+				if err != nil {
+					return err
+				}
+			}
+
+			migrated, err := s.P2PKeyStore.MigratePlaintextKeys(passphrase)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("p2p keys encrypted successfully", "nicknames", migrated)
+			return nil
+		},
+	}
+	return keysPassphraseConfigFlags(&cmd, serviceName)
+}
+
 // GetP2PKeyOrGenerateNewOne takes a nickname and either returns its corresponding private key if it
 // doesn't exist, return the first key in the store if it doesn't exist, create a new key, store it in the
 // keystore, then return it.
-func GetP2PKeyOrGenerateNewOne(ks *keystore.FSKeystore, nickname string) (crypto.PrivKey, error) {
+// The keys are decrypted, or encrypted if generated, using the provided passphrase. If it's empty, it
+// will be asked interactively.
+func GetP2PKeyOrGenerateNewOne(ks *store.P2PKeyStore, nickname string, passphrase string) (crypto.PrivKey, error) {
 	// if the key name is not empty, then we try to get its corresponding key
 	if nickname != "" {
 		// return the corresponding key or return an error
-		return ks.Get(nickname)
+		return getP2PKey(ks, nickname, passphrase)
 	}
 	// if not, check if the keystore has any other keys
 	nicknames, err := ks.List()
@@ -293,19 +532,76 @@ func GetP2PKeyOrGenerateNewOne(ks *keystore.FSKeystore, nickname string) (crypto
 	}
 	// if so, get the first key
 	if len(nicknames) != 0 {
-		return ks.Get(nicknames[0])
+		return getP2PKey(ks, nicknames[0], passphrase)
+	}
+	// the plaintext keys should be migrated instead of being replaced by a new key
+	plaintext, err := ks.ListPlaintext()
+	if err != nil {
+		return nil, err
+	}
+	if len(plaintext) != 0 {
+		return nil, fmt.Errorf("%w: %v", store.ErrPlaintextP2PKey, plaintext)
 	}
 	// if not, generate a new key
 	priv, err := GenerateNewEd25519()
 	if err != nil {
 		return nil, err
 	}
+	if passphrase == "" {
+		passphrase, err = GetNewPassphrase()
+		if err != nil {
+			return nil, err
+		}
+	}
 	// store it under the name "0"
 	newKeyNickname := "0"
-	err = ks.Put(newKeyNickname, priv)
+	err = ks.Put(newKeyNickname, priv, passphrase)
 	if err != nil {
 		return nil, err
 	}
 	// return the newly generated key
-	return ks.Get(newKeyNickname)
+	return ks.Get(newKeyNickname, passphrase)
+}
+
+// getP2PKey decrypts the key stored under the provided nickname, asking for the passphrase if empty.
+func getP2PKey(ks *store.P2PKeyStore, nickname string, passphrase string) (crypto.PrivKey, error) {
+	if passphrase == "" {
+		var err error
+		passphrase, err = GetPassphrase()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ks.Get(nickname, passphrase)
+}
+
+func GetPassphrase() (string, error) {
+	fmt.Fprint(os.Stderr, "please provide the p2p key passphrase: ")
+	bzPassphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	if err != nil {
+		return "", err
+	}
+	return string(bzPassphrase), nil
+}
+
+func GetNewPassphrase() (string, error) {
+	var err error
+	var bzPassphrase []byte
+	for {
+		fmt.Fprint(os.Stderr, "please provide the p2p key new passphrase: ")
+		bzPassphrase, err = term.ReadPassword(int(os.Stdin.Fd()))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprint(os.Stderr, "\nenter the same passphrase again: ")
+		bzPassphraseConfirm, err := term.ReadPassword(int(os.Stdin.Fd()))
+		if err != nil {
+			return "", err
+		}
+		if bytes.Equal(bzPassphrase, bzPassphraseConfirm) {
+			break
+		}
+		fmt.Fprint(os.Stderr, "\npassphrase and confirmation mismatch.\n")
+	}
+	return string(bzPassphrase), nil
 }
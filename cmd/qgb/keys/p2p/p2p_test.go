@@ -1,33 +1,41 @@
 package p2p_test
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/common"
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/p2p"
-	"github.com/ipfs/boxo/keystore"
+	"github.com/celestiaorg/orchestrator-relayer/store"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	keystore2 "github.com/ipfs/boxo/keystore"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestGetP2PKeyOrGenerateNewOne(t *testing.T) {
 	tempDir := t.TempDir()
-	ks, err := keystore.NewFSKeystore(tempDir)
+	ks, err := store.NewP2PKeyStore(tempDir, keystore.LightScryptN, keystore.LightScryptP)
 	require.NoError(t, err)
 
 	nickname := "test"
+	passphrase := "123"
 	// test non-existing nickname
-	_, err = p2p.GetP2PKeyOrGenerateNewOne(ks, nickname)
+	_, err = p2p.GetP2PKeyOrGenerateNewOne(ks, nickname, passphrase)
 	// because the key is still not added
 	assert.Error(t, err)
 
 	// test empty nickname
-	priv, err := p2p.GetP2PKeyOrGenerateNewOne(ks, "")
+	priv, err := p2p.GetP2PKeyOrGenerateNewOne(ks, "", passphrase)
 	// should create a new key with nickname 0
 	assert.NoError(t, err)
 	assert.NotNil(t, priv)
 
 	// get the key with nickname 0
-	priv2, err := p2p.GetP2PKeyOrGenerateNewOne(ks, "0")
+	priv2, err := p2p.GetP2PKeyOrGenerateNewOne(ks, "0", passphrase)
 	assert.NoError(t, err)
 	assert.NotNil(t, priv2)
 	assert.Equal(t, priv, priv2)
@@ -35,16 +43,104 @@ func TestGetP2PKeyOrGenerateNewOne(t *testing.T) {
 	// put a new key
 	priv3, err := p2p.GenerateNewEd25519()
 	require.NoError(t, err)
-	err = ks.Put(nickname, priv3)
+	err = ks.Put(nickname, priv3, passphrase)
 	require.NoError(t, err)
-	priv4, err := p2p.GetP2PKeyOrGenerateNewOne(ks, nickname)
+	priv4, err := p2p.GetP2PKeyOrGenerateNewOne(ks, nickname, passphrase)
 	assert.NoError(t, err)
 	assert.NotNil(t, priv4)
 	assert.Equal(t, priv3, priv4)
 }
 
+func TestGetP2PKeyOrGenerateNewOnePlaintextKeys(t *testing.T) {
+	tempDir := t.TempDir()
+	legacy, err := keystore2.NewFSKeystore(tempDir)
+	require.NoError(t, err)
+	priv, err := p2p.GenerateNewEd25519()
+	require.NoError(t, err)
+	require.NoError(t, legacy.Put("legacy", priv))
+
+	ks, err := store.NewP2PKeyStore(tempDir, keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+
+	// a new key shouldn't be generated while the plaintext ones are not migrated
+	_, err = p2p.GetP2PKeyOrGenerateNewOne(ks, "", "123")
+	assert.ErrorIs(t, err, store.ErrPlaintextP2PKey)
+	_, err = p2p.GetP2PKeyOrGenerateNewOne(ks, "legacy", "123")
+	assert.ErrorIs(t, err, store.ErrPlaintextP2PKey)
+
+	_, err = ks.MigratePlaintextKeys("123")
+	require.NoError(t, err)
+	priv2, err := p2p.GetP2PKeyOrGenerateNewOne(ks, "", "123")
+	require.NoError(t, err)
+	assert.True(t, priv.Equals(priv2))
+}
+
 func TestGenerateNewEd25519(t *testing.T) {
 	priv, err := p2p.GenerateNewEd25519()
 	assert.NoError(t, err)
 	assert.NotNil(t, priv)
 }
+
+func TestExportImport(t *testing.T) {
+	home := t.TempDir()
+	run := func(args ...string) error {
+		cmd := p2p.Root("orchestrator")
+		cmd.SetArgs(append(args, "--"+base.FlagHome, home))
+		return cmd.Execute()
+	}
+	require.NoError(t, run("add", "key", "--"+base.FlagP2PPassphrase, "123"))
+
+	tests := []struct {
+		format     p2p.ExportFormat
+		importArgs func(exported string) []string
+		passphrase string
+	}{
+		{
+			format: p2p.ExportFormatProtobuf,
+			importArgs: func(exported string) []string {
+				key, err := os.ReadFile(exported)
+				require.NoError(t, err)
+				return []string{string(key), "--" + base.FlagP2PPassphrase, "456"}
+			},
+			passphrase: "456",
+		},
+		{
+			format: p2p.ExportFormatEncrypted,
+			importArgs: func(exported string) []string {
+				return []string{exported, "--" + base.FlagP2PPassphrase, "123"}
+			},
+			passphrase: "123",
+		},
+		{
+			format: p2p.ExportFormatEncrypted,
+			importArgs: func(exported string) []string {
+				return []string{exported, "--" + base.FlagP2PPassphrase, "123", "--" + p2p.FlagNewP2PPassphrase, "789"}
+			},
+			passphrase: "789",
+		},
+	}
+	for i, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			exported := filepath.Join(t.TempDir(), "p2p.key")
+			require.NoError(t, run(
+				"export", "key",
+				"--"+p2p.FlagExportFormat, string(tt.format),
+				"--"+base.FlagP2PPassphrase, "123",
+				"--"+common.FlagExportOutput, exported,
+				"--"+common.FlagExportYes,
+			))
+
+			nickname := fmt.Sprintf("imported%d", i)
+			args := append([]string{"import", nickname}, tt.importArgs(exported)...)
+			require.NoError(t, run(append(args, "--"+p2p.FlagImportFormat, string(tt.format))...))
+
+			ks, err := store.NewP2PKeyStore(filepath.Join(home, store.P2PKeyStorePath), keystore.LightScryptN, keystore.LightScryptP)
+			require.NoError(t, err)
+			priv, err := ks.Get("key", "123")
+			require.NoError(t, err)
+			imported, err := ks.Get(nickname, tt.passphrase)
+			require.NoError(t, err)
+			assert.True(t, priv.Equals(imported))
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package swarm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/common"
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	"github.com/celestiaorg/orchestrator-relayer/store"
+	"github.com/spf13/cobra"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+// swarmKeyPerms the swarm key file permissions, only readable by the owner.
+const swarmKeyPerms = 0o600
+
+var ErrSwarmKeyExists = errors.New("swarm key already exists. delete it first to replace it")
+
+func Root(serviceName string) *cobra.Command {
+	swarmCmd := &cobra.Command{
+		Use:          "swarm",
+		Short:        "QGB private network pre-shared key manager",
+		SilenceUsage: true,
+	}
+
+	swarmCmd.SetHelpCommand(&cobra.Command{})
+	swarmCmd.AddCommand(
+		Add(serviceName),
+		Import(serviceName),
+		Show(serviceName),
+		Delete(serviceName),
+	)
+
+	return swarmCmd
+}
+
+func Add(serviceName string) *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "add",
+		Short: "create a new private network pre-shared key",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseKeysConfigFlags(cmd, serviceName)
+			if err != nil {
+				return err
+			}
+
+			logger := tmlog.NewTMLogger(os.Stdout)
+
+			s, err := openStore(logger, config.home)
+			if err != nil {
+				return err
+			}
+			if store.Exists(s.SwarmKeyPath()) {
+				return ErrSwarmKeyExists
+			}
+
+			logger.Info("generating a new private network pre-shared key")
+
+			psk, err := p2p.GenerateSwarmKey()
+			if err != nil {
+				return err
+			}
+			err = os.WriteFile(s.SwarmKeyPath(), p2p.MarshalSwarmKey(psk), swarmKeyPerms)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("swarm key created successfully. share it with the other peers of the private network", "path", s.SwarmKeyPath())
+			return nil
+		},
+	}
+	return keysConfigFlags(&cmd, serviceName)
+}
+
+func Import(serviceName string) *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "import <swarm_key_file>",
+		Short: "import an existing private network pre-shared key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseKeysConfigFlags(cmd, serviceName)
+			if err != nil {
+				return err
+			}
+
+			logger := tmlog.NewTMLogger(os.Stdout)
+
+			s, err := openStore(logger, config.home)
+			if err != nil {
+				return err
+			}
+			if store.Exists(s.SwarmKeyPath()) {
+				return ErrSwarmKeyExists
+			}
+
+			psk, err := p2p.LoadSwarmKey(args[0])
+			if err != nil {
+				return err
+			}
+			err = os.WriteFile(s.SwarmKeyPath(), p2p.MarshalSwarmKey(psk), swarmKeyPerms)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("swarm key imported successfully", "path", s.SwarmKeyPath())
+			return nil
+		},
+	}
+	return keysConfigFlags(&cmd, serviceName)
+}
+
+func Show(serviceName string) *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "show",
+		Short: "print the private network pre-shared key to be shared with the other peers",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseKeysConfigFlags(cmd, serviceName)
+			if err != nil {
+				return err
+			}
+
+			logger := tmlog.NewTMLogger(os.Stderr)
+
+			s, err := openStore(logger, config.home)
+			if err != nil {
+				return err
+			}
+			psk, err := p2p.LoadSwarmKey(s.SwarmKeyPath())
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(string(p2p.MarshalSwarmKey(psk)))
+			return nil
+		},
+	}
+	return keysConfigFlags(&cmd, serviceName)
+}
+
+func Delete(serviceName string) *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "delete",
+		Short: "delete the private network pre-shared key from store",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseKeysConfigFlags(cmd, serviceName)
+			if err != nil {
+				return err
+			}
+
+			logger := tmlog.NewTMLogger(os.Stdout)
+
+			s, err := openStore(logger, config.home)
+			if err != nil {
+				return err
+			}
+			if !store.Exists(s.SwarmKeyPath()) {
+				logger.Info("no swarm key found", "path", s.SwarmKeyPath())
+				return nil
+			}
+
+			logger.Info("deleting private network pre-shared key", "path", s.SwarmKeyPath())
+
+			confirm := common.ConfirmDeletePrivateKey(logger)
+			if !confirm {
+				logger.Info("deletion of swarm key has been cancelled")
+				return nil
+			}
+
+			err = os.Remove(s.SwarmKeyPath())
+			if err != nil {
+				return err
+			}
+
+			logger.Info("swarm key deleted successfully")
+			return nil
+		},
+	}
+	return keysConfigFlags(&cmd, serviceName)
+}
+
+// openStore opens the store, initializing it if needed. The swarm key is stored
+// alongside the P2P keystore.
+func openStore(logger tmlog.Logger, home string) (*store.Store, error) {
+	initOptions := store.InitOptions{NeedP2PKeyStore: true}
+	isInit := store.IsInit(logger, home, initOptions)
+
+	// initialize the store if not initialized
+	if !isInit {
+		err := store.Init(logger, home, initOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the keystores don't need to be closed
+	return store.OpenStore(logger, home, store.OpenOptions{HasP2PKeyStore: true})
+}
@@ -2,21 +2,24 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"os"
 	"time"
 
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/common"
+	configcmd "github.com/celestiaorg/orchestrator-relayer/cmd/qgb/config"
 	evm2 "github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/evm"
 	"github.com/celestiaorg/orchestrator-relayer/p2p"
 	dssync "github.com/ipfs/go-datastore/sync"
 
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys"
+	storecmd "github.com/celestiaorg/orchestrator-relayer/cmd/qgb/store"
 	"github.com/celestiaorg/orchestrator-relayer/store"
 
 	"github.com/celestiaorg/orchestrator-relayer/helpers"
 	"github.com/celestiaorg/orchestrator-relayer/orchestrator"
 	"github.com/spf13/cobra"
-	tmlog "github.com/tendermint/tendermint/libs/log"
 )
 
 func Command() *cobra.Command {
@@ -31,6 +34,8 @@ func Command() *cobra.Command {
 		Start(),
 		Init(),
 		keys.Command(ServiceNameOrchestrator),
+		configcmd.Command(addOrchestratorFlags),
+		storecmd.Command(ServiceNameOrchestrator),
 	)
 
 	orchCmd.SetHelpCommand(&cobra.Command{})
@@ -49,7 +54,11 @@ func Start() *cobra.Command {
 				return err
 			}
 
-			logger := tmlog.NewTMLogger(os.Stdout)
+			logger, err := base.NewLogger(os.Stdout, config.Log)
+			if err != nil {
+				return err
+			}
+			p2pLogger := base.ModuleLogger(logger, base.LogModuleP2P)
 			logger.Debug("initializing orchestrator")
 
 			ctx, cancel := context.WithCancel(cmd.Context())
@@ -57,7 +66,7 @@ func Start() *cobra.Command {
 
 			stopFuncs := make([]func() error, 0)
 
-			tmQuerier, appQuerier, stops, err := common.NewTmAndAppQuerier(logger, config.coreRPC, config.coreGRPC)
+			tmQuerier, appQuerier, stops, err := common.NewTmAndAppQuerier(base.ModuleLogger(logger, base.LogModuleRPC), config.coreRPC, config.coreGRPC)
 			stopFuncs = append(stopFuncs, stops...)
 			if err != nil {
 				return err
@@ -65,7 +74,8 @@ func Start() *cobra.Command {
 
 			s, stops, err := common.OpenStore(logger, config.Home, store.OpenOptions{
 				HasDataStore:      true,
-				BadgerOptions:     store.DefaultBadgerOptions(config.Home),
+				Backend:           config.storeBackend,
+				BadgerOptions:     store.BadgerOptionsWithGC(config.Home, config.storeGC),
 				HasSignatureStore: false,
 				HasEVMKeyStore:    true,
 				HasP2PKeyStore:    true,
@@ -75,7 +85,7 @@ func Start() *cobra.Command {
 				return err
 			}
 
-			logger.Info("loading EVM account", "address", config.evmAccAddress)
+			logger.Info("loading EVM account", "evm_address", config.evmAccAddress)
 
 			acc, err := evm2.GetAccountFromStoreAndUnlockIt(s.EVMKeyStore, config.evmAccAddress, config.EVMPassphrase)
 			stopFuncs = append(stopFuncs, func() error { return s.EVMKeyStore.Lock(acc.Address) })
@@ -86,14 +96,55 @@ func Start() *cobra.Command {
 			// creating the data store
 			dataStore := dssync.MutexWrap(s.DataStore)
 
-			dht, err := common.CreateDHTAndWaitForPeers(ctx, logger, s.P2PKeyStore, config.p2pNickname, config.p2pListenAddr, config.bootstrappers, dataStore)
+			// the orchestrator always proves control of its EVM address so that it can participate
+			// in networks restricting the DHT servers to the validators.
+			authOpts := common.P2PAuthOptions{
+				EVMKeyStore: s.EVMKeyStore,
+				EVMAccount:  &acc,
+			}
+			if config.p2pAuthenticate {
+				authOpts.Gater = p2p.NewConnectionGater(appQuerier, config.p2pAllowlist, p2pLogger)
+			}
+			if config.p2pChainValidation {
+				authOpts.ChainView = p2p.NewChainView(appQuerier, tmQuerier, p2pLogger)
+			}
+
+			swarmKey, err := common.LoadSwarmKey(logger, s, config.p2pSwarmKey)
+			if err != nil {
+				return err
+			}
+			hostConfig := config.p2pHostConfig
+			hostConfig.SwarmKey = swarmKey
+
+			dht, err := common.CreateDHTAndWaitForPeers(ctx, p2pLogger, s.P2PKeyStore, config.p2pNickname, config.P2PPassphrase, config.p2pListenAddr, config.bootstrappers, dataStore, hostConfig, config.p2pVersions, authOpts)
 			if err != nil {
 				return err
 			}
 			stopFuncs = append(stopFuncs, func() error { return dht.Close() })
+			dht.ConfirmEncoding = config.confirmEncoding
+
+			// creating the gossipsub router used to propagate the confirms
+			ps, err := p2p.NewQgbPubSub(ctx, dht.Host(), p2pLogger)
+			if err != nil {
+				return err
+			}
+			stopFuncs = append(stopFuncs, func() error { return ps.Close() })
+			if authOpts.ChainView != nil {
+				err = ps.UseChainView(authOpts.ChainView)
+				if err != nil {
+					return err
+				}
+			}
+			ps.ConfirmEncoding = config.confirmEncoding
+			// joining the confirms mesh so that the confirms are forwarded to the peers that are not
+			// directly connected to the publisher
+			err = ps.Relay()
+			if err != nil {
+				return err
+			}
 
 			// creating the p2p querier
-			p2pQuerier := p2p.NewQuerier(dht, logger)
+			p2pQuerier := p2p.NewQuerier(dht, p2pLogger)
 			retrier := helpers.NewRetrier(logger, 6, time.Minute)
 
 			defer func() {
@@ -105,15 +156,29 @@ func Start() *cobra.Command {
 				}
 			}()
 
+			// pruning the confirms that are not needed anymore
+			pruner := p2p.Pruner{
+				DataStore:   s.DataStore,
+				NonceWindow: config.pruneWindow,
+				LatestNonce: appQuerier.QueryLatestAttestationNonce,
+				// the confirms inside the unbonding period are signed again by the orchestrators if missing
+				UnbondingNonce: func(ctx context.Context) (uint64, error) {
+					return orchestrator.UnbondingStartingNonce(ctx, appQuerier)
+				},
+				Logger: p2pLogger,
+			}
+			pruner.Start(ctx, config.pruneInterval)
+
 			// creating the broadcaster
 			broadcaster := orchestrator.NewBroadcaster(p2pQuerier.QgbDHT)
+			broadcaster.WithPubSub(ps, p2pLogger)
 			if err != nil {
 				return err
 			}
 
 			// creating the orchestrator
 			orch := orchestrator.New(
-				logger,
+				base.ModuleLogger(logger, base.LogModuleOrchestrator),
 				appQuerier,
 				tmQuerier,
 				p2pQuerier,
@@ -144,31 +209,44 @@ func Start() *cobra.Command {
 func Init() *cobra.Command {
 	cmd := cobra.Command{
 		Use:   "init",
-		Short: "Initialize the QGB orchestrator store. Passed flags have persisted effect.",
+		Short: "Initialize the QGB orchestrator store, and write the passed flags, along with the defaults, to the config file loaded on start",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			config, err := parseInitFlags(cmd)
 			if err != nil {
 				return err
 			}
 
-			logger := tmlog.NewTMLogger(os.Stdout)
+			logger, err := base.NewLogger(os.Stdout, config.log)
+			if err != nil {
+				return err
+			}
 
 			initOptions := store.InitOptions{
 				NeedDataStore:   true,
 				NeedEVMKeyStore: true,
 				NeedP2PKeyStore: true,
+				Backend:         config.storeBackend,
 			}
 			isInit := store.IsInit(logger, config.home, initOptions)
 			if isInit {
 				logger.Info("provided path is already initiated", "path", config.home)
-				return nil
+			} else {
+				err = store.Init(logger, config.home, initOptions)
+				if err != nil {
+					return err
+				}
 			}
 
-			err = store.Init(logger, config.home, initOptions)
+			// persist the passed flags, along with the defaults, so that they're loaded on start
+			err = base.WriteConfigFile(cmd, config.home)
 			if err != nil {
+				if errors.Is(err, base.ErrConfigFileExists) {
+					logger.Info("config file already exists, not overwriting it", "path", base.ConfigFilePath(config.home))
+					return nil
+				}
 				return err
 			}
-
+			logger.Info("config file written", "path", base.ConfigFilePath(config.home))
 			return nil
 		},
 	}
@@ -3,9 +3,15 @@ package orchestrator
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/celestiaorg/orchestrator-relayer/helpers"
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	"github.com/celestiaorg/orchestrator-relayer/store"
+	"github.com/celestiaorg/orchestrator-relayer/types"
 	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/spf13/cobra"
 )
 
@@ -33,10 +39,21 @@ func addOrchestratorFlags(cmd *cobra.Command) *cobra.Command {
 		panic(err)
 	}
 	cmd.Flags().String(base.FlagHome, homeDir, "The qgb orchestrator home directory")
-	cmd.Flags().String(base.FlagEVMPassphrase, "", "the evm account passphrase (if not specified as a flag, it will be asked interactively)")
+	base.AddEVMPassphraseFlags(cmd)
+	base.AddP2PPassphraseFlags(cmd)
 	base.AddP2PNicknameFlag(cmd)
 	base.AddP2PListenAddressFlag(cmd)
 	base.AddBootstrappersFlag(cmd)
+	base.AddP2PAuthFlags(cmd)
+	base.AddP2PChainValidationFlag(cmd)
+	base.AddP2PSwarmKeyFlag(cmd)
+	base.AddP2PHostFlags(cmd)
+	base.AddP2PProtocolVersionFlags(cmd)
+	base.AddP2PConfirmEncodingFlag(cmd)
+	base.AddStoreBackendFlag(cmd)
+	base.AddStoreGCFlags(cmd)
+	base.AddStorePruneFlags(cmd)
+	base.AddLogFlags(cmd)
 	return cmd
 }
 
@@ -46,6 +63,17 @@ type StartConfig struct {
 	evmAccAddress                string
 	bootstrappers, p2pListenAddr string
 	p2pNickname                  string
+	p2pAuthenticate              bool
+	p2pAllowlist                 []peer.ID
+	p2pChainValidation           bool
+	p2pSwarmKey                  string
+	p2pHostConfig                p2p.HostConfig
+	p2pVersions                  p2p.ProtocolVersions
+	confirmEncoding              types.ConfirmEncoding
+	storeBackend                 store.Backend
+	storeGC                      store.GCConfig
+	pruneWindow                  uint64
+	pruneInterval                time.Duration
 }
 
 func parseOrchestratorFlags(cmd *cobra.Command) (StartConfig, error) {
@@ -84,6 +112,54 @@ func parseOrchestratorFlags(cmd *cobra.Command) (StartConfig, error) {
 	if err != nil {
 		return StartConfig{}, err
 	}
+	p2pAuthenticate, err := cmd.Flags().GetBool(base.FlagP2PAuthenticate)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	p2pAllowlist, err := cmd.Flags().GetString(base.FlagP2PAllowlist)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	allowlist, err := helpers.ParsePeerIDs(p2pAllowlist)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	p2pChainValidation, err := cmd.Flags().GetBool(base.FlagP2PChainValidation)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	p2pSwarmKey, err := cmd.Flags().GetString(base.FlagP2PSwarmKey)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	p2pHostConfig, err := base.ParseP2PHostFlags(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	p2pVersions, err := base.ParseP2PProtocolVersions(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	confirmEncodingName, err := cmd.Flags().GetString(base.FlagP2PConfirmEncoding)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	confirmEncoding, err := types.ParseConfirmEncoding(confirmEncodingName)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	storeBackend, err := base.ParseStoreBackendFlag(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	storeGC, err := base.ParseStoreGCFlags(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	pruneWindow, pruneInterval, err := base.ParseStorePruneFlags(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
 	homeDir, err := cmd.Flags().GetString(base.FlagHome)
 	if err != nil {
 		return StartConfig{}, err
@@ -95,36 +171,55 @@ func parseOrchestratorFlags(cmd *cobra.Command) (StartConfig, error) {
 			return StartConfig{}, err
 		}
 	}
-	passphrase, err := cmd.Flags().GetString(base.FlagEVMPassphrase)
+	passphrase, err := base.ParseEVMPassphrase(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	p2pPassphrase, err := base.ParseP2PPassphrase(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	logConfig, err := base.ParseLogFlags(cmd)
 	if err != nil {
 		return StartConfig{}, err
 	}
 
 	return StartConfig{
-		evmAccAddress: evmAccAddr,
-		coreGRPC:      fmt.Sprintf("%s:%d", coreGRPCHost, coreGRPCPort),
-		coreRPC:       fmt.Sprintf("tcp://%s:%d", coreRPCHost, coreRPCPort),
-		bootstrappers: bootstrappers,
-		p2pNickname:   p2pNickname,
-		p2pListenAddr: p2pListenAddress,
+		evmAccAddress:      evmAccAddr,
+		coreGRPC:           fmt.Sprintf("%s:%d", coreGRPCHost, coreGRPCPort),
+		coreRPC:            fmt.Sprintf("tcp://%s:%d", coreRPCHost, coreRPCPort),
+		bootstrappers:      bootstrappers,
+		p2pNickname:        p2pNickname,
+		p2pAuthenticate:    p2pAuthenticate,
+		p2pAllowlist:       allowlist,
+		p2pChainValidation: p2pChainValidation,
+		p2pSwarmKey:        p2pSwarmKey,
+		p2pHostConfig:      p2pHostConfig,
+		p2pVersions:        p2pVersions,
+		confirmEncoding:    confirmEncoding,
+		storeBackend:       storeBackend,
+		storeGC:            storeGC,
+		pruneWindow:        pruneWindow,
+		pruneInterval:      pruneInterval,
+		p2pListenAddr:      p2pListenAddress,
 		Config: &base.Config{
 			Home:          homeDir,
 			EVMPassphrase: passphrase,
+			P2PPassphrase: p2pPassphrase,
+			Log:           logConfig,
 		},
 	}, nil
 }
 
+// addInitFlags adds the start flags to the init command, so that they're written to the config file.
 func addInitFlags(cmd *cobra.Command) *cobra.Command {
-	homeDir, err := base.DefaultServicePath(ServiceNameOrchestrator)
-	if err != nil {
-		panic(err)
-	}
-	cmd.Flags().String(base.FlagHome, homeDir, "The qgb orchestrator home directory")
-	return cmd
+	return addOrchestratorFlags(cmd)
 }
 
 type InitConfig struct {
-	home string
+	home         string
+	storeBackend store.Backend
+	log          base.LogConfig
 }
 
 func parseInitFlags(cmd *cobra.Command) (InitConfig, error) {
@@ -140,7 +235,19 @@ func parseInitFlags(cmd *cobra.Command) (InitConfig, error) {
 		}
 	}
 
+	storeBackend, err := base.ParseStoreBackendFlag(cmd)
+	if err != nil {
+		return InitConfig{}, err
+	}
+
+	logConfig, err := base.ParseLogFlags(cmd)
+	if err != nil {
+		return InitConfig{}, err
+	}
+
 	return InitConfig{
-		home: homeDir,
+		home:         homeDir,
+		storeBackend: storeBackend,
+		log:          logConfig,
 	}, nil
 }
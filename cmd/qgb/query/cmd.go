@@ -12,13 +12,22 @@ import (
 	common2 "github.com/ethereum/go-ethereum/common"
 
 	celestiatypes "github.com/celestiaorg/celestia-app/x/qgb/types"
+	"github.com/celestiaorg/orchestrator-relayer/archive"
+	"github.com/celestiaorg/orchestrator-relayer/auditor"
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/common"
+	"github.com/celestiaorg/orchestrator-relayer/evm"
 	"github.com/celestiaorg/orchestrator-relayer/p2p"
 	"github.com/celestiaorg/orchestrator-relayer/rpc"
+	"github.com/celestiaorg/orchestrator-relayer/store"
 	"github.com/celestiaorg/orchestrator-relayer/types"
+	wrapper "github.com/celestiaorg/quantum-gravity-bridge/wrappers/QuantumGravityBridge.sol"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
 	ds "github.com/ipfs/go-datastore"
 	dssync "github.com/ipfs/go-datastore/sync"
 	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -36,6 +45,9 @@ func Command() *cobra.Command {
 	queryCmd.AddCommand(
 		Signers(),
 		Signature(),
+		Proof(),
+		Archive(),
+		Status(),
 	)
 
 	queryCmd.SetHelpCommand(&cobra.Command{})
@@ -58,7 +70,10 @@ func Signers() *cobra.Command {
 			}
 
 			// creating the logger
-			logger := tmlog.NewTMLogger(os.Stdout)
+			logger, err := base.NewLogger(os.Stdout, config.log)
+			if err != nil {
+				return err
+			}
 			logger.Debug("initializing queriers")
 
 			ctx, cancel := context.WithCancel(cmd.Context())
@@ -81,40 +96,12 @@ func Signers() *cobra.Command {
 				return err
 			}
 
-			// creating the host
-			h, err := libp2p.New()
-			if err != nil {
-				return err
-			}
-			addrInfo, err := peer.AddrInfoFromString(config.targetNode)
-			if err != nil {
-				return err
-			}
-			for i := 0; i < 5; i++ {
-				logger.Debug("connecting to target node...")
-				err := h.Connect(ctx, *addrInfo)
-				if err != nil {
-					logger.Error("couldn't connect to target node", "err", err.Error())
-				}
-				if err == nil {
-					logger.Debug("connected to target node")
-					break
-				}
-				time.Sleep(5 * time.Second)
-			}
-
-			// creating the data store
-			dataStore := dssync.MutexWrap(ds.NewMapDatastore())
-
-			// creating the dht
-			dht, err := p2p.NewQgbDHT(cmd.Context(), h, dataStore, []peer.AddrInfo{}, logger)
+			p2pQuerier, stops, err := newP2PQuerier(ctx, logger, config.targetNode)
+			stopFuncs = append(stopFuncs, stops...)
 			if err != nil {
 				return err
 			}
 
-			// creating the p2p querier
-			p2pQuerier := p2p.NewQuerier(dht, logger)
-
 			nonce, err := parseNonce(ctx, appQuerier, args[0])
 			if err != nil {
 				return err
@@ -157,38 +144,50 @@ func getSignaturesAndPrintThem(
 ) error {
 	logger.Info("getting signatures for nonce", "nonce", nonce)
 
-	lastValset, err := appQuerier.QueryLastValsetBeforeNonce(ctx, nonce)
+	qOutput, err := querySignatures(ctx, appQuerier, tmQuerier, p2pQuerier, nonce)
 	if err != nil {
 		return err
 	}
+	if outputFile == "" {
+		printConfirms(logger, qOutput)
+		return nil
+	}
+	return writeConfirmsToJSONFile(logger, qOutput, outputFile)
+}
+
+// querySignatures queries the confirms of the attestation having the provided nonce from the P2P network,
+// and checks them against the last valset before it.
+func querySignatures(
+	ctx context.Context,
+	appQuerier *rpc.AppQuerier,
+	tmQuerier *rpc.TmQuerier,
+	p2pQuerier *p2p.Querier,
+	nonce uint64,
+) (queryOutput, error) {
+	lastValset, err := appQuerier.QueryLastValsetBeforeNonce(ctx, nonce)
+	if err != nil {
+		return queryOutput{}, err
+	}
 
 	att, err := appQuerier.QueryAttestationByNonce(ctx, nonce)
 	if err != nil {
-		return err
+		return queryOutput{}, err
 	}
 	if att == nil {
-		return celestiatypes.ErrAttestationNotFound
+		return queryOutput{}, celestiatypes.ErrAttestationNotFound
 	}
 
 	switch castedAtt := att.(type) {
 	case *celestiatypes.Valset:
 		signBytes, err := castedAtt.SignBytes()
 		if err != nil {
-			return err
+			return queryOutput{}, err
 		}
 		confirms, err := p2pQuerier.QueryValsetConfirms(ctx, nonce, *lastValset, signBytes.Hex())
 		if err != nil {
-			return err
-		}
-		qOutput := toQueryOutput(toValsetConfirmsMap(confirms), nonce, *lastValset)
-		if outputFile == "" {
-			printConfirms(logger, qOutput)
-		} else {
-			err := writeConfirmsToJSONFile(logger, qOutput, outputFile)
-			if err != nil {
-				return err
-			}
+			return queryOutput{}, err
 		}
+		return toQueryOutput(toValsetConfirmsMap(confirms), nonce, *lastValset), nil
 	case *celestiatypes.DataCommitment:
 		commitment, err := tmQuerier.QueryCommitment(
 			ctx,
@@ -196,26 +195,89 @@ func getSignaturesAndPrintThem(
 			castedAtt.EndBlock,
 		)
 		if err != nil {
-			return err
+			return queryOutput{}, err
 		}
 		dataRootHash := types.DataCommitmentTupleRootSignBytes(big.NewInt(int64(castedAtt.Nonce)), commitment)
 		confirms, err := p2pQuerier.QueryDataCommitmentConfirms(ctx, *lastValset, nonce, dataRootHash.Hex())
 		if err != nil {
-			return err
-		}
-		qOutput := toQueryOutput(toDataCommitmentConfirmsMap(confirms), nonce, *lastValset)
-		if outputFile == "" {
-			printConfirms(logger, qOutput)
-		} else {
-			err := writeConfirmsToJSONFile(logger, qOutput, outputFile)
-			if err != nil {
-				return err
-			}
+			return queryOutput{}, err
 		}
+		return toQueryOutput(toDataCommitmentConfirmsMap(confirms), nonce, *lastValset), nil
 	default:
-		return errors.Wrap(types.ErrUnknownAttestationType, strconv.FormatUint(nonce, 10))
+		return queryOutput{}, errors.Wrap(types.ErrUnknownAttestationType, strconv.FormatUint(nonce, 10))
 	}
-	return nil
+}
+
+// newP2PQuerier connects to the target node, then creates a P2P querier using it to query the DHT and
+// subscribing to the gossiped confirms. The returned stop functions should be called even if an error is returned.
+func newP2PQuerier(ctx context.Context, logger tmlog.Logger, targetNode string) (*p2p.Querier, []func() error, error) {
+	stopFuncs := make([]func() error, 0, 1)
+
+	// creating the host
+	h, err := libp2p.New()
+	if err != nil {
+		return nil, stopFuncs, err
+	}
+	addrInfo, err := peer.AddrInfoFromString(targetNode)
+	if err != nil {
+		return nil, stopFuncs, err
+	}
+	for i := 0; i < 5; i++ {
+		logger.Debug("connecting to target node...")
+		err := h.Connect(ctx, *addrInfo)
+		if err != nil {
+			logger.Error("couldn't connect to target node", "err", err.Error())
+		}
+		if err == nil {
+			logger.Debug("connected to target node")
+			break
+		}
+		time.Sleep(5 * time.Second)
+	}
+
+	// creating the data store
+	dataStore := dssync.MutexWrap(ds.NewMapDatastore())
+
+	// creating the dht
+	dht, err := newQueryDHT(ctx, logger, h, dataStore, addrInfo.ID)
+	if err != nil {
+		return nil, stopFuncs, err
+	}
+
+	// subscribing to the gossiped confirms
+	ps, err := p2p.NewQgbPubSub(ctx, h, logger)
+	if err != nil {
+		return nil, stopFuncs, err
+	}
+	stopFuncs = append(stopFuncs, func() error { return ps.Close() })
+	err = ps.Subscribe(ctx)
+	if err != nil {
+		return nil, stopFuncs, err
+	}
+
+	// creating the p2p querier
+	p2pQuerier := p2p.NewQuerier(dht, logger)
+	p2pQuerier.WithPubSub(ps)
+	return p2pQuerier, stopFuncs, nil
+}
+
+// newQueryDHT creates a DHT speaking all the supported protocol versions, so that any network can be queried,
+// then logs the protocol version spoken by the target node.
+func newQueryDHT(ctx context.Context, logger tmlog.Logger, h host.Host, dataStore ds.Batching, targetNode peer.ID) (*p2p.QgbDHT, error) {
+	dht, err := p2p.NewVersionedQgbDHT(ctx, h, dataStore, []peer.AddrInfo{}, logger, p2p.ProtocolVersions{
+		Preferred: p2p.DefaultProtocolVersion,
+		Fallbacks: p2p.SupportedProtocolVersions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	version, ok := dht.PeerProtocolVersion(targetNode)
+	if !ok {
+		logger.Info("target node protocol version unknown", "peer", targetNode.String())
+	} else {
+		logger.Info("target node protocol version", "peer", targetNode.String(), "protocol_version", version.Name)
+	}
+	return dht, nil
 }
 
 func parseNonce(ctx context.Context, querier *rpc.AppQuerier, nonce string) (uint64, error) {
@@ -341,7 +403,10 @@ func Signature() *cobra.Command {
 			}
 
 			// creating the logger
-			logger := tmlog.NewTMLogger(os.Stdout)
+			logger, err := base.NewLogger(os.Stdout, config.log)
+			if err != nil {
+				return err
+			}
 			logger.Debug("initializing queriers")
 
 			ctx, cancel := context.WithCancel(cmd.Context())
@@ -364,40 +429,12 @@ func Signature() *cobra.Command {
 				return err
 			}
 
-			// creating the host
-			h, err := libp2p.New()
-			if err != nil {
-				return err
-			}
-			addrInfo, err := peer.AddrInfoFromString(config.targetNode)
-			if err != nil {
-				return err
-			}
-			for i := 0; i < 5; i++ {
-				logger.Debug("connecting to target node...")
-				err := h.Connect(ctx, *addrInfo)
-				if err != nil {
-					logger.Error("couldn't connect to target node", "err", err.Error())
-				}
-				if err == nil {
-					logger.Debug("connected to target node")
-					break
-				}
-				time.Sleep(5 * time.Second)
-			}
-
-			// creating the data store
-			dataStore := dssync.MutexWrap(ds.NewMapDatastore())
-
-			// creating the dht
-			dht, err := p2p.NewQgbDHT(cmd.Context(), h, dataStore, []peer.AddrInfo{}, logger)
+			p2pQuerier, stops, err := newP2PQuerier(ctx, logger, config.targetNode)
+			stopFuncs = append(stopFuncs, stops...)
 			if err != nil {
 				return err
 			}
 
-			// creating the p2p querier
-			p2pQuerier := p2p.NewQuerier(dht, logger)
-
 			nonce, err := parseNonce(ctx, appQuerier, args[0])
 			if err != nil {
 				return err
@@ -429,7 +466,7 @@ func getSignatureAndPrintIt(
 	evmAddress string,
 	nonce uint64,
 ) error {
-	logger.Info("getting signature for address and nonce", "nonce", nonce, "evm_account", evmAddress)
+	logger.Info("getting signature for address and nonce", "nonce", nonce, "evm_address", evmAddress)
 
 	att, err := appQuerier.QueryAttestationByNonce(ctx, nonce)
 	if err != nil {
@@ -450,9 +487,9 @@ func getSignatureAndPrintIt(
 			return err
 		}
 		if confirm == nil {
-			logger.Info("couldn't find orchestrator signature", "nonce", nonce, "evm_account", evmAddress)
+			logger.Info("couldn't find orchestrator signature", "nonce", nonce, "evm_address", evmAddress)
 		} else {
-			logger.Info("found orchestrator signature", "nonce", nonce, "evm_account", evmAddress, "signature", confirm.Signature)
+			logger.Info("found orchestrator signature", "nonce", nonce, "evm_address", evmAddress, "signature", confirm.Signature)
 		}
 	case *celestiatypes.DataCommitment:
 		commitment, err := tmQuerier.QueryCommitment(
@@ -469,12 +506,622 @@ func getSignatureAndPrintIt(
 			return err
 		}
 		if confirm == nil {
-			logger.Info("couldn't find orchestrator signature", "nonce", nonce, "evm_account", evmAddress)
+			logger.Info("couldn't find orchestrator signature", "nonce", nonce, "evm_address", evmAddress)
 		} else {
-			logger.Info("found orchestrator signature", "nonce", nonce, "evm_account", evmAddress, "signature", confirm.Signature)
+			logger.Info("found orchestrator signature", "nonce", nonce, "evm_address", evmAddress, "signature", confirm.Signature)
 		}
 	default:
 		return errors.Wrap(types.ErrUnknownAttestationType, strconv.FormatUint(nonce, 10))
 	}
 	return nil
 }
+
+func Proof() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "proof <height>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Queries the data root inclusion proof of a Celestia height to the QGB contract",
+		Long: "Queries the data root inclusion proof of a Celestia height to the QGB contract. The command finds the" +
+			" data commitment covering the provided height, and outputs the data root tuple root nonce, the data" +
+			" root tuple and the binary Merkle proof in the format expected by the contract `verifyAttestation` method." +
+			" If the contract address is specified, the proof will also be verified against the contract.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseProofFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			height, err := strconv.ParseUint(args[0], 10, 0)
+			if err != nil {
+				return err
+			}
+
+			// creating the logger.
+			// logging to stderr so that the proof printed to stdout can be piped.
+			logger, err := base.NewLogger(os.Stderr, config.log)
+			if err != nil {
+				return err
+			}
+			logger.Debug("initializing queriers")
+
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			stopFuncs := make([]func() error, 0, 1)
+			defer func() {
+				for _, f := range stopFuncs {
+					err := f()
+					if err != nil {
+						logger.Error(err.Error())
+					}
+				}
+			}()
+
+			// create tm querier and app querier
+			tmQuerier, appQuerier, stops, err := common.NewTmAndAppQuerier(logger, config.coreRPC, config.coreGRPC)
+			stopFuncs = append(stopFuncs, stops...)
+			if err != nil {
+				return err
+			}
+
+			dc, err := appQuerier.QueryDataCommitmentForHeight(ctx, height)
+			if err != nil {
+				return err
+			}
+			logger.Info("found data commitment covering height", "height", height, "nonce", dc.Nonce, "begin_block", dc.BeginBlock, "end_block", dc.EndBlock)
+
+			dataRoot, err := tmQuerier.QueryDataRoot(ctx, height)
+			if err != nil {
+				return err
+			}
+			proof, err := tmQuerier.QueryDataRootInclusionProof(ctx, height, dc.BeginBlock, dc.EndBlock)
+			if err != nil {
+				return err
+			}
+			inclusionProof, err := types.NewDataRootTupleInclusionProof(dc.Nonce, height, dataRoot, *proof)
+			if err != nil {
+				return err
+			}
+
+			output := proofOutput{DataRootTupleInclusionProof: *inclusionProof}
+			if config.contractAddr != "" {
+				verified, err := verifyAttestation(ctx, logger, config.evmRPC, common2.HexToAddress(config.contractAddr), *inclusionProof)
+				if err != nil {
+					return err
+				}
+				output.Verified = &verified
+			}
+
+			return writeProof(logger, output, config.outputFile)
+		},
+	}
+	return addProofFlags(command)
+}
+
+type proofOutput struct {
+	types.DataRootTupleInclusionProof
+	// Verified is set when the proof was verified against the QGB contract.
+	Verified *bool `json:"verified,omitempty"`
+}
+
+// verifyAttestation calls the QGB contract `verifyAttestation` method to check if the
+// provided proof is valid.
+func verifyAttestation(
+	ctx context.Context,
+	logger tmlog.Logger,
+	evmRPC string,
+	contractAddr common2.Address,
+	inclusionProof types.DataRootTupleInclusionProof,
+) (bool, error) {
+	ethClient, err := ethclient.Dial(evmRPC)
+	if err != nil {
+		return false, err
+	}
+	defer ethClient.Close()
+	qgbWrapper, err := wrapper.NewQuantumGravityBridge(contractAddr, ethClient)
+	if err != nil {
+		return false, err
+	}
+	evmClient := evm.NewClient(logger, qgbWrapper, nil, nil, evmRPC, evm.DefaultEVMGasLimit)
+
+	lastNonce, err := evmClient.StateLastEventNonce(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return false, err
+	}
+	if lastNonce < inclusionProof.TupleRootNonce {
+		logger.Info("data root tuple root not relayed to the contract yet", "nonce", inclusionProof.TupleRootNonce, "contract_nonce", lastNonce)
+		return false, nil
+	}
+
+	verified, err := evmClient.VerifyAttestation(
+		&bind.CallOpts{Context: ctx},
+		inclusionProof.TupleRootNonce,
+		inclusionProof.WrapperTuple(),
+		inclusionProof.WrapperProof(),
+	)
+	if err != nil {
+		return false, err
+	}
+	logger.Info("verified proof against the contract", "nonce", inclusionProof.TupleRootNonce, "verified", verified)
+	return verified, nil
+}
+
+func writeProof(logger tmlog.Logger, output proofOutput, outputFile string) error {
+	if outputFile == "" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output)
+	}
+
+	logger.Info("writing proof json file", "path", outputFile)
+	file, err := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) {
+		err := file.Close()
+		if err != nil {
+			logger.Error("failed to close file", "err", err.Error())
+		}
+	}(file)
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(output)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("output written to file successfully", "path", outputFile)
+	return nil
+}
+
+func Status() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "status",
+		Args:  cobra.ExactArgs(0),
+		Short: "Shows the QGB contract relaying progress compared to Celestia",
+		Long: "Shows the QGB contract relaying progress compared to Celestia: the latest Celestia attestation nonce," +
+			" the contract nonce, the lag between them in nonces and in Celestia blocks, the next attestation to relay," +
+			" and the age of the last relay event. If a P2P node is specified, the voting power that signed the next" +
+			" attestation is queried from the DHT to tell whether it is relayable.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseStatusFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			// creating the logger.
+			// logging to stderr so that the status printed to stdout can be piped.
+			logger, err := base.NewLogger(os.Stderr, config.log)
+			if err != nil {
+				return err
+			}
+			logger.Debug("initializing queriers")
+
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			stopFuncs := make([]func() error, 0, 1)
+			defer func() {
+				for _, f := range stopFuncs {
+					err := f()
+					if err != nil {
+						logger.Error(err.Error())
+					}
+				}
+			}()
+
+			// create tm querier and app querier
+			tmQuerier, appQuerier, stops, err := common.NewTmAndAppQuerier(logger, config.coreRPC, config.coreGRPC)
+			stopFuncs = append(stopFuncs, stops...)
+			if err != nil {
+				return err
+			}
+
+			// connecting to the QGB contract
+			ethClient, err := ethclient.Dial(config.evmRPC)
+			if err != nil {
+				return err
+			}
+			defer ethClient.Close()
+			qgbWrapper, err := wrapper.NewQuantumGravityBridge(config.contractAddr, ethClient)
+			if err != nil {
+				return err
+			}
+			evmClient := evm.NewClient(logger, qgbWrapper, nil, nil, config.evmRPC, evm.DefaultEVMGasLimit)
+
+			output, err := queryStatus(ctx, logger, appQuerier, tmQuerier, evmClient)
+			if err != nil {
+				return err
+			}
+
+			lastRelay, err := queryLastRelay(ctx, evmClient, ethClient, output.ContractNonce, config.evmStartBlock, config.evmLookbackBlocks, evm.DefaultEventsFilterWindow)
+			if err != nil {
+				return err
+			}
+			if lastRelay == nil {
+				logger.Info(
+					"no relay event found for the contract nonce. it is either the contract initial nonce, or was relayed before the searched blocks. use --"+FlagEVMLookbackBlocks+" to search further back",
+					"nonce", output.ContractNonce,
+					"start_block", config.evmStartBlock,
+					"lookback_blocks", config.evmLookbackBlocks,
+				)
+			}
+			output.LastRelay = lastRelay
+
+			switch {
+			case output.NextAttestation == nil:
+				logger.Info("the contract is up to date with Celestia", "nonce", output.ContractNonce)
+			case config.targetNode == "":
+				logger.Info("no P2P node specified, not checking whether the next attestation is relayable", "flag", FlagP2PNode)
+			default:
+				p2pQuerier, stops, err := newP2PQuerier(ctx, logger, config.targetNode)
+				stopFuncs = append(stopFuncs, stops...)
+				if err != nil {
+					return err
+				}
+				err = queryNextAttestationSignatures(ctx, appQuerier, tmQuerier, p2pQuerier, output.NextAttestation)
+				if err != nil {
+					return err
+				}
+			}
+
+			return writeStatus(logger, output, config.outputFile)
+		},
+	}
+	return addStatusFlags(command)
+}
+
+type statusOutput struct {
+	// LatestNonce the latest attestation nonce in Celestia.
+	LatestNonce uint64 `json:"latest_nonce"`
+	// ContractNonce the nonce of the last attestation relayed to the QGB contract.
+	ContractNonce uint64 `json:"contract_nonce"`
+	NoncesLag     uint64 `json:"nonces_lag"`
+	// CelestiaHeight the latest Celestia height.
+	CelestiaHeight uint64 `json:"celestia_height"`
+	// LastRelayedHeight the end block of the last data commitment relayed to the QGB contract,
+	// i.e. the last Celestia block that can be proven using the contract.
+	LastRelayedHeight uint64 `json:"last_relayed_height"`
+	BlocksLag         uint64 `json:"blocks_lag"`
+	// NextAttestation the next attestation to relay. Nil if the contract is up to date.
+	NextAttestation *nextAttestationStatus `json:"next_attestation,omitempty"`
+	// LastRelay the event emitted when relaying the contract nonce. Nil if it was not found.
+	LastRelay *lastRelayStatus `json:"last_relay,omitempty"`
+}
+
+type nextAttestationStatus struct {
+	Nonce uint64 `json:"nonce"`
+	Type  string `json:"type"`
+	// Height the valset height.
+	Height uint64 `json:"height,omitempty"`
+	// BeginBlock and EndBlock the data commitment range.
+	BeginBlock uint64 `json:"begin_block,omitempty"`
+	EndBlock   uint64 `json:"end_block,omitempty"`
+	// The following are set when the confirms are queried from the DHT.
+	MajorityThreshold *uint64 `json:"majority_threshold,omitempty"`
+	SignedPower       *uint64 `json:"signed_power,omitempty"`
+	Relayable         *bool   `json:"relayable,omitempty"`
+}
+
+type lastRelayStatus struct {
+	Nonce    uint64    `json:"nonce"`
+	Type     string    `json:"type"`
+	EVMBlock uint64    `json:"evm_block"`
+	TxHash   string    `json:"tx_hash"`
+	Time     time.Time `json:"time"`
+	Age      string    `json:"age"`
+}
+
+// queryStatus compares the QGB contract nonce to the Celestia state, and queries the next attestation to relay.
+func queryStatus(
+	ctx context.Context,
+	logger tmlog.Logger,
+	appQuerier *rpc.AppQuerier,
+	tmQuerier *rpc.TmQuerier,
+	evmClient *evm.Client,
+) (statusOutput, error) {
+	latestNonce, err := appQuerier.QueryLatestAttestationNonce(ctx)
+	if err != nil {
+		return statusOutput{}, err
+	}
+	contractNonce, err := evmClient.StateLastEventNonce(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return statusOutput{}, err
+	}
+	height, err := tmQuerier.QueryHeight(ctx)
+	if err != nil {
+		return statusOutput{}, err
+	}
+	lastRelayedHeight, err := lastRelayedDataCommitmentEndBlock(ctx, logger, appQuerier, contractNonce)
+	if err != nil {
+		return statusOutput{}, err
+	}
+
+	output := statusOutput{
+		LatestNonce:       latestNonce,
+		ContractNonce:     contractNonce,
+		NoncesLag:         lag(latestNonce, contractNonce),
+		CelestiaHeight:    uint64(height),
+		LastRelayedHeight: lastRelayedHeight,
+		BlocksLag:         lag(uint64(height), lastRelayedHeight),
+	}
+	if contractNonce >= latestNonce {
+		return output, nil
+	}
+
+	nextNonce := contractNonce + 1
+	att, err := appQuerier.QueryAttestationByNonce(ctx, nextNonce)
+	if err != nil {
+		return statusOutput{}, err
+	}
+	if att == nil {
+		return statusOutput{}, errors.Wrap(celestiatypes.ErrAttestationNotFound, strconv.FormatUint(nextNonce, 10))
+	}
+	switch castedAtt := att.(type) {
+	case *celestiatypes.Valset:
+		output.NextAttestation = &nextAttestationStatus{
+			Nonce:  nextNonce,
+			Type:   auditor.AttestationTypeValset,
+			Height: castedAtt.Height,
+		}
+	case *celestiatypes.DataCommitment:
+		output.NextAttestation = &nextAttestationStatus{
+			Nonce:      nextNonce,
+			Type:       auditor.AttestationTypeDataCommitment,
+			BeginBlock: castedAtt.BeginBlock,
+			EndBlock:   castedAtt.EndBlock,
+		}
+	default:
+		return statusOutput{}, errors.Wrap(types.ErrUnknownAttestationType, strconv.FormatUint(nextNonce, 10))
+	}
+	return output, nil
+}
+
+// queryNextAttestationSignatures queries the confirms of the next attestation from the DHT, and sets the
+// voting power that signed it and whether it is relayable.
+func queryNextAttestationSignatures(
+	ctx context.Context,
+	appQuerier *rpc.AppQuerier,
+	tmQuerier *rpc.TmQuerier,
+	p2pQuerier *p2p.Querier,
+	next *nextAttestationStatus,
+) error {
+	signatures, err := querySignatures(ctx, appQuerier, tmQuerier, p2pQuerier, next.Nonce)
+	if err != nil {
+		return err
+	}
+	next.MajorityThreshold = &signatures.MajorityThreshold
+	next.SignedPower = &signatures.CurrentThreshold
+	next.Relayable = &signatures.CanRelay
+	return nil
+}
+
+// lastRelayedDataCommitmentEndBlock returns the end block of the last data commitment whose nonce is lower
+// or equal to the provided contract nonce. Returns 0 if no data commitment was found, e.g. if they were pruned
+// from the Celestia state.
+func lastRelayedDataCommitmentEndBlock(ctx context.Context, logger tmlog.Logger, appQuerier *rpc.AppQuerier, contractNonce uint64) (uint64, error) {
+	for nonce := contractNonce; nonce > 0; nonce-- {
+		att, err := appQuerier.QueryAttestationByNonce(ctx, nonce)
+		if err != nil {
+			return 0, err
+		}
+		if att == nil {
+			logger.Info("attestation not found in Celestia state, the last relayed height is unknown", "nonce", nonce)
+			return 0, nil
+		}
+		if dc, ok := att.(*celestiatypes.DataCommitment); ok {
+			return dc.EndBlock, nil
+		}
+	}
+	return 0, nil
+}
+
+// queryLastRelay finds the event emitted when the provided nonce was relayed to the QGB contract, searching
+// the contract events backwards from the latest block down to the start block, by windows of windowSize
+// blocks, so that the RPC providers limiting the blocks range of `eth_getLogs` are supported.
+// At most lookbackBlocks blocks are searched, so that a missing event doesn't walk the chain back to genesis.
+// Returns nil if no event was found.
+func queryLastRelay(
+	ctx context.Context,
+	evmClient *evm.Client,
+	backend bind.ContractBackend,
+	nonce uint64,
+	startBlock uint64,
+	lookbackBlocks uint64,
+	windowSize uint64,
+) (*lastRelayStatus, error) {
+	latestBlock, err := evm.LatestBlockNumber(ctx, backend)
+	if err != nil {
+		return nil, err
+	}
+	if latestBlock < startBlock {
+		return nil, nil
+	}
+	if latestBlock-startBlock >= lookbackBlocks {
+		startBlock = latestBlock - lookbackBlocks + 1
+	}
+	nonces := []*big.Int{big.NewInt(int64(nonce))}
+
+	var relay *lastRelayStatus
+	for windowEnd := latestBlock; relay == nil; {
+		windowStart := startBlock
+		if windowEnd-startBlock >= windowSize {
+			windowStart = windowEnd - windowSize + 1
+		}
+		end := windowEnd
+		filterOpts := &bind.FilterOpts{Start: windowStart, End: &end, Context: ctx}
+
+		dcEvents, err := evmClient.FilterDataRootTupleRootEvents(filterOpts, nonces)
+		if err != nil {
+			return nil, err
+		}
+		vsEvents, err := evmClient.FilterValidatorSetUpdatedEvents(filterOpts, nonces)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case len(dcEvents) != 0:
+			event := dcEvents[len(dcEvents)-1]
+			relay = &lastRelayStatus{
+				Nonce:    nonce,
+				Type:     auditor.AttestationTypeDataCommitment,
+				EVMBlock: event.Raw.BlockNumber,
+				TxHash:   event.Raw.TxHash.Hex(),
+			}
+		case len(vsEvents) != 0:
+			event := vsEvents[len(vsEvents)-1]
+			relay = &lastRelayStatus{
+				Nonce:    nonce,
+				Type:     auditor.AttestationTypeValset,
+				EVMBlock: event.Raw.BlockNumber,
+				TxHash:   event.Raw.TxHash.Hex(),
+			}
+		case windowStart == startBlock:
+			return nil, nil
+		default:
+			windowEnd = windowStart - 1
+		}
+	}
+
+	header, err := backend.HeaderByNumber(ctx, new(big.Int).SetUint64(relay.EVMBlock))
+	if err != nil {
+		return nil, err
+	}
+	relay.Time = time.Unix(int64(header.Time), 0).UTC()
+	relay.Age = time.Since(relay.Time).Round(time.Second).String()
+	return relay, nil
+}
+
+// lag returns how far the current value is behind the target one, or 0 if it is not behind.
+func lag(target uint64, current uint64) uint64 {
+	if current >= target {
+		return 0
+	}
+	return target - current
+}
+
+func writeStatus(logger tmlog.Logger, output statusOutput, outputFile string) error {
+	if outputFile == "" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output)
+	}
+
+	logger.Info("writing status json file", "path", outputFile)
+	file, err := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) {
+		err := file.Close()
+		if err != nil {
+			logger.Error("failed to close file", "err", err.Error())
+		}
+	}(file)
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(output)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("output written to file successfully", "path", outputFile)
+	return nil
+}
+
+func Archive() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "archive",
+		Args:  cobra.ExactArgs(0),
+		Short: "Lists the confirms archived by a relayer",
+		Long: "Lists the confirms archived by a relayer, i.e. the confirms it relayed to the QGB contract, by nonce" +
+			" range, signer EVM address and digest. The archive is queried from the relayer API, or read from" +
+			" the relayer store if its home directory is specified.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseArchiveFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			// logging to stderr so that the confirms printed to stdout can be piped.
+			logger, err := base.NewLogger(os.Stderr, config.log)
+			if err != nil {
+				return err
+			}
+
+			var page archive.ListResponse
+			if config.home != "" {
+				page, err = listArchivedConfirmsFromStore(cmd.Context(), logger, config.home, config.storeBackend, config.filter)
+			} else {
+				logger.Debug("querying the signature archive api", "url", config.archiveURL)
+				page, err = archive.NewClient(config.archiveURL).ListPage(cmd.Context(), config.filter)
+			}
+			if err != nil {
+				return err
+			}
+			if page.NextNonce != 0 {
+				logger.Info("more confirms are archived. list them using --"+FlagArchiveFromNonce, "next_nonce", page.NextNonce)
+			}
+
+			return writeArchivedConfirms(logger, page, config.outputFile)
+		},
+	}
+	return addArchiveFlags(command)
+}
+
+// listArchivedConfirmsFromStore lists the archived confirms from the relayer store under the provided home.
+// The store is opened read-only.
+func listArchivedConfirmsFromStore(ctx context.Context, logger tmlog.Logger, home string, backend store.Backend, filter archive.Filter) (archive.ListResponse, error) {
+	badgerOptions := store.DefaultBadgerOptions(home)
+	badgerOptions.Options = badgerOptions.Options.WithReadOnly(true)
+	openOptions := store.OpenOptions{
+		HasSignatureStore: true,
+		Backend:           backend,
+		BadgerOptions:     badgerOptions,
+		ReadOnly:          true,
+	}
+	s, err := store.OpenStore(logger, home, openOptions)
+	if err != nil {
+		return archive.ListResponse{}, err
+	}
+	defer func() {
+		err := s.Close(logger, openOptions)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+	}()
+	return archive.New(s.SignatureStore).WithLogger(logger).ListPage(ctx, filter)
+}
+
+func writeArchivedConfirms(logger tmlog.Logger, output archive.ListResponse, outputFile string) error {
+	if outputFile == "" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output)
+	}
+
+	logger.Info("writing archived confirms json file", "path", outputFile)
+	file, err := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) {
+		err := file.Close()
+		if err != nil {
+			logger.Error("failed to close file", "err", err.Error())
+		}
+	}(file)
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(output)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("output written to file successfully", "path", outputFile)
+	return nil
+}
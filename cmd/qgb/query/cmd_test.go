@@ -0,0 +1,123 @@
+package query
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	celestiatypes "github.com/celestiaorg/celestia-app/x/qgb/types"
+	"github.com/celestiaorg/orchestrator-relayer/auditor"
+	qgbtesting "github.com/celestiaorg/orchestrator-relayer/testing"
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+func TestQueryStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping query status test in short mode.")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	logger := tmlog.NewNopLogger()
+
+	node := qgbtesting.NewTestNode(ctx, t)
+	defer node.Close()
+	_, err := node.CelestiaNetwork.WaitForHeightWithTimeout(120, time.Minute)
+	require.NoError(t, err)
+	orch := qgbtesting.NewOrchestrator(t, node)
+	r := qgbtesting.NewRelayer(t, node)
+	go node.EVMChain.PeriodicCommit(ctx, time.Millisecond)
+
+	latestDC, err := r.AppQuerier.QueryLatestDataCommitment(ctx)
+	require.NoError(t, err)
+	vs, err := r.AppQuerier.QueryLastValsetBeforeNonce(ctx, latestDC.Nonce)
+	require.NoError(t, err)
+	// the attestations following the last valset before a data commitment are data commitments
+	dc, err := r.AppQuerier.QueryDataCommitmentByNonce(ctx, vs.Nonce+1)
+	require.NoError(t, err)
+
+	// deploying a contract lagging behind Celestia at the valset preceding the data commitment
+	_, tx, _, err := r.EVMClient.DeployQGBContract(node.EVMChain.Auth, node.EVMChain.Backend, *vs, vs.Nonce, true)
+	require.NoError(t, err)
+	_, err = r.EVMClient.WaitForTransaction(ctx, node.EVMChain.Backend, tx)
+	require.NoError(t, err)
+
+	output, err := queryStatus(ctx, logger, r.AppQuerier, r.TmQuerier, r.EVMClient)
+	require.NoError(t, err)
+	assert.Equal(t, vs.Nonce, output.ContractNonce)
+	assert.GreaterOrEqual(t, output.LatestNonce, latestDC.Nonce)
+	assert.Equal(t, output.LatestNonce-vs.Nonce, output.NoncesLag)
+	assert.GreaterOrEqual(t, output.CelestiaHeight, uint64(120))
+	// the last relayed height is the end block of the last data commitment before the contract nonce
+	lastRelayedHeight := uint64(0)
+	for nonce := vs.Nonce; nonce > 0 && lastRelayedHeight == 0; nonce-- {
+		att, err := r.AppQuerier.QueryAttestationByNonce(ctx, nonce)
+		require.NoError(t, err)
+		if prevDC, ok := att.(*celestiatypes.DataCommitment); ok {
+			lastRelayedHeight = prevDC.EndBlock
+		}
+	}
+	assert.Equal(t, lastRelayedHeight, output.LastRelayedHeight)
+	assert.Equal(t, output.CelestiaHeight-output.LastRelayedHeight, output.BlocksLag)
+	require.NotNil(t, output.NextAttestation)
+	assert.Equal(t, dc.Nonce, output.NextAttestation.Nonce)
+	assert.Equal(t, auditor.AttestationTypeDataCommitment, output.NextAttestation.Type)
+	assert.Equal(t, dc.BeginBlock, output.NextAttestation.BeginBlock)
+	assert.Equal(t, dc.EndBlock, output.NextAttestation.EndBlock)
+
+	// the next attestation is not relayable until it's signed
+	require.NoError(t, queryNextAttestationSignatures(ctx, r.AppQuerier, r.TmQuerier, r.P2PQuerier, output.NextAttestation))
+	require.NotNil(t, output.NextAttestation.Relayable)
+	assert.False(t, *output.NextAttestation.Relayable)
+	assert.Equal(t, uint64(0), *output.NextAttestation.SignedPower)
+
+	commitment, err := r.TmQuerier.QueryCommitment(ctx, dc.BeginBlock, dc.EndBlock)
+	require.NoError(t, err)
+	dataRootTupleRoot := types.DataCommitmentTupleRootSignBytes(big.NewInt(int64(dc.Nonce)), commitment)
+	require.NoError(t, orch.ProcessDataCommitmentEvent(ctx, *dc, dataRootTupleRoot))
+	require.NoError(t, queryNextAttestationSignatures(ctx, r.AppQuerier, r.TmQuerier, r.P2PQuerier, output.NextAttestation))
+	assert.True(t, *output.NextAttestation.Relayable)
+	assert.GreaterOrEqual(t, *output.NextAttestation.SignedPower, *output.NextAttestation.MajorityThreshold)
+
+	// no relay event is found for the next attestation until it's relayed
+	relay, err := queryLastRelay(ctx, r.EVMClient, node.EVMChain.Backend, dc.Nonce, 0, DefaultEVMLookbackBlocks, 2)
+	require.NoError(t, err)
+	assert.Nil(t, relay)
+
+	tx, err = r.ProcessAttestation(ctx, node.EVMChain.Auth, dc)
+	require.NoError(t, err)
+	receipt, err := r.EVMClient.WaitForTransaction(ctx, node.EVMChain.Backend, tx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), receipt.Status)
+	// committing more blocks so that the relay event is not in the latest window
+	require.Eventually(t, func() bool {
+		latest, err := node.EVMChain.Backend.BlockByNumber(ctx, nil)
+		return err == nil && latest.NumberU64() > receipt.BlockNumber.Uint64()+4
+	}, 10*time.Second, 10*time.Millisecond)
+
+	output, err = queryStatus(ctx, logger, r.AppQuerier, r.TmQuerier, r.EVMClient)
+	require.NoError(t, err)
+	assert.Equal(t, dc.Nonce, output.ContractNonce)
+	assert.Equal(t, dc.EndBlock, output.LastRelayedHeight)
+
+	relay, err = queryLastRelay(ctx, r.EVMClient, node.EVMChain.Backend, output.ContractNonce, 0, DefaultEVMLookbackBlocks, 2)
+	require.NoError(t, err)
+	require.NotNil(t, relay)
+	assert.Equal(t, dc.Nonce, relay.Nonce)
+	assert.Equal(t, auditor.AttestationTypeDataCommitment, relay.Type)
+	assert.Equal(t, receipt.BlockNumber.Uint64(), relay.EVMBlock)
+	assert.Equal(t, tx.Hash().Hex(), relay.TxHash)
+
+	// the relay event is not searched before the start block
+	relay, err = queryLastRelay(ctx, r.EVMClient, node.EVMChain.Backend, output.ContractNonce, receipt.BlockNumber.Uint64()+1, DefaultEVMLookbackBlocks, 2)
+	require.NoError(t, err)
+	assert.Nil(t, relay)
+
+	// nor before the lookback blocks
+	relay, err = queryLastRelay(ctx, r.EVMClient, node.EVMChain.Backend, output.ContractNonce, 0, 2, 2)
+	require.NoError(t, err)
+	assert.Nil(t, relay)
+}
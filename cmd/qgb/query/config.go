@@ -3,7 +3,11 @@ package query
 import (
 	"fmt"
 
+	"github.com/celestiaorg/orchestrator-relayer/archive"
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/relayer"
+	"github.com/celestiaorg/orchestrator-relayer/store"
+	ethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +23,7 @@ func addFlags(cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().Uint(relayer.FlagCoreRPCPort, 26657, "Specify the rest rpc address")
 	cmd.Flags().String(FlagP2PNode, "", "P2P target node multiaddress (eg. /ip4/127.0.0.1/tcp/30000/p2p/12D3KooWBSMasWzRSRKXREhediFUwABNZwzJbkZcYz5rYr9Zdmfn)")
 	cmd.Flags().String(FlagOutputFile, "", "Path to an output file path if the results need to be written to a json file. Leaving it as empty will result in printing the result to stdout")
+	base.AddLogFlags(cmd)
 
 	return cmd
 }
@@ -27,6 +32,7 @@ type Config struct {
 	coreGRPC, coreRPC string
 	targetNode        string
 	outputFile        string
+	log               base.LogConfig
 }
 
 func parseFlags(cmd *cobra.Command) (Config, error) {
@@ -55,10 +61,293 @@ func parseFlags(cmd *cobra.Command) (Config, error) {
 		return Config{}, err
 	}
 
+	logConfig, err := base.ParseLogFlags(cmd)
+	if err != nil {
+		return Config{}, err
+	}
+
 	return Config{
 		coreGRPC:   fmt.Sprintf("%s:%d", coreGRPCHost, coreGRPCPort),
 		coreRPC:    fmt.Sprintf("tcp://%s:%d", coreRPCHost, coreRPCPort),
 		targetNode: targetNode,
 		outputFile: outputFile,
+		log:        logConfig,
+	}, nil
+}
+
+func addProofFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(relayer.FlagCoreGRPCHost, "localhost", "Specify the grpc address host")
+	cmd.Flags().Uint(relayer.FlagCoreGRPCPort, 9090, "Specify the grpc address port")
+	cmd.Flags().String(relayer.FlagCoreRPCHost, "localhost", "Specify the rest rpc address host")
+	cmd.Flags().Uint(relayer.FlagCoreRPCPort, 26657, "Specify the rest rpc address")
+	cmd.Flags().String(relayer.FlagEVMRPC, "http://localhost:8545", "Specify the ethereum rpc address")
+	cmd.Flags().String(relayer.FlagContractAddress, "", "Specify the contract at which the qgb is deployed. If set, the proof will be verified against the contract using an eth_call to `verifyAttestation`")
+	cmd.Flags().String(FlagOutputFile, "", "Path to an output file path if the results need to be written to a json file. Leaving it as empty will result in printing the result to stdout")
+	base.AddLogFlags(cmd)
+
+	return cmd
+}
+
+type ProofConfig struct {
+	coreGRPC, coreRPC string
+	evmRPC            string
+	contractAddr      string
+	outputFile        string
+	log               base.LogConfig
+}
+
+func parseProofFlags(cmd *cobra.Command) (ProofConfig, error) {
+	coreRPCHost, err := cmd.Flags().GetString(relayer.FlagCoreRPCHost)
+	if err != nil {
+		return ProofConfig{}, err
+	}
+	coreRPCPort, err := cmd.Flags().GetUint(relayer.FlagCoreRPCPort)
+	if err != nil {
+		return ProofConfig{}, err
+	}
+	coreGRPCHost, err := cmd.Flags().GetString(relayer.FlagCoreGRPCHost)
+	if err != nil {
+		return ProofConfig{}, err
+	}
+	coreGRPCPort, err := cmd.Flags().GetUint(relayer.FlagCoreGRPCPort)
+	if err != nil {
+		return ProofConfig{}, err
+	}
+	evmRPC, err := cmd.Flags().GetString(relayer.FlagEVMRPC)
+	if err != nil {
+		return ProofConfig{}, err
+	}
+	contractAddr, err := cmd.Flags().GetString(relayer.FlagContractAddress)
+	if err != nil {
+		return ProofConfig{}, err
+	}
+	if contractAddr != "" && !ethcmn.IsHexAddress(contractAddr) {
+		return ProofConfig{}, fmt.Errorf("valid contract address flag is required: %s", relayer.FlagContractAddress)
+	}
+	outputFile, err := cmd.Flags().GetString(FlagOutputFile)
+	if err != nil {
+		return ProofConfig{}, err
+	}
+
+	logConfig, err := base.ParseLogFlags(cmd)
+	if err != nil {
+		return ProofConfig{}, err
+	}
+
+	return ProofConfig{
+		coreGRPC:     fmt.Sprintf("%s:%d", coreGRPCHost, coreGRPCPort),
+		coreRPC:      fmt.Sprintf("tcp://%s:%d", coreRPCHost, coreRPCPort),
+		evmRPC:       evmRPC,
+		contractAddr: contractAddr,
+		outputFile:   outputFile,
+		log:          logConfig,
+	}, nil
+}
+
+const (
+	FlagArchiveURL        = "archive.url"
+	FlagArchiveFromNonce  = "from-nonce"
+	FlagArchiveToNonce    = "to-nonce"
+	FlagArchiveEVMAddress = "evm-address"
+	FlagArchiveDigest     = "digest"
+	FlagArchiveType       = "type"
+	FlagArchiveLimit      = "limit"
+)
+
+func addArchiveFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(FlagArchiveURL, "http://localhost:8080", "The relayer signature archive API URL, served when the relayer is started with --"+relayer.FlagArchiveListenAddr)
+	cmd.Flags().String(base.FlagHome, "", "The relayer home directory. If set, the archive is read from the relayer store instead of the API. The relayer should be stopped")
+	base.AddStoreBackendFlag(cmd)
+	cmd.Flags().Uint64(FlagArchiveFromNonce, 0, "The lowest nonce of the listed confirms, inclusive")
+	cmd.Flags().Uint64(FlagArchiveToNonce, 0, "The highest nonce of the listed confirms, inclusive. Zero means no upper bound")
+	cmd.Flags().String(FlagArchiveEVMAddress, "", "Only list the confirms signed by this EVM address")
+	cmd.Flags().String(FlagArchiveDigest, "", "Only list the confirms signing this digest, i.e. the valset sign bytes or the data root tuple root")
+	cmd.Flags().String(FlagArchiveType, "", "Only list the confirms of this type: 'valset' or 'data_commitment'")
+	cmd.Flags().Int(FlagArchiveLimit, 0, "The maximum number of listed confirms. Zero means no limit when reading the store, and the API default limit otherwise. The next page is listed from the returned next nonce")
+	cmd.Flags().String(FlagOutputFile, "", "Path to an output file path if the results need to be written to a json file. Leaving it as empty will result in printing the result to stdout")
+	base.AddLogFlags(cmd)
+
+	return cmd
+}
+
+type ArchiveConfig struct {
+	archiveURL   string
+	home         string
+	storeBackend store.Backend
+	filter       archive.Filter
+	outputFile   string
+	log          base.LogConfig
+}
+
+func parseArchiveFlags(cmd *cobra.Command) (ArchiveConfig, error) {
+	archiveURL, err := cmd.Flags().GetString(FlagArchiveURL)
+	if err != nil {
+		return ArchiveConfig{}, err
+	}
+	home, err := cmd.Flags().GetString(base.FlagHome)
+	if err != nil {
+		return ArchiveConfig{}, err
+	}
+	storeBackend, err := base.ParseStoreBackendFlag(cmd)
+	if err != nil {
+		return ArchiveConfig{}, err
+	}
+	fromNonce, err := cmd.Flags().GetUint64(FlagArchiveFromNonce)
+	if err != nil {
+		return ArchiveConfig{}, err
+	}
+	toNonce, err := cmd.Flags().GetUint64(FlagArchiveToNonce)
+	if err != nil {
+		return ArchiveConfig{}, err
+	}
+	evmAddress, err := cmd.Flags().GetString(FlagArchiveEVMAddress)
+	if err != nil {
+		return ArchiveConfig{}, err
+	}
+	digest, err := cmd.Flags().GetString(FlagArchiveDigest)
+	if err != nil {
+		return ArchiveConfig{}, err
+	}
+	confirmType, err := cmd.Flags().GetString(FlagArchiveType)
+	if err != nil {
+		return ArchiveConfig{}, err
+	}
+	limit, err := cmd.Flags().GetInt(FlagArchiveLimit)
+	if err != nil {
+		return ArchiveConfig{}, err
+	}
+	filter := archive.Filter{
+		FromNonce:  fromNonce,
+		ToNonce:    toNonce,
+		EVMAddress: evmAddress,
+		Digest:     digest,
+		Type:       confirmType,
+		Limit:      limit,
+	}
+	err = filter.Validate()
+	if err != nil {
+		return ArchiveConfig{}, err
+	}
+	outputFile, err := cmd.Flags().GetString(FlagOutputFile)
+	if err != nil {
+		return ArchiveConfig{}, err
+	}
+
+	logConfig, err := base.ParseLogFlags(cmd)
+	if err != nil {
+		return ArchiveConfig{}, err
+	}
+
+	return ArchiveConfig{
+		archiveURL:   archiveURL,
+		home:         home,
+		storeBackend: storeBackend,
+		filter:       filter,
+		outputFile:   outputFile,
+		log:          logConfig,
+	}, nil
+}
+
+const (
+	FlagEVMStartBlock     = "evm.start-block"
+	FlagEVMLookbackBlocks = "evm.lookback-blocks"
+)
+
+// DefaultEVMLookbackBlocks the default number of EVM blocks, before the latest one, searched for the last relay event.
+const DefaultEVMLookbackBlocks = uint64(100000)
+
+func addStatusFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(relayer.FlagCoreGRPCHost, "localhost", "Specify the grpc address host")
+	cmd.Flags().Uint(relayer.FlagCoreGRPCPort, 9090, "Specify the grpc address port")
+	cmd.Flags().String(relayer.FlagCoreRPCHost, "localhost", "Specify the rest rpc address host")
+	cmd.Flags().Uint(relayer.FlagCoreRPCPort, 26657, "Specify the rest rpc address")
+	cmd.Flags().String(relayer.FlagEVMRPC, "http://localhost:8545", "Specify the ethereum rpc address")
+	cmd.Flags().String(relayer.FlagContractAddress, "", "Specify the contract at which the qgb is deployed")
+	cmd.Flags().Uint64(FlagEVMStartBlock, 0, "Specify the EVM block down to which the contract events are searched, backwards from the latest block, to find the last relay event (usually, the contract deployment block)")
+	cmd.Flags().Uint64(FlagEVMLookbackBlocks, DefaultEVMLookbackBlocks, "Specify the maximum number of EVM blocks, before the latest one, searched for the last relay event. The search stops at the start block if it's more recent")
+	cmd.Flags().String(FlagP2PNode, "", "P2P target node multiaddress used to query the confirms of the next attestation. Leaving it as empty will skip checking whether the next attestation is relayable")
+	cmd.Flags().String(FlagOutputFile, "", "Path to an output file path if the status needs to be written to a json file. Leaving it as empty will result in printing the status to stdout")
+	base.AddLogFlags(cmd)
+
+	return cmd
+}
+
+type StatusConfig struct {
+	coreGRPC, coreRPC string
+	evmRPC            string
+	contractAddr      ethcmn.Address
+	evmStartBlock     uint64
+	evmLookbackBlocks uint64
+	targetNode        string
+	outputFile        string
+	log               base.LogConfig
+}
+
+func parseStatusFlags(cmd *cobra.Command) (StatusConfig, error) {
+	coreRPCHost, err := cmd.Flags().GetString(relayer.FlagCoreRPCHost)
+	if err != nil {
+		return StatusConfig{}, err
+	}
+	coreRPCPort, err := cmd.Flags().GetUint(relayer.FlagCoreRPCPort)
+	if err != nil {
+		return StatusConfig{}, err
+	}
+	coreGRPCHost, err := cmd.Flags().GetString(relayer.FlagCoreGRPCHost)
+	if err != nil {
+		return StatusConfig{}, err
+	}
+	coreGRPCPort, err := cmd.Flags().GetUint(relayer.FlagCoreGRPCPort)
+	if err != nil {
+		return StatusConfig{}, err
+	}
+	evmRPC, err := cmd.Flags().GetString(relayer.FlagEVMRPC)
+	if err != nil {
+		return StatusConfig{}, err
+	}
+	contractAddr, err := cmd.Flags().GetString(relayer.FlagContractAddress)
+	if err != nil {
+		return StatusConfig{}, err
+	}
+	if contractAddr == "" {
+		return StatusConfig{}, fmt.Errorf("contract address flag is required: %s", relayer.FlagContractAddress)
+	}
+	if !ethcmn.IsHexAddress(contractAddr) {
+		return StatusConfig{}, fmt.Errorf("valid contract address flag is required: %s", relayer.FlagContractAddress)
+	}
+	evmStartBlock, err := cmd.Flags().GetUint64(FlagEVMStartBlock)
+	if err != nil {
+		return StatusConfig{}, err
+	}
+	evmLookbackBlocks, err := cmd.Flags().GetUint64(FlagEVMLookbackBlocks)
+	if err != nil {
+		return StatusConfig{}, err
+	}
+	if evmLookbackBlocks == 0 {
+		return StatusConfig{}, fmt.Errorf("the %s flag should be positive", FlagEVMLookbackBlocks)
+	}
+	targetNode, err := cmd.Flags().GetString(FlagP2PNode)
+	if err != nil {
+		return StatusConfig{}, err
+	}
+	outputFile, err := cmd.Flags().GetString(FlagOutputFile)
+	if err != nil {
+		return StatusConfig{}, err
+	}
+
+	logConfig, err := base.ParseLogFlags(cmd)
+	if err != nil {
+		return StatusConfig{}, err
+	}
+
+	return StatusConfig{
+		coreGRPC:          fmt.Sprintf("%s:%d", coreGRPCHost, coreGRPCPort),
+		coreRPC:           fmt.Sprintf("tcp://%s:%d", coreRPCHost, coreRPCPort),
+		evmRPC:            evmRPC,
+		contractAddr:      ethcmn.HexToAddress(contractAddr),
+		evmStartBlock:     evmStartBlock,
+		evmLookbackBlocks: evmLookbackBlocks,
+		targetNode:        targetNode,
+		outputFile:        outputFile,
+		log:               logConfig,
 	}, nil
 }
@@ -2,24 +2,30 @@ package relayer
 
 import (
 	"context"
+	"errors"
 	"os"
 	"time"
 
+	"github.com/celestiaorg/orchestrator-relayer/archive"
 	evm2 "github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys/evm"
 	"github.com/celestiaorg/orchestrator-relayer/p2p"
 	dssync "github.com/ipfs/go-datastore/sync"
 
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/common"
+	configcmd "github.com/celestiaorg/orchestrator-relayer/cmd/qgb/config"
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/keys"
+	storecmd "github.com/celestiaorg/orchestrator-relayer/cmd/qgb/store"
 	"github.com/celestiaorg/orchestrator-relayer/evm"
 	"github.com/celestiaorg/orchestrator-relayer/helpers"
+	"github.com/celestiaorg/orchestrator-relayer/orchestrator"
 	"github.com/celestiaorg/orchestrator-relayer/store"
 
 	"github.com/celestiaorg/orchestrator-relayer/relayer"
 	wrapper "github.com/celestiaorg/quantum-gravity-bridge/wrappers/QuantumGravityBridge.sol"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
-	tmlog "github.com/tendermint/tendermint/libs/log"
 )
 
 func Command() *cobra.Command {
@@ -34,6 +40,8 @@ func Command() *cobra.Command {
 		Start(),
 		Init(),
 		keys.Command(ServiceNameRelayer),
+		configcmd.Command(addRelayerStartFlags),
+		storecmd.Command(ServiceNameRelayer),
 	)
 
 	relCmd.SetHelpCommand(&cobra.Command{})
@@ -45,32 +53,45 @@ func Command() *cobra.Command {
 func Init() *cobra.Command {
 	cmd := cobra.Command{
 		Use:   "init",
-		Short: "Initialize the QGB relayer store. Passed flags have persisted effect.",
+		Short: "Initialize the QGB relayer store, and write the passed flags, along with the defaults, to the config file loaded on start",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			config, err := parseInitFlags(cmd)
 			if err != nil {
 				return err
 			}
 
-			logger := tmlog.NewTMLogger(os.Stdout)
+			logger, err := base.NewLogger(os.Stdout, config.log)
+			if err != nil {
+				return err
+			}
 
 			initOptions := store.InitOptions{
 				NeedDataStore:      true,
 				NeedEVMKeyStore:    true,
 				NeedP2PKeyStore:    true,
 				NeedSignatureStore: true,
+				Backend:            config.storeBackend,
 			}
 			isInit := store.IsInit(logger, config.home, initOptions)
 			if isInit {
 				logger.Info("provided path is already initiated", "path", config.home)
-				return nil
+			} else {
+				err = store.Init(logger, config.home, initOptions)
+				if err != nil {
+					return err
+				}
 			}
 
-			err = store.Init(logger, config.home, initOptions)
+			// persist the passed flags, along with the defaults, so that they're loaded on start
+			err = base.WriteConfigFile(cmd, config.home)
 			if err != nil {
+				if errors.Is(err, base.ErrConfigFileExists) {
+					logger.Info("config file already exists, not overwriting it", "path", base.ConfigFilePath(config.home))
+					return nil
+				}
 				return err
 			}
-
+			logger.Info("config file written", "path", base.ConfigFilePath(config.home))
 			return nil
 		},
 	}
@@ -88,7 +109,11 @@ func Start() *cobra.Command {
 			}
 
 			// creating the logger
-			logger := tmlog.NewTMLogger(os.Stdout)
+			logger, err := base.NewLogger(os.Stdout, config.Log)
+			if err != nil {
+				return err
+			}
+			p2pLogger := base.ModuleLogger(logger, base.LogModuleP2P)
 			logger.Debug("initializing relayer")
 
 			ctx, cancel := context.WithCancel(cmd.Context())
@@ -96,7 +121,7 @@ func Start() *cobra.Command {
 
 			stopFuncs := make([]func() error, 0)
 
-			tmQuerier, appQuerier, stops, err := common.NewTmAndAppQuerier(logger, config.coreRPC, config.coreGRPC)
+			tmQuerier, appQuerier, stops, err := common.NewTmAndAppQuerier(base.ModuleLogger(logger, base.LogModuleRPC), config.coreRPC, config.coreGRPC)
 			stopFuncs = append(stopFuncs, stops...)
 			if err != nil {
 				return err
@@ -104,7 +129,8 @@ func Start() *cobra.Command {
 
 			s, stops, err := common.OpenStore(logger, config.Home, store.OpenOptions{
 				HasDataStore:      true,
-				BadgerOptions:     store.DefaultBadgerOptions(config.Home),
+				Backend:           config.storeBackend,
+				BadgerOptions:     store.BadgerOptionsWithGC(config.Home, config.storeGC),
 				HasSignatureStore: true,
 				HasEVMKeyStore:    true,
 				HasP2PKeyStore:    true,
@@ -114,7 +140,7 @@ func Start() *cobra.Command {
 				return err
 			}
 
-			logger.Info("loading EVM account", "address", config.evmAccAddress)
+			logger.Info("loading EVM account", "evm_address", config.evmAccAddress)
 
 			acc, err := evm2.GetAccountFromStoreAndUnlockIt(s.EVMKeyStore, config.evmAccAddress, config.EVMPassphrase)
 			stopFuncs = append(stopFuncs, func() error { return s.EVMKeyStore.Lock(acc.Address) })
@@ -125,14 +151,47 @@ func Start() *cobra.Command {
 			// creating the data store
 			dataStore := dssync.MutexWrap(s.DataStore)
 
-			dht, err := common.CreateDHTAndWaitForPeers(ctx, logger, s.P2PKeyStore, config.p2pNickname, config.p2pListenAddr, config.bootstrappers, dataStore)
+			authOpts := common.P2PAuthOptions{}
+			if config.p2pAuthenticate {
+				authOpts.Gater = p2p.NewConnectionGater(appQuerier, config.p2pAllowlist, p2pLogger)
+			}
+			if config.p2pChainValidation {
+				authOpts.ChainView = p2p.NewChainView(appQuerier, tmQuerier, p2pLogger)
+			}
+
+			swarmKey, err := common.LoadSwarmKey(logger, s, config.p2pSwarmKey)
+			if err != nil {
+				return err
+			}
+			hostConfig := config.p2pHostConfig
+			hostConfig.SwarmKey = swarmKey
+
+			dht, err := common.CreateDHTAndWaitForPeers(ctx, p2pLogger, s.P2PKeyStore, config.p2pNickname, config.P2PPassphrase, config.p2pListenAddr, config.bootstrappers, dataStore, hostConfig, config.p2pVersions, authOpts)
 			if err != nil {
 				return err
 			}
 			stopFuncs = append(stopFuncs, func() error { return dht.Close() })
 
+			// creating the gossipsub router used to receive the confirms in near real time
+			ps, err := p2p.NewQgbPubSub(ctx, dht.Host(), p2pLogger)
+			if err != nil {
+				return err
+			}
+			stopFuncs = append(stopFuncs, func() error { return ps.Close() })
+			if authOpts.ChainView != nil {
+				err = ps.UseChainView(authOpts.ChainView)
+				if err != nil {
+					return err
+				}
+			}
+			err = ps.Subscribe(ctx)
+			if err != nil {
+				return err
+			}
+
 			// creating the p2p querier
-			p2pQuerier := p2p.NewQuerier(dht, logger)
+			p2pQuerier := p2p.NewQuerier(dht, p2pLogger)
+			p2pQuerier.WithPubSub(ps)
 			retrier := helpers.NewRetrier(logger, 6, time.Minute)
 
 			defer func() {
@@ -156,7 +215,7 @@ func Start() *cobra.Command {
 			}
 
 			evmClient := evm.NewClient(
-				logger,
+				base.ModuleLogger(logger, base.LogModuleEVM),
 				qgbWrapper,
 				s.EVMKeyStore,
 				&acc,
@@ -164,12 +223,41 @@ func Start() *cobra.Command {
 				config.evmGasLimit,
 			)
 
+			// pruning the confirms that are not needed anymore
+			pruner := p2p.Pruner{
+				DataStore:   s.DataStore,
+				NonceWindow: config.pruneWindow,
+				LatestNonce: appQuerier.QueryLatestAttestationNonce,
+				// the confirms inside the unbonding period are signed again by the orchestrators if missing
+				UnbondingNonce: func(ctx context.Context) (uint64, error) {
+					return orchestrator.UnbondingStartingNonce(ctx, appQuerier)
+				},
+				Logger: p2pLogger,
+			}
+			if config.pruneRelayed {
+				pruner.LastRelayedNonce = func(ctx context.Context) (uint64, error) {
+					return evmClient.StateLastEventNonce(&bind.CallOpts{Context: ctx})
+				}
+			}
+			if config.pruneSignatures {
+				pruner.SignatureStore = s.SignatureStore
+			}
+			pruner.Start(ctx, config.pruneInterval)
+
+			// serving the signature archive api
+			if config.archiveListenAddr != "" {
+				err = archive.NewServer(archive.New(s.SignatureStore).WithLogger(logger), logger).Serve(ctx, config.archiveListenAddr)
+				if err != nil {
+					return err
+				}
+			}
+
 			relay := relayer.NewRelayer(
 				tmQuerier,
 				appQuerier,
 				p2pQuerier,
 				evmClient,
-				logger,
+				base.ModuleLogger(logger, base.LogModuleRelayer),
 				retrier,
 				s.SignatureStore,
 			)
@@ -3,10 +3,15 @@ package relayer
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/client/flags"
 
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/celestiaorg/orchestrator-relayer/helpers"
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	"github.com/celestiaorg/orchestrator-relayer/store"
+	"github.com/libp2p/go-libp2p/core/peer"
 
 	"github.com/celestiaorg/orchestrator-relayer/evm"
 	"github.com/spf13/cobra"
@@ -25,6 +30,11 @@ const (
 	FlagContractAddress = "evm.contract-address"
 	FlagEVMGasLimit     = "evm.gas-limit"
 	ServiceNameRelayer  = "relayer"
+
+	FlagStorePruneRelayed    = "store.prune-relayed"
+	FlagStorePruneSignatures = "store.prune-signatures"
+
+	FlagArchiveListenAddr = "archive.listen-addr"
 )
 
 func addRelayerStartFlags(cmd *cobra.Command) *cobra.Command {
@@ -42,10 +52,23 @@ func addRelayerStartFlags(cmd *cobra.Command) *cobra.Command {
 		panic(err)
 	}
 	cmd.Flags().String(base.FlagHome, homeDir, "The qgb relayer home directory")
-	cmd.Flags().String(base.FlagEVMPassphrase, "", "the evm account passphrase (if not specified as a flag, it will be asked interactively)")
+	base.AddEVMPassphraseFlags(cmd)
+	base.AddP2PPassphraseFlags(cmd)
 	base.AddP2PNicknameFlag(cmd)
 	base.AddP2PListenAddressFlag(cmd)
 	base.AddBootstrappersFlag(cmd)
+	base.AddP2PAuthFlags(cmd)
+	base.AddP2PChainValidationFlag(cmd)
+	base.AddP2PSwarmKeyFlag(cmd)
+	base.AddP2PHostFlags(cmd)
+	base.AddP2PProtocolVersionFlags(cmd)
+	base.AddStoreBackendFlag(cmd)
+	base.AddStoreGCFlags(cmd)
+	base.AddStorePruneFlags(cmd)
+	cmd.Flags().Bool(FlagStorePruneRelayed, false, "Prune the confirms whose nonces are lower than the last nonce relayed to the QGB contract. The confirms inside the unbonding period are always kept")
+	cmd.Flags().Bool(FlagStorePruneSignatures, false, "Also prune the relayed signatures archive. By default, it is kept in full")
+	cmd.Flags().String(FlagArchiveListenAddr, "", "The address to serve the read-only signature archive HTTP/JSON API on, e.g. localhost:8080. Leaving it empty disables the API")
+	base.AddLogFlags(cmd)
 
 	return cmd
 }
@@ -59,6 +82,19 @@ type StartConfig struct {
 	evmGasLimit                  uint64
 	bootstrappers, p2pListenAddr string
 	p2pNickname                  string
+	p2pAuthenticate              bool
+	p2pAllowlist                 []peer.ID
+	p2pChainValidation           bool
+	p2pSwarmKey                  string
+	p2pHostConfig                p2p.HostConfig
+	p2pVersions                  p2p.ProtocolVersions
+	storeBackend                 store.Backend
+	storeGC                      store.GCConfig
+	pruneWindow                  uint64
+	pruneInterval                time.Duration
+	pruneRelayed                 bool
+	pruneSignatures              bool
+	archiveListenAddr            string
 }
 
 func parseRelayerStartFlags(cmd *cobra.Command) (StartConfig, error) {
@@ -120,6 +156,58 @@ func parseRelayerStartFlags(cmd *cobra.Command) (StartConfig, error) {
 	if err != nil {
 		return StartConfig{}, err
 	}
+	p2pAuthenticate, err := cmd.Flags().GetBool(base.FlagP2PAuthenticate)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	p2pAllowlist, err := cmd.Flags().GetString(base.FlagP2PAllowlist)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	allowlist, err := helpers.ParsePeerIDs(p2pAllowlist)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	p2pChainValidation, err := cmd.Flags().GetBool(base.FlagP2PChainValidation)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	p2pSwarmKey, err := cmd.Flags().GetString(base.FlagP2PSwarmKey)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	p2pHostConfig, err := base.ParseP2PHostFlags(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	p2pVersions, err := base.ParseP2PProtocolVersions(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	storeBackend, err := base.ParseStoreBackendFlag(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	storeGC, err := base.ParseStoreGCFlags(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	pruneWindow, pruneInterval, err := base.ParseStorePruneFlags(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	pruneRelayed, err := cmd.Flags().GetBool(FlagStorePruneRelayed)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	pruneSignatures, err := cmd.Flags().GetBool(FlagStorePruneSignatures)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	archiveListenAddr, err := cmd.Flags().GetString(FlagArchiveListenAddr)
+	if err != nil {
+		return StartConfig{}, err
+	}
 	homeDir, err := cmd.Flags().GetString(base.FlagHome)
 	if err != nil {
 		return StartConfig{}, err
@@ -131,40 +219,61 @@ func parseRelayerStartFlags(cmd *cobra.Command) (StartConfig, error) {
 			return StartConfig{}, err
 		}
 	}
-	passphrase, err := cmd.Flags().GetString(base.FlagEVMPassphrase)
+	passphrase, err := base.ParseEVMPassphrase(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	p2pPassphrase, err := base.ParseP2PPassphrase(cmd)
+	if err != nil {
+		return StartConfig{}, err
+	}
+	logConfig, err := base.ParseLogFlags(cmd)
 	if err != nil {
 		return StartConfig{}, err
 	}
 
 	return StartConfig{
-		evmAccAddress: evmAccAddr,
-		evmChainID:    evmChainID,
-		coreGRPC:      fmt.Sprintf("%s:%d", coreGRPCHost, coreGRPCPort),
-		coreRPC:       fmt.Sprintf("tcp://%s:%d", coreRPCHost, coreRPCPort),
-		contractAddr:  address,
-		evmRPC:        evmRPC,
-		evmGasLimit:   evmGasLimit,
-		bootstrappers: bootstrappers,
-		p2pListenAddr: p2pListenAddress,
-		p2pNickname:   p2pNickname,
+		evmAccAddress:      evmAccAddr,
+		evmChainID:         evmChainID,
+		coreGRPC:           fmt.Sprintf("%s:%d", coreGRPCHost, coreGRPCPort),
+		coreRPC:            fmt.Sprintf("tcp://%s:%d", coreRPCHost, coreRPCPort),
+		contractAddr:       address,
+		evmRPC:             evmRPC,
+		evmGasLimit:        evmGasLimit,
+		bootstrappers:      bootstrappers,
+		p2pListenAddr:      p2pListenAddress,
+		p2pNickname:        p2pNickname,
+		p2pAuthenticate:    p2pAuthenticate,
+		p2pAllowlist:       allowlist,
+		p2pChainValidation: p2pChainValidation,
+		p2pSwarmKey:        p2pSwarmKey,
+		p2pHostConfig:      p2pHostConfig,
+		p2pVersions:        p2pVersions,
+		storeBackend:       storeBackend,
+		storeGC:            storeGC,
+		pruneWindow:        pruneWindow,
+		pruneInterval:      pruneInterval,
+		pruneRelayed:       pruneRelayed,
+		pruneSignatures:    pruneSignatures,
+		archiveListenAddr:  archiveListenAddr,
 		Config: &base.Config{
 			Home:          homeDir,
 			EVMPassphrase: passphrase,
+			P2PPassphrase: p2pPassphrase,
+			Log:           logConfig,
 		},
 	}, nil
 }
 
+// addInitFlags adds the start flags to the init command, so that they're written to the config file.
 func addInitFlags(cmd *cobra.Command) *cobra.Command {
-	homeDir, err := base.DefaultServicePath(ServiceNameRelayer)
-	if err != nil {
-		panic(err)
-	}
-	cmd.Flags().String(base.FlagHome, homeDir, "The qgb relayer home directory")
-	return cmd
+	return addRelayerStartFlags(cmd)
 }
 
 type InitConfig struct {
-	home string
+	home         string
+	storeBackend store.Backend
+	log          base.LogConfig
 }
 
 func parseInitFlags(cmd *cobra.Command) (InitConfig, error) {
@@ -180,7 +289,19 @@ func parseInitFlags(cmd *cobra.Command) (InitConfig, error) {
 		}
 	}
 
+	storeBackend, err := base.ParseStoreBackendFlag(cmd)
+	if err != nil {
+		return InitConfig{}, err
+	}
+
+	logConfig, err := base.ParseLogFlags(cmd)
+	if err != nil {
+		return InitConfig{}, err
+	}
+
 	return InitConfig{
-		home: homeDir,
+		home:         homeDir,
+		storeBackend: storeBackend,
+		log:          logConfig,
 	}, nil
 }
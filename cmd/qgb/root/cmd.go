@@ -1,6 +1,8 @@
 package root
 
 import (
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/audit"
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/bootstrapper"
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/generate"
 	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/query"
@@ -20,6 +22,10 @@ func Cmd() *cobra.Command {
 		Use:          "qgb",
 		Short:        "The Quantum-Gravity-Bridge CLI",
 		SilenceUsage: true,
+		// every command reads its configuration from the config file and the environment variables
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return base.LoadConfig(cmd)
+		},
 	}
 
 	rootCmd.AddCommand(
@@ -30,6 +36,7 @@ func Cmd() *cobra.Command {
 		generate.Command(),
 		query.Command(),
 		bootstrapper.Command(),
+		audit.Command(),
 	)
 
 	rootCmd.SetHelpCommand(&cobra.Command{})
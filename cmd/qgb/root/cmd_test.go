@@ -0,0 +1,55 @@
+package root_test
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/root"
+	"github.com/celestiaorg/orchestrator-relayer/store"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadConfig checks that the commands other than start are configured using the environment
+// variables and the config file.
+func TestLoadConfig(t *testing.T) {
+	home := t.TempDir()
+	run := func(args ...string) error {
+		cmd := root.Cmd()
+		cmd.SetArgs(append(args, "--"+base.FlagHome, home))
+		return cmd.Execute()
+	}
+
+	// the passphrase is read from the environment, otherwise, it would be asked interactively
+	t.Setenv(base.EnvVarName(base.FlagP2PPassphrase), "123")
+	require.NoError(t, run("orchestrator", "keys", "p2p", "add", "key"))
+	ks, err := store.NewP2PKeyStore(filepath.Join(home, store.P2PKeyStorePath), keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	priv, err := ks.Get("key", "123")
+	require.NoError(t, err)
+
+	// the export format is read from the config file, but not the one-shot export flags
+	output := filepath.Join(t.TempDir(), "p2p.key")
+	config := "format = \"protobuf\"\noutput = \"" + output + "\"\nyes = true\n"
+	require.NoError(t, os.WriteFile(base.ConfigFilePath(home), []byte(config), 0o600))
+	// the export confirmation is asked interactively and declined
+	stdin, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	defer stdin.Close()
+	defaultStdin := os.Stdin
+	os.Stdin = stdin
+	defer func() { os.Stdin = defaultStdin }()
+	require.NoError(t, run("orchestrator", "keys", "p2p", "export", "key"))
+	assert.NoFileExists(t, output)
+
+	require.NoError(t, run("orchestrator", "keys", "p2p", "export", "key", "--yes", "--output", output))
+	exported, err := os.ReadFile(output)
+	require.NoError(t, err)
+	raw, err := priv.Raw()
+	require.NoError(t, err)
+	assert.Contains(t, string(exported), hex.EncodeToString(raw))
+}
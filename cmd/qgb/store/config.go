@@ -0,0 +1,104 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/spf13/cobra"
+)
+
+const (
+	FlagBackupOut   = "out"
+	FlagCheckRepair = "repair"
+)
+
+func storeConfigFlags(cmd *cobra.Command, service string) *cobra.Command {
+	homeDir, err := base.DefaultServicePath(service)
+	if err != nil {
+		panic(err)
+	}
+	cmd.Flags().String(base.FlagHome, homeDir, "The qgb store home directory")
+	base.AddLogFlags(cmd)
+	return cmd
+}
+
+type StoreConfig struct {
+	home string
+	log  base.LogConfig
+}
+
+func parseStoreConfigFlags(cmd *cobra.Command, serviceName string) (StoreConfig, error) {
+	homeDir, err := cmd.Flags().GetString(flags.FlagHome)
+	if err != nil {
+		return StoreConfig{}, err
+	}
+	if homeDir == "" {
+		var err error
+		homeDir, err = base.DefaultServicePath(serviceName)
+		if err != nil {
+			return StoreConfig{}, err
+		}
+	}
+	logConfig, err := base.ParseLogFlags(cmd)
+	if err != nil {
+		return StoreConfig{}, err
+	}
+	return StoreConfig{
+		home: homeDir,
+		log:  logConfig,
+	}, nil
+}
+
+func addBackupFlags(cmd *cobra.Command, service string) *cobra.Command {
+	cmd.Flags().String(FlagBackupOut, "", "The file to write the backup archive to. Should not exist")
+	return storeConfigFlags(cmd, service)
+}
+
+type BackupConfig struct {
+	StoreConfig
+	out string
+}
+
+func parseBackupFlags(cmd *cobra.Command, serviceName string) (BackupConfig, error) {
+	storeConfig, err := parseStoreConfigFlags(cmd, serviceName)
+	if err != nil {
+		return BackupConfig{}, err
+	}
+	out, err := cmd.Flags().GetString(FlagBackupOut)
+	if err != nil {
+		return BackupConfig{}, err
+	}
+	if out == "" {
+		return BackupConfig{}, fmt.Errorf("the backup file should be specified using the --%s flag", FlagBackupOut)
+	}
+	return BackupConfig{
+		StoreConfig: storeConfig,
+		out:         out,
+	}, nil
+}
+
+func addCheckFlags(cmd *cobra.Command, service string) *cobra.Command {
+	cmd.Flags().Bool(FlagCheckRepair, false, "Repair the store: truncate the corrupted value logs, losing the writes that were not fully persisted, remove the invalid records and the orphaned lock files")
+	return storeConfigFlags(cmd, service)
+}
+
+type CheckConfig struct {
+	StoreConfig
+	repair bool
+}
+
+func parseCheckFlags(cmd *cobra.Command, serviceName string) (CheckConfig, error) {
+	storeConfig, err := parseStoreConfigFlags(cmd, serviceName)
+	if err != nil {
+		return CheckConfig{}, err
+	}
+	repair, err := cmd.Flags().GetBool(FlagCheckRepair)
+	if err != nil {
+		return CheckConfig{}, err
+	}
+	return CheckConfig{
+		StoreConfig: storeConfig,
+		repair:      repair,
+	}, nil
+}
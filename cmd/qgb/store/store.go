@@ -0,0 +1,242 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/celestiaorg/orchestrator-relayer/cmd/qgb/base"
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	"github.com/celestiaorg/orchestrator-relayer/store"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/spf13/cobra"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+// backupPerms the backup archive permissions, only readable by the owner as it contains the keystores.
+const backupPerms = 0o600
+
+func Command(serviceName string) *cobra.Command {
+	storeCmd := &cobra.Command{
+		Use:          "store",
+		Short:        "QGB store manager",
+		SilenceUsage: true,
+	}
+
+	storeCmd.AddCommand(
+		Backup(serviceName),
+		Restore(serviceName),
+		Check(serviceName),
+	)
+
+	storeCmd.SetHelpCommand(&cobra.Command{})
+
+	return storeCmd
+}
+
+func Backup(serviceName string) *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "backup",
+		Short: "back up the store, i.e. the data and signature stores, the keystores and the metadata, into a single archive. The store can be in use by a running process",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseBackupFlags(cmd, serviceName)
+			if err != nil {
+				return err
+			}
+
+			logger, err := base.NewLogger(os.Stdout, config.log)
+			if err != nil {
+				return err
+			}
+
+			if !store.Exists(config.home) {
+				return store.ErrNotInited
+			}
+
+			out, err := os.OpenFile(config.out, os.O_CREATE|os.O_WRONLY|os.O_EXCL, backupPerms)
+			if err != nil {
+				return err
+			}
+			err = store.Backup(logger, config.home, out)
+			if err != nil {
+				out.Close()
+				os.Remove(config.out) //nolint: errcheck
+				return err
+			}
+			err = out.Close()
+			if err != nil {
+				return err
+			}
+
+			logger.Info("store backed up successfully. the archive contains the private keys, keep it safe", "path", config.home, "out", config.out)
+			return nil
+		},
+	}
+	return addBackupFlags(&cmd, serviceName)
+}
+
+func Restore(serviceName string) *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "restore <backup_file>",
+		Short: "restore a store backup into a new home directory",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseStoreConfigFlags(cmd, serviceName)
+			if err != nil {
+				return err
+			}
+
+			logger, err := base.NewLogger(os.Stdout, config.log)
+			if err != nil {
+				return err
+			}
+
+			in, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer in.Close()
+
+			err = store.Restore(logger, config.home, in)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("store restored successfully", "path", config.home, "backup", args[0])
+			return nil
+		},
+	}
+	return storeConfigFlags(&cmd, serviceName)
+}
+
+func Check(serviceName string) *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "check",
+		Short: "verify the store databases and confirms, and report the orphaned lock files left after an unclean shutdown. The store should not be in use",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := parseCheckFlags(cmd, serviceName)
+			if err != nil {
+				return err
+			}
+
+			logger, err := base.NewLogger(os.Stdout, config.log)
+			if err != nil {
+				return err
+			}
+
+			report, err := store.Check(logger, config.home, config.repair)
+			if err != nil {
+				return err
+			}
+			for _, lock := range report.OrphanedLocks {
+				if config.repair {
+					logger.Info("removed orphaned lock file", "path", lock)
+				} else {
+					logger.Info("found orphaned lock file", "path", lock)
+				}
+			}
+			for _, db := range report.Databases {
+				switch {
+				case db.Err != nil:
+					logger.Error("corrupted database", "path", db.Path, "err", db.Err.Error())
+				case db.Repaired:
+					logger.Info("repaired database by truncating its value log", "path", db.Path)
+				default:
+					logger.Info("database verified", "path", db.Path)
+				}
+			}
+			if !report.Healthy() {
+				if config.repair {
+					return fmt.Errorf("%w: couldn't repair the corrupted databases", store.ErrStoreCheckFailed)
+				}
+				return fmt.Errorf("%w: corrupted databases. run with --%s to repair them", store.ErrStoreCheckFailed, FlagCheckRepair)
+			}
+
+			invalid, err := checkConfirms(cmd.Context(), logger, config)
+			if err != nil {
+				return err
+			}
+			if invalid != 0 && !config.repair {
+				return fmt.Errorf("%w: %d invalid records. run with --%s to remove them", store.ErrStoreCheckFailed, invalid, FlagCheckRepair)
+			}
+
+			logger.Info("store checked successfully", "path", config.home)
+			return nil
+		},
+	}
+	return addCheckFlags(&cmd, serviceName)
+}
+
+// checkConfirms checks the confirms of the data and signature stores, if they exist, and removes the
+// invalid ones if repairing. The stores are opened using the backend that wrote them, and are only
+// written to when repairing.
+// Returns the number of invalid confirms.
+func checkConfirms(ctx context.Context, logger tmlog.Logger, config CheckConfig) (int, error) {
+	dataBackend, hasDataStore, err := store.DetectBackend(filepath.Join(config.home, store.DataPath))
+	if err != nil {
+		return 0, err
+	}
+	signatureBackend, hasSignatureStore, err := store.DetectBackend(filepath.Join(config.home, store.SignaturePath))
+	if err != nil {
+		return 0, err
+	}
+	backend := dataBackend
+	switch {
+	case !hasDataStore && !hasSignatureStore:
+		return 0, nil
+	case !hasDataStore:
+		backend = signatureBackend
+	case hasSignatureStore && signatureBackend != dataBackend:
+		return 0, fmt.Errorf("%w: data store written by %s, signature store written by %s", store.ErrBackendMismatch, dataBackend, signatureBackend)
+	}
+
+	openOptions := store.OpenOptions{
+		HasDataStore:      hasDataStore,
+		Backend:           backend,
+		BadgerOptions:     store.DefaultBadgerOptions(config.home),
+		HasSignatureStore: hasSignatureStore,
+		ReadOnly:          !config.repair,
+	}
+	s, err := store.OpenStore(logger, config.home, openOptions)
+	if errors.Is(err, store.ErrOutdatedStoreVersion) {
+		logger.Info("skipping the confirms verification of a store that needs to be migrated. run with --"+FlagCheckRepair+" to migrate it", "err", err.Error())
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		err := s.Close(logger, openOptions)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+	}()
+
+	invalid := 0
+	for _, db := range []struct {
+		name  string
+		store ds.Batching
+		check func(context.Context, ds.Batching, bool) (p2p.CheckResult, error)
+	}{
+		{name: "data store", store: s.DataStore, check: p2p.CheckDHTConfirms},
+		{name: "signature store", store: s.SignatureStore, check: p2p.CheckArchivedConfirms},
+	} {
+		if db.store == nil {
+			continue
+		}
+		result, err := db.check(ctx, db.store, config.repair)
+		if err != nil {
+			return 0, err
+		}
+		for _, record := range result.Invalid {
+			logger.Error("invalid record", "store", db.name, "key", record.Key, "err", record.Err.Error())
+		}
+		logger.Info("checked confirms", "store", db.name, "checked", result.Checked, "invalid", len(result.Invalid), "removed", result.Removed)
+		invalid += len(result.Invalid)
+	}
+	return invalid, nil
+}
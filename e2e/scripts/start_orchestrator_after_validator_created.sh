@@ -45,7 +45,7 @@ done
 if [[ -z "${P2P_BOOTSTRAPPERS}" ]]
 then
   # import the p2p key to use
-  /bin/qgb orchestrator keys p2p import key "${P2P_IDENTITY}"
+  /bin/qgb orchestrator keys p2p import key "${P2P_IDENTITY}" --p2p.passphrase=123
 
   /bin/qgb orchestrator start \
     --evm.account="${EVM_ACCOUNT}" \
@@ -55,7 +55,8 @@ then
     --core.grpc.port="${CORE_GRPC_PORT}" \
     --p2p.nickname=key \
     --p2p.listen-addr="${P2P_LISTEN}" \
-    --evm.passphrase=123
+    --evm.passphrase=123 \
+    --p2p.passphrase=123
 else
   # to give time for the bootstrappers to be up
   sleep 5s
@@ -68,5 +69,6 @@ else
     --core.grpc.port="${CORE_GRPC_PORT}" \
     --p2p.listen-addr="${P2P_LISTEN}" \
     --p2p.bootstrappers="${P2P_BOOTSTRAPPERS}" \
-    --evm.passphrase=123
+    --evm.passphrase=123 \
+    --p2p.passphrase=123
 fi
@@ -62,4 +62,5 @@ sleep 5s
   --evm.contract-address="${QGB_CONTRACT}" \
   --p2p.bootstrappers="${P2P_BOOTSTRAPPERS}" \
   --p2p.listen-addr="${P2P_LISTEN}" \
-  --evm.passphrase=123
+  --evm.passphrase=123 \
+  --p2p.passphrase=123
@@ -38,7 +38,7 @@ func ConnectToDHT(ctx context.Context, h host.Host, dht *p2p.QgbDHT, target peer
 		case <-timeout.C:
 			return errors.New("couldn't connect to dht")
 		default:
-			if len(dht.RoutingTable().ListPeers()) == 0 {
+			if len(dht.ListPeers()) == 0 {
 				if h.Connect(ctx, target) == nil {
 					return nil
 				}
@@ -192,19 +192,156 @@ func (ec *Client) StateLastEventNonce(opts *bind.CallOpts) (uint64, error) {
 	return nonce.Uint64(), nil
 }
 
+// VerifyAttestation calls the QGB contract to verify that the provided data root tuple is committed to
+// by the data root tuple root having the provided nonce.
+func (ec *Client) VerifyAttestation(
+	opts *bind.CallOpts,
+	tupleRootNonce uint64,
+	tuple wrapper.DataRootTuple,
+	proof wrapper.BinaryMerkleProof,
+) (bool, error) {
+	return ec.Wrapper.VerifyAttestation(opts, big.NewInt(int64(tupleRootNonce)), tuple, proof)
+}
+
+// FilterDataRootTupleRootEvents returns all the DataRootTupleRootEvent logs emitted by the QGB contract
+// in the blocks range defined by the filter options.
+// If the nonces slice is not empty, only the events having those nonces will be returned.
+func (ec *Client) FilterDataRootTupleRootEvents(
+	opts *bind.FilterOpts,
+	nonces []*big.Int,
+) ([]wrapper.QuantumGravityBridgeDataRootTupleRootEvent, error) {
+	it, err := ec.Wrapper.FilterDataRootTupleRootEvent(opts, nonces)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	events := make([]wrapper.QuantumGravityBridgeDataRootTupleRootEvent, 0)
+	for it.Next() {
+		events = append(events, *it.Event)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// FilterValidatorSetUpdatedEvents returns all the ValidatorSetUpdatedEvent logs emitted by the QGB contract
+// in the blocks range defined by the filter options.
+// If the nonces slice is not empty, only the events having those nonces will be returned.
+func (ec *Client) FilterValidatorSetUpdatedEvents(
+	opts *bind.FilterOpts,
+	nonces []*big.Int,
+) ([]wrapper.QuantumGravityBridgeValidatorSetUpdatedEvent, error) {
+	it, err := ec.Wrapper.FilterValidatorSetUpdatedEvent(opts, nonces)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	events := make([]wrapper.QuantumGravityBridgeValidatorSetUpdatedEvent, 0)
+	for it.Next() {
+		events = append(events, *it.Event)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// DefaultEventsFilterWindow the maximum number of EVM blocks requested at once when filtering the QGB
+// contract events, as the RPC providers usually limit the blocks range of `eth_getLogs`.
+const DefaultEventsFilterWindow = uint64(5000)
+
+// FilterDataRootTupleRootEventsInRange returns the DataRootTupleRootEvent logs emitted by the QGB contract
+// between the start and end blocks, inclusive. The range is requested by windows of DefaultEventsFilterWindow blocks.
+// If the nonces slice is not empty, only the events having those nonces will be returned.
+func (ec *Client) FilterDataRootTupleRootEventsInRange(
+	ctx context.Context,
+	start uint64,
+	end uint64,
+	nonces []*big.Int,
+) ([]wrapper.QuantumGravityBridgeDataRootTupleRootEvent, error) {
+	events := make([]wrapper.QuantumGravityBridgeDataRootTupleRootEvent, 0)
+	err := forEachBlocksWindow(start, end, DefaultEventsFilterWindow, func(windowStart uint64, windowEnd uint64) error {
+		windowEvents, err := ec.FilterDataRootTupleRootEvents(&bind.FilterOpts{Start: windowStart, End: &windowEnd, Context: ctx}, nonces)
+		if err != nil {
+			return err
+		}
+		events = append(events, windowEvents...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// FilterValidatorSetUpdatedEventsInRange returns the ValidatorSetUpdatedEvent logs emitted by the QGB contract
+// between the start and end blocks, inclusive. The range is requested by windows of DefaultEventsFilterWindow blocks.
+// If the nonces slice is not empty, only the events having those nonces will be returned.
+func (ec *Client) FilterValidatorSetUpdatedEventsInRange(
+	ctx context.Context,
+	start uint64,
+	end uint64,
+	nonces []*big.Int,
+) ([]wrapper.QuantumGravityBridgeValidatorSetUpdatedEvent, error) {
+	events := make([]wrapper.QuantumGravityBridgeValidatorSetUpdatedEvent, 0)
+	err := forEachBlocksWindow(start, end, DefaultEventsFilterWindow, func(windowStart uint64, windowEnd uint64) error {
+		windowEvents, err := ec.FilterValidatorSetUpdatedEvents(&bind.FilterOpts{Start: windowStart, End: &windowEnd, Context: ctx}, nonces)
+		if err != nil {
+			return err
+		}
+		events = append(events, windowEvents...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// forEachBlocksWindow calls f for every window of at most windowSize blocks, in increasing order,
+// covering the start and end blocks range, inclusive.
+func forEachBlocksWindow(start uint64, end uint64, windowSize uint64, f func(windowStart uint64, windowEnd uint64) error) error {
+	for windowStart := start; windowStart <= end; windowStart += windowSize {
+		windowEnd := windowStart + windowSize - 1
+		if windowEnd > end || windowEnd < windowStart {
+			windowEnd = end
+		}
+		err := f(windowStart, windowEnd)
+		if err != nil {
+			return err
+		}
+		if windowEnd == end {
+			return nil
+		}
+	}
+	return nil
+}
+
+// LatestBlockNumber returns the number of the latest block of the EVM chain.
+func LatestBlockNumber(ctx context.Context, backend bind.ContractBackend) (uint64, error) {
+	header, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	return header.Number.Uint64(), nil
+}
+
 func (ec *Client) WaitForTransaction(
 	ctx context.Context,
 	backend bind.DeployBackend,
 	tx *coregethtypes.Transaction,
 ) (*coregethtypes.Receipt, error) {
-	ec.logger.Debug("waiting for transaction to be confirmed", "hash", tx.Hash().String())
+	ec.logger.Debug("waiting for transaction to be confirmed", "tx_hash", tx.Hash().String())
 
 	receipt, err := bind.WaitMined(ctx, backend, tx)
 	if err == nil && receipt != nil && receipt.Status == 1 {
-		ec.logger.Info("transaction confirmed", "hash", tx.Hash().String(), "block", receipt.BlockNumber.Uint64())
+		ec.logger.Info("transaction confirmed", "tx_hash", tx.Hash().String(), "block", receipt.BlockNumber.Uint64())
 		return receipt, nil
 	}
-	ec.logger.Error("transaction failed", "hash", tx.Hash().String())
+	ec.logger.Error("transaction failed", "tx_hash", tx.Hash().String())
 
 	return receipt, err
 }
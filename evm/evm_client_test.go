@@ -4,6 +4,7 @@ import (
 	"context"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 
 	celestiatypes "github.com/celestiaorg/celestia-app/x/qgb/types"
@@ -143,3 +144,80 @@ func (s *EVMTestSuite) TestUpdateValset() {
 	// check that the validator set was changed.
 	s.Equal(uint64(2), nonce)
 }
+
+func (s *EVMTestSuite) TestFilterEvents() {
+	// deploy a new bridge contract
+	_, _, _, err := s.Client.DeployQGBContract(s.Chain.Auth, s.Chain.Backend, *s.InitVs, 1, true)
+	s.NoError(err)
+	s.Chain.Backend.Commit()
+
+	commitment := ethcmn.HexToHash("0x12345")
+	signBytes := types.DataCommitmentTupleRootSignBytes(
+		big.NewInt(2),
+		commitment[:],
+	)
+
+	ks := keystore.NewKeyStore(s.T().TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	acc, err := ks.ImportECDSA(s.VsPrivateKey, "123")
+	s.NoError(err)
+	err = ks.Unlock(acc, "123")
+	s.NoError(err)
+
+	signature, err := evm.NewEthereumSignature(signBytes.Bytes(), ks, acc)
+	s.NoError(err)
+	v, r, ss, err := evm.SigToVRS(ethcmn.Bytes2Hex(signature))
+	s.NoError(err)
+
+	_, err = s.Client.SubmitDataRootTupleRoot(
+		s.Chain.Auth,
+		commitment,
+		2,
+		*s.InitVs,
+		[]wrapper.Signature{{V: v, R: r, S: ss}},
+	)
+	s.NoError(err)
+	s.Chain.Backend.Commit()
+
+	// the contract deployment emits a valset updated event with the initial valset
+	vsEvents, err := s.Client.FilterValidatorSetUpdatedEvents(&bind.FilterOpts{Start: 0}, nil)
+	s.NoError(err)
+	s.Require().Len(vsEvents, 1)
+	s.Equal(uint64(1), vsEvents[0].Nonce.Uint64())
+	vsHash, err := s.InitVs.Hash()
+	s.NoError(err)
+	s.Equal(vsHash, ethcmn.Hash(vsEvents[0].ValidatorSetHash))
+
+	dcEvents, err := s.Client.FilterDataRootTupleRootEvents(&bind.FilterOpts{Start: 0}, nil)
+	s.NoError(err)
+	s.Require().Len(dcEvents, 1)
+	s.Equal(uint64(2), dcEvents[0].Nonce.Uint64())
+	s.Equal(commitment, ethcmn.Hash(dcEvents[0].DataRootTupleRoot))
+
+	// filtering using a nonce that was never emitted
+	dcEvents, err = s.Client.FilterDataRootTupleRootEvents(&bind.FilterOpts{Start: 0}, []*big.Int{big.NewInt(3)})
+	s.NoError(err)
+	s.Empty(dcEvents)
+}
+
+func (s *EVMTestSuite) TestFilterEventsInRange() {
+	_, _, _, err := s.Client.DeployQGBContract(s.Chain.Auth, s.Chain.Backend, *s.InitVs, 1, true)
+	s.NoError(err)
+	s.Chain.Backend.Commit()
+
+	latestBlock, err := evm.LatestBlockNumber(context.Background(), s.Chain.Backend)
+	s.NoError(err)
+
+	vsEvents, err := s.Client.FilterValidatorSetUpdatedEventsInRange(context.Background(), 0, latestBlock, nil)
+	s.NoError(err)
+	s.Require().Len(vsEvents, 1)
+	s.Equal(uint64(1), vsEvents[0].Nonce.Uint64())
+
+	// the deployment block is excluded
+	vsEvents, err = s.Client.FilterValidatorSetUpdatedEventsInRange(context.Background(), latestBlock+1, latestBlock+1, nil)
+	s.NoError(err)
+	s.Empty(vsEvents)
+
+	dcEvents, err := s.Client.FilterDataRootTupleRootEventsInRange(context.Background(), 0, latestBlock, nil)
+	s.NoError(err)
+	s.Empty(dcEvents)
+}
@@ -1,6 +1,8 @@
 package helpers
 
 import (
+	"strings"
+
 	"github.com/libp2p/go-libp2p/core/peer"
 	tmlog "github.com/tendermint/tendermint/libs/log"
 )
@@ -18,3 +20,20 @@ func ParseAddrInfos(logger tmlog.Logger, addrs []string) ([]peer.AddrInfo, error
 	}
 	return infos, nil
 }
+
+// ParsePeerIDs converts comma-separated strings to peer IDs.
+// Returns an empty slice if the input is empty.
+func ParsePeerIDs(ids string) ([]peer.ID, error) {
+	peerIDs := make([]peer.ID, 0)
+	if ids == "" {
+		return peerIDs, nil
+	}
+	for _, id := range strings.Split(ids, ",") {
+		peerID, err := peer.Decode(strings.TrimSpace(id))
+		if err != nil {
+			return nil, err
+		}
+		peerIDs = append(peerIDs, peerID)
+	}
+	return peerIDs, nil
+}
@@ -63,3 +63,18 @@ func TestParseAddrInfos(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePeerIDs(t *testing.T) {
+	got, err := ParsePeerIDs("")
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+
+	got, err = ParsePeerIDs("12D3KooWHr2wqFAsMXnPzpFsgxmePgXb8BqpkePebwUgLyZc95bd, 12D3KooWDgG69kXfmSiHjUErN2ahpUC1SXpSfB2urrqMZ6aWC8NS")
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "12D3KooWHr2wqFAsMXnPzpFsgxmePgXb8BqpkePebwUgLyZc95bd", got[0].String())
+	assert.Equal(t, "12D3KooWDgG69kXfmSiHjUErN2ahpUC1SXpSfB2urrqMZ6aWC8NS", got[1].String())
+
+	_, err = ParsePeerIDs("invalid-peer-id")
+	assert.Error(t, err)
+}
@@ -6,26 +6,60 @@ import (
 	"github.com/celestiaorg/orchestrator-relayer/p2p"
 
 	"github.com/celestiaorg/orchestrator-relayer/types"
+	tmlog "github.com/tendermint/tendermint/libs/log"
 )
 
 type Broadcaster struct {
 	QgbDHT *p2p.QgbDHT
+	// PubSub optional gossipsub router used to propagate the confirms alongside the DHT.
+	PubSub *p2p.QgbPubSub
+	logger tmlog.Logger
 }
 
 func NewBroadcaster(qgbDHT *p2p.QgbDHT) *Broadcaster {
-	return &Broadcaster{QgbDHT: qgbDHT}
+	return &Broadcaster{QgbDHT: qgbDHT, logger: tmlog.NewNopLogger()}
+}
+
+// WithPubSub sets the gossipsub router used to publish the confirms after putting them in the DHT.
+// Publishing is best effort: as the confirms are already in the DHT, the publishing errors are only logged
+// using the provided logger.
+func (b *Broadcaster) WithPubSub(ps *p2p.QgbPubSub, logger tmlog.Logger) {
+	b.PubSub = ps
+	b.logger = logger
 }
 
 func (b Broadcaster) ProvideDataCommitmentConfirm(ctx context.Context, nonce uint64, confirm types.DataCommitmentConfirm, dataRootTupleRoot string) error {
-	if len(b.QgbDHT.RoutingTable().ListPeers()) == 0 {
+	if len(b.QgbDHT.ListPeers()) == 0 {
 		return ErrEmptyPeersTable
 	}
-	return b.QgbDHT.PutDataCommitmentConfirm(ctx, p2p.GetDataCommitmentConfirmKey(nonce, confirm.EthAddress, dataRootTupleRoot), confirm)
+	key := p2p.GetDataCommitmentConfirmKey(nonce, confirm.EthAddress, dataRootTupleRoot)
+	err := b.QgbDHT.PutDataCommitmentConfirm(ctx, key, confirm)
+	if err != nil {
+		return err
+	}
+	if b.PubSub != nil {
+		err = b.PubSub.PublishDataCommitmentConfirm(ctx, key, confirm)
+		if err != nil {
+			b.logger.Error("failed to publish data commitment confirm", "nonce", nonce, "key", key, "err", err.Error())
+		}
+	}
+	return nil
 }
 
 func (b Broadcaster) ProvideValsetConfirm(ctx context.Context, nonce uint64, confirm types.ValsetConfirm, signBytes string) error {
-	if len(b.QgbDHT.RoutingTable().ListPeers()) == 0 {
+	if len(b.QgbDHT.ListPeers()) == 0 {
 		return ErrEmptyPeersTable
 	}
-	return b.QgbDHT.PutValsetConfirm(ctx, p2p.GetValsetConfirmKey(nonce, confirm.EthAddress, signBytes), confirm)
+	key := p2p.GetValsetConfirmKey(nonce, confirm.EthAddress, signBytes)
+	err := b.QgbDHT.PutValsetConfirm(ctx, key, confirm)
+	if err != nil {
+		return err
+	}
+	if b.PubSub != nil {
+		err = b.PubSub.PublishValsetConfirm(ctx, key, confirm)
+		if err != nil {
+			b.logger.Error("failed to publish valset confirm", "nonce", nonce, "key", key, "err", err.Error())
+		}
+	}
+	return nil
 }
@@ -20,6 +20,7 @@ import (
 	qgbtesting "github.com/celestiaorg/orchestrator-relayer/testing"
 	"github.com/celestiaorg/orchestrator-relayer/types"
 	"github.com/stretchr/testify/assert"
+	tmlog "github.com/tendermint/tendermint/libs/log"
 )
 
 var (
@@ -135,3 +136,39 @@ func TestEmptyPeersTable(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, orchestrator.ErrEmptyPeersTable, err)
 }
+
+// TestBroadcastPublishFailure tests that failing to publish a confirm to gossipsub
+// doesn't fail the broadcast once the confirm is in the DHT.
+func TestBroadcastPublishFailure(t *testing.T) {
+	network := qgbtesting.NewDHTNetwork(context.Background(), 4)
+	defer network.Stop()
+
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	acc, err := ks.ImportECDSA(privateKey, "123")
+	require.NoError(t, err)
+	err = ks.Unlock(acc, "123")
+	require.NoError(t, err)
+
+	nonce := uint64(10)
+	commitment := "1234"
+	bCommitment, _ := hex.DecodeString(commitment)
+	dataRootHash := types.DataCommitmentTupleRootSignBytes(big.NewInt(int64(nonce)), bCommitment)
+	signature, err := evm.NewEthereumSignature(dataRootHash.Bytes(), ks, acc)
+	require.NoError(t, err)
+	expectedConfirm := types.NewDataCommitmentConfirm(hex.EncodeToString(signature), common.HexToAddress(evmAddress))
+	testKey := p2p.GetDataCommitmentConfirmKey(nonce, evmAddress, dataRootHash.Hex())
+
+	// closing the pubsub topics so that publishing fails
+	ps, err := p2p.NewQgbPubSub(context.Background(), network.Hosts[1], tmlog.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, ps.Close())
+
+	broadcaster := orchestrator.NewBroadcaster(network.DHTs[1])
+	broadcaster.WithPubSub(ps, tmlog.NewNopLogger())
+	err = broadcaster.ProvideDataCommitmentConfirm(context.Background(), nonce, *expectedConfirm, dataRootHash.Hex())
+	assert.NoError(t, err)
+
+	actualConfirm, err := network.DHTs[3].GetDataCommitmentConfirm(context.Background(), testKey)
+	assert.NoError(t, err)
+	assert.Equal(t, *expectedConfirm, actualConfirm)
+}
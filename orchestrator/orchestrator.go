@@ -203,41 +203,10 @@ func (orch Orchestrator) EnqueueMissingEvents(
 		return err
 	}
 
-	lastUnbondingHeight, err := orch.AppQuerier.QueryLastUnbondingHeight(ctx)
+	startingNonce, err := UnbondingStartingNonce(ctx, orch.AppQuerier)
 	if err != nil {
 		return err
 	}
-	var startingNonce uint64
-	if lastUnbondingHeight == 0 {
-		// chain startup case
-		startingNonce = 1
-	} else {
-		lastDc, err := orch.AppQuerier.QueryLatestDataCommitment(ctx)
-		if err != nil {
-			return err
-		}
-		if lastUnbondingHeight >= int64(lastDc.EndBlock) {
-			// if no data commitment has committed to the last unbonding height,
-			// then, the orchestrator should start signing at the latest valset
-			vs, err := orch.AppQuerier.QueryLatestValset(ctx)
-			if err != nil {
-				return err
-			}
-			startingNonce = vs.Nonce
-		} else {
-			// some data commitment has committed to the last unbonding height,
-			// so, we start signing from the valset that attests to that one
-			dc, err := orch.AppQuerier.QueryDataCommitmentForHeight(ctx, uint64(lastUnbondingHeight))
-			if err != nil {
-				return err
-			}
-			startingValset, err := orch.AppQuerier.QueryLastValsetBeforeNonce(ctx, dc.Nonce)
-			if err != nil {
-				return err
-			}
-			startingNonce = startingValset.Nonce
-		}
-	}
 
 	orch.Logger.Info("syncing missing nonces", "latest_nonce", latestNonce, "first_nonce", startingNonce)
 
@@ -267,6 +236,44 @@ func (orch Orchestrator) EnqueueMissingEvents(
 	return nil
 }
 
+// UnbondingStartingNonce returns the nonce from which the orchestrators sign the attestations when they start,
+// i.e. the valset attesting to the last unbonding height. The confirms of the nonces starting from this one
+// are still needed, and are signed again if they're missing.
+func UnbondingStartingNonce(ctx context.Context, appQuerier *rpc.AppQuerier) (uint64, error) {
+	lastUnbondingHeight, err := appQuerier.QueryLastUnbondingHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if lastUnbondingHeight == 0 {
+		// chain startup case
+		return 1, nil
+	}
+	lastDc, err := appQuerier.QueryLatestDataCommitment(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if lastUnbondingHeight >= int64(lastDc.EndBlock) {
+		// if no data commitment has committed to the last unbonding height,
+		// then, the orchestrator should start signing at the latest valset
+		vs, err := appQuerier.QueryLatestValset(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return vs.Nonce, nil
+	}
+	// some data commitment has committed to the last unbonding height,
+	// so, we start signing from the valset that attests to that one
+	dc, err := appQuerier.QueryDataCommitmentForHeight(ctx, uint64(lastUnbondingHeight))
+	if err != nil {
+		return 0, err
+	}
+	startingValset, err := appQuerier.QueryLastValsetBeforeNonce(ctx, dc.Nonce)
+	if err != nil {
+		return 0, err
+	}
+	return startingValset.Nonce, nil
+}
+
 func (orch Orchestrator) ProcessNonces(
 	ctx context.Context,
 	noncesQueue <-chan uint64,
@@ -0,0 +1,117 @@
+package p2p
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/celestiaorg/orchestrator-relayer/evm"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+const (
+	// AuthProtocolID the protocol used by peers to prove control of an EVM address.
+	AuthProtocolID = protocol.ID(ProtocolPrefix + "/auth")
+	// PeerAuthDomainSeparator the domain separator used when signing a peer ID to avoid
+	// re-using the signature in other contexts.
+	PeerAuthDomainSeparator = "qgb-peer-auth"
+	// maxPeerAuthenticationSize the maximum size of an encoded peer authentication.
+	maxPeerAuthenticationSize = 1024
+	// authStreamTimeout the time allowed to exchange the peer authentication.
+	authStreamTimeout = 10 * time.Second
+)
+
+// PeerAuthentication proves that the peer sending it controls the EVM address
+// by signing its libp2p peer ID.
+type PeerAuthentication struct {
+	// Hex `0x` encoded Ethereum address.
+	EVMAddress string
+	// Hex encoded signature over the peer ID sign bytes.
+	Signature string
+}
+
+// PeerIDSignBytes the digest to be signed over by a peer to prove control of an EVM address.
+func PeerIDSignBytes(id peer.ID) ethcmn.Hash {
+	return crypto.Keccak256Hash([]byte(PeerAuthDomainSeparator), []byte(id))
+}
+
+// NewPeerAuthentication signs the provided peer ID using the provided EVM account.
+// The account should be unlocked.
+func NewPeerAuthentication(ks *keystore.KeyStore, acc accounts.Account, id peer.ID) (*PeerAuthentication, error) {
+	signature, err := evm.NewEthereumSignature(PeerIDSignBytes(id).Bytes(), ks, acc)
+	if err != nil {
+		return nil, err
+	}
+	return &PeerAuthentication{
+		EVMAddress: acc.Address.Hex(),
+		Signature:  hex.EncodeToString(signature),
+	}, nil
+}
+
+// VerifyPeerAuthentication checks that the peer authentication was signed by the
+// EVM address over the provided peer ID.
+func VerifyPeerAuthentication(id peer.ID, auth PeerAuthentication) error {
+	if !ethcmn.IsHexAddress(auth.EVMAddress) {
+		return ErrInvalidEVMAddress
+	}
+	signature := auth.Signature
+	if len(signature) > 2 && signature[:2] == "0x" {
+		signature = signature[2:]
+	}
+	bSignature, err := hex.DecodeString(signature)
+	if err != nil {
+		return err
+	}
+	return evm.ValidateEthereumSignature(PeerIDSignBytes(id).Bytes(), bSignature, ethcmn.HexToAddress(auth.EVMAddress))
+}
+
+// SetAuthenticationHandler makes the host answer the peer authentication requests
+// with the provided authentication.
+// The authentication should be created using the host peer ID.
+func SetAuthenticationHandler(h host.Host, auth PeerAuthentication) error {
+	encoded, err := json.Marshal(auth)
+	if err != nil {
+		return err
+	}
+	h.SetStreamHandler(AuthProtocolID, func(s network.Stream) {
+		defer s.Close()
+		_ = s.SetWriteDeadline(time.Now().Add(authStreamTimeout))
+		_, err := s.Write(encoded)
+		if err != nil {
+			_ = s.Reset()
+		}
+	})
+	return nil
+}
+
+// RequestPeerAuthentication requests the peer authentication from the provided peer.
+// The returned authentication is not verified.
+func RequestPeerAuthentication(ctx context.Context, h host.Host, p peer.ID) (PeerAuthentication, error) {
+	ctx, cancel := context.WithTimeout(ctx, authStreamTimeout)
+	defer cancel()
+	s, err := h.NewStream(ctx, p, AuthProtocolID)
+	if err != nil {
+		return PeerAuthentication{}, err
+	}
+	defer s.Close()
+	_ = s.SetReadDeadline(time.Now().Add(authStreamTimeout))
+	encoded, err := io.ReadAll(io.LimitReader(s, maxPeerAuthenticationSize))
+	if err != nil {
+		return PeerAuthentication{}, err
+	}
+	var auth PeerAuthentication
+	err = json.Unmarshal(encoded, &auth)
+	if err != nil {
+		return PeerAuthentication{}, err
+	}
+	return auth, nil
+}
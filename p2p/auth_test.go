@@ -0,0 +1,43 @@
+package p2p_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerAuthentication(t *testing.T) {
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	acc, err := ks.ImportECDSA(privateKey, "123")
+	require.NoError(t, err)
+	err = ks.Unlock(acc, "123")
+	require.NoError(t, err)
+
+	h1, err := libp2p.New()
+	require.NoError(t, err)
+	defer h1.Close()
+	h2, err := libp2p.New()
+	require.NoError(t, err)
+	defer h2.Close()
+
+	auth, err := p2p.NewPeerAuthentication(ks, acc, h2.ID())
+	require.NoError(t, err)
+	assert.Equal(t, evmAddress, auth.EVMAddress)
+	assert.NoError(t, p2p.VerifyPeerAuthentication(h2.ID(), *auth))
+	// the authentication is only valid for the signed peer ID
+	assert.Error(t, p2p.VerifyPeerAuthentication(h1.ID(), *auth))
+
+	require.NoError(t, p2p.SetAuthenticationHandler(h2, *auth))
+	require.NoError(t, h1.Connect(context.Background(), peer.AddrInfo{ID: h2.ID(), Addrs: h2.Addrs()}))
+
+	receivedAuth, err := p2p.RequestPeerAuthentication(context.Background(), h1, h2.ID())
+	require.NoError(t, err)
+	assert.Equal(t, *auth, receivedAuth)
+	assert.NoError(t, p2p.VerifyPeerAuthentication(h2.ID(), receivedAuth))
+}
@@ -0,0 +1,259 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	celestiatypes "github.com/celestiaorg/celestia-app/x/qgb/types"
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	dht "github.com/libp2p/go-libp2p-kad-dht"
+	"github.com/tendermint/tendermint/libs/bytes"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	// MaxCachedAttestations the maximum number of attestations kept in memory by the chain view.
+	// When exceeded, the attestation with the lowest nonce is evicted.
+	MaxCachedAttestations = 1000
+	// chainQueryTimeout the time allowed to query Celestia when validating a confirm.
+	chainQueryTimeout = 15 * time.Second
+)
+
+// AttestationQuerier queries the Celestia attestations and valsets.
+// Implemented by `rpc.AppQuerier`.
+type AttestationQuerier interface {
+	QueryAttestationByNonce(ctx context.Context, nonce uint64) (celestiatypes.AttestationRequestI, error)
+	QueryValsetByNonce(ctx context.Context, nonce uint64) (*celestiatypes.Valset, error)
+	QueryLastValsetBeforeNonce(ctx context.Context, nonce uint64) (*celestiatypes.Valset, error)
+}
+
+// CommitmentQuerier queries the Celestia data commitments.
+// Implemented by `rpc.TmQuerier`.
+type CommitmentQuerier interface {
+	QueryCommitment(ctx context.Context, beginBlock uint64, endBlock uint64) (bytes.HexBytes, error)
+}
+
+// expectedConfirm what a confirm for a certain nonce should contain.
+type expectedConfirm struct {
+	// namespace the confirm namespace, depending on the attestation type.
+	namespace string
+	// digest the sign bytes for valsets, and the data root tuple root for data commitments.
+	digest ethcmn.Hash
+	// signers the EVM addresses, in lower case, of the valset that should sign the attestation.
+	signers map[string]struct{}
+}
+
+// ChainView a cached view of the Celestia attestations used to validate the confirms against
+// the chain state.
+// The attestations are immutable once created, so they're cached until evicted.
+type ChainView struct {
+	attestationQuerier AttestationQuerier
+	commitmentQuerier  CommitmentQuerier
+	logger             tmlog.Logger
+
+	mutex        *sync.Mutex
+	maxNonces    int
+	attestations map[uint64]expectedConfirm
+}
+
+// NewChainView creates a new ChainView using the provided queriers.
+func NewChainView(attestationQuerier AttestationQuerier, commitmentQuerier CommitmentQuerier, logger tmlog.Logger) *ChainView {
+	return &ChainView{
+		attestationQuerier: attestationQuerier,
+		commitmentQuerier:  commitmentQuerier,
+		logger:             logger,
+		mutex:              &sync.Mutex{},
+		maxNonces:          MaxCachedAttestations,
+		attestations:       make(map[uint64]expectedConfirm),
+	}
+}
+
+// ValidateConfirm checks that the confirm, defined by its key fields, is expected by the attestation
+// having the provided nonce:
+// - the namespace corresponds to the attestation type.
+// - the EVM address is part of the valset that should sign the attestation.
+// - the digest is the attestation sign bytes, or data root tuple root.
+// Returns an error if the attestation is not found.
+func (c *ChainView) ValidateConfirm(ctx context.Context, namespace string, nonce uint64, evmAddr string, digest string) error {
+	expected, err := c.expectedConfirm(ctx, nonce)
+	if err != nil {
+		return err
+	}
+	if expected.namespace != namespace {
+		return ErrInvalidConfirmNamespace
+	}
+	if _, ok := expected.signers[strings.ToLower(evmAddr)]; !ok {
+		return ErrNotAValidator
+	}
+	if expected.digest != ethcmn.HexToHash(digest) {
+		return ErrUnexpectedDigest
+	}
+	return nil
+}
+
+// DHTOptions returns the DHT options replacing the stateless confirm validators with chain aware ones.
+// They should be appended to the default options in `NewQgbDHT`.
+func (c *ChainView) DHTOptions() []dht.Option {
+	return []dht.Option{
+		dht.NamespacedValidator(DataCommitmentConfirmNamespace, ChainDataCommitmentConfirmValidator{ChainView: c}),
+		dht.NamespacedValidator(ValsetConfirmNamespace, ChainValsetConfirmValidator{ChainView: c}),
+	}
+}
+
+// expectedConfirm returns the expected confirm for the provided nonce, querying Celestia
+// if it is not cached.
+func (c *ChainView) expectedConfirm(ctx context.Context, nonce uint64) (expectedConfirm, error) {
+	c.mutex.Lock()
+	expected, found := c.attestations[nonce]
+	c.mutex.Unlock()
+	if found {
+		return expected, nil
+	}
+
+	expected, err := c.queryExpectedConfirm(ctx, nonce)
+	if err != nil {
+		return expectedConfirm{}, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.attestations[nonce] = expected
+	if len(c.attestations) > c.maxNonces {
+		lowest := nonce
+		for n := range c.attestations {
+			if n < lowest {
+				lowest = n
+			}
+		}
+		delete(c.attestations, lowest)
+	}
+	return expected, nil
+}
+
+func (c *ChainView) queryExpectedConfirm(ctx context.Context, nonce uint64) (expectedConfirm, error) {
+	att, err := c.attestationQuerier.QueryAttestationByNonce(ctx, nonce)
+	if err != nil {
+		return expectedConfirm{}, err
+	}
+	if att == nil {
+		return expectedConfirm{}, fmt.Errorf("%w: nonce %d", celestiatypes.ErrAttestationNotFound, nonce)
+	}
+
+	// the valset that should sign the attestation, which is the attestation valset itself for the first nonce.
+	// check `orchestrator.Process` for more details.
+	var signingValset *celestiatypes.Valset
+	if nonce == 1 {
+		signingValset, err = c.attestationQuerier.QueryValsetByNonce(ctx, nonce)
+	} else {
+		signingValset, err = c.attestationQuerier.QueryLastValsetBeforeNonce(ctx, nonce)
+	}
+	if err != nil {
+		return expectedConfirm{}, err
+	}
+	signers := make(map[string]struct{}, len(signingValset.Members))
+	for _, member := range signingValset.Members {
+		signers[strings.ToLower(member.EvmAddress)] = struct{}{}
+	}
+
+	switch castedAtt := att.(type) {
+	case *celestiatypes.Valset:
+		signBytes, err := castedAtt.SignBytes()
+		if err != nil {
+			return expectedConfirm{}, err
+		}
+		return expectedConfirm{namespace: ValsetConfirmNamespace, digest: signBytes, signers: signers}, nil
+	case *celestiatypes.DataCommitment:
+		commitment, err := c.commitmentQuerier.QueryCommitment(ctx, castedAtt.BeginBlock, castedAtt.EndBlock)
+		if err != nil {
+			return expectedConfirm{}, err
+		}
+		dataRootHash := types.DataCommitmentTupleRootSignBytes(big.NewInt(int64(castedAtt.Nonce)), commitment)
+		return expectedConfirm{namespace: DataCommitmentConfirmNamespace, digest: dataRootHash, signers: signers}, nil
+	default:
+		return expectedConfirm{}, types.ErrUnknownAttestationType
+	}
+}
+
+// validateAgainstChain checks the confirm key against the chain view.
+// Expects the key to be already validated by the stateless validators.
+func (c *ChainView) validateAgainstChain(key string) error {
+	namespace, nonce, evmAddr, digest, err := ParseKey(key)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), chainQueryTimeout)
+	defer cancel()
+	err = c.ValidateConfirm(ctx, namespace, nonce, evmAddr, digest)
+	if err != nil {
+		c.logger.Debug("rejecting confirm not matching the chain state", "key", key, "err", err.Error())
+		return err
+	}
+	return nil
+}
+
+// ChainValsetConfirmValidator runs the stateless checks of the ValsetConfirmValidator, then checks the confirm
+// against the Celestia valsets and attestations.
+type ChainValsetConfirmValidator struct {
+	ValsetConfirmValidator
+	ChainView *ChainView
+}
+
+// Validate runs the stateless checks then the chain aware checks on the provided confirm key and value.
+func (v ChainValsetConfirmValidator) Validate(key string, value []byte) error {
+	err := v.ValsetConfirmValidator.Validate(key, value)
+	if err != nil {
+		return err
+	}
+	return v.ChainView.validateAgainstChain(key)
+}
+
+// Select selects a valid dht confirm value from multiple ones.
+// returns an error of no valid value is found.
+func (v ChainValsetConfirmValidator) Select(key string, values [][]byte) (int, error) {
+	if len(values) == 0 {
+		return 0, ErrNoValues
+	}
+	for index, value := range values {
+		// choose the first correct value
+		if err := v.Validate(key, value); err == nil {
+			return index, nil
+		}
+	}
+	return 0, ErrNoValidValueFound
+}
+
+// ChainDataCommitmentConfirmValidator runs the stateless checks of the DataCommitmentConfirmValidator, then checks
+// the confirm against the Celestia valsets and attestations.
+type ChainDataCommitmentConfirmValidator struct {
+	DataCommitmentConfirmValidator
+	ChainView *ChainView
+}
+
+// Validate runs the stateless checks then the chain aware checks on the provided confirm key and value.
+func (v ChainDataCommitmentConfirmValidator) Validate(key string, value []byte) error {
+	err := v.DataCommitmentConfirmValidator.Validate(key, value)
+	if err != nil {
+		return err
+	}
+	return v.ChainView.validateAgainstChain(key)
+}
+
+// Select selects a valid dht confirm value from multiple ones.
+// returns an error of no valid value is found.
+func (v ChainDataCommitmentConfirmValidator) Select(key string, values [][]byte) (int, error) {
+	if len(values) == 0 {
+		return 0, ErrNoValues
+	}
+	for index, value := range values {
+		// choose the first correct value
+		if err := v.Validate(key, value); err == nil {
+			return index, nil
+		}
+	}
+	return 0, ErrNoValidValueFound
+}
@@ -0,0 +1,156 @@
+package p2p_test
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	celestiatypes "github.com/celestiaorg/celestia-app/x/qgb/types"
+	"github.com/celestiaorg/orchestrator-relayer/evm"
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/bytes"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+type mockChainQuerier struct {
+	attestations map[uint64]celestiatypes.AttestationRequestI
+	commitment   bytes.HexBytes
+	queries      int
+}
+
+func (m *mockChainQuerier) QueryAttestationByNonce(_ context.Context, nonce uint64) (celestiatypes.AttestationRequestI, error) {
+	m.queries++
+	return m.attestations[nonce], nil
+}
+
+func (m *mockChainQuerier) QueryValsetByNonce(_ context.Context, nonce uint64) (*celestiatypes.Valset, error) {
+	vs, ok := m.attestations[nonce].(*celestiatypes.Valset)
+	if !ok {
+		return nil, celestiatypes.ErrAttestationNotValsetRequest
+	}
+	return vs, nil
+}
+
+func (m *mockChainQuerier) QueryLastValsetBeforeNonce(_ context.Context, nonce uint64) (*celestiatypes.Valset, error) {
+	for n := nonce - 1; n > 0; n-- {
+		if vs, ok := m.attestations[n].(*celestiatypes.Valset); ok {
+			return vs, nil
+		}
+	}
+	return nil, celestiatypes.ErrAttestationNotFound
+}
+
+func (m *mockChainQuerier) QueryCommitment(_ context.Context, _ uint64, _ uint64) (bytes.HexBytes, error) {
+	return m.commitment, nil
+}
+
+func TestChainView(t *testing.T) {
+	vs1 := &celestiatypes.Valset{
+		Nonce:   1,
+		Members: []celestiatypes.BridgeValidator{{Power: 100, EvmAddress: ethAddr1.Hex()}},
+		Height:  1,
+		Time:    time.UnixMicro(10),
+	}
+	vs2 := &celestiatypes.Valset{
+		Nonce:   2,
+		Members: []celestiatypes.BridgeValidator{{Power: 100, EvmAddress: ethAddr2.Hex()}},
+		Height:  5,
+		Time:    time.UnixMicro(20),
+	}
+	dc := &celestiatypes.DataCommitment{Nonce: 3, BeginBlock: 1, EndBlock: 10}
+	querier := &mockChainQuerier{
+		attestations: map[uint64]celestiatypes.AttestationRequestI{1: vs1, 2: vs2, 3: dc},
+		commitment:   bytes.HexBytes{0x12, 0x34},
+	}
+	chainView := p2p.NewChainView(querier, querier, tmlog.NewNopLogger())
+	vcValidator := p2p.ChainValsetConfirmValidator{ChainView: chainView}
+	dccValidator := p2p.ChainDataCommitmentConfirmValidator{ChainView: chainView}
+
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	acc1, err := ks.ImportECDSA(privateKey1, "123")
+	require.NoError(t, err)
+	require.NoError(t, ks.Unlock(acc1, "123"))
+	acc2, err := ks.ImportECDSA(privateKey2, "123")
+	require.NoError(t, err)
+	require.NoError(t, ks.Unlock(acc2, "123"))
+
+	vs2SignBytes, err := vs2.SignBytes()
+	require.NoError(t, err)
+	dataRootHash := types.DataCommitmentTupleRootSignBytes(big.NewInt(int64(dc.Nonce)), querier.commitment)
+	wrongDigest := ethcmn.HexToHash("0x1234")
+
+	newValsetConfirm := func(acc accounts.Account, digest ethcmn.Hash) []byte {
+		signature, err := evm.NewEthereumSignature(digest.Bytes(), ks, acc)
+		require.NoError(t, err)
+		value, err := types.MarshalValsetConfirm(*types.NewValsetConfirm(acc.Address, hex.EncodeToString(signature)))
+		require.NoError(t, err)
+		return value
+	}
+	newDataCommitmentConfirm := func(acc accounts.Account, digest ethcmn.Hash) []byte {
+		signature, err := evm.NewEthereumSignature(digest.Bytes(), ks, acc)
+		require.NoError(t, err)
+		value, err := types.MarshalDataCommitmentConfirm(*types.NewDataCommitmentConfirm(hex.EncodeToString(signature), acc.Address))
+		require.NoError(t, err)
+		return value
+	}
+
+	// the valset 2 is signed by the members of valset 1
+	err = vcValidator.Validate(
+		p2p.GetValsetConfirmKey(2, ethAddr1.Hex(), vs2SignBytes.Hex()),
+		newValsetConfirm(acc1, vs2SignBytes),
+	)
+	assert.NoError(t, err)
+	// a signer not part of the valset 1
+	err = vcValidator.Validate(
+		p2p.GetValsetConfirmKey(2, ethAddr2.Hex(), vs2SignBytes.Hex()),
+		newValsetConfirm(acc2, vs2SignBytes),
+	)
+	assert.ErrorIs(t, err, p2p.ErrNotAValidator)
+	// a digest not matching the valset sign bytes
+	err = vcValidator.Validate(
+		p2p.GetValsetConfirmKey(2, ethAddr1.Hex(), wrongDigest.Hex()),
+		newValsetConfirm(acc1, wrongDigest),
+	)
+	assert.ErrorIs(t, err, p2p.ErrUnexpectedDigest)
+	// a valset confirm for a data commitment nonce
+	err = vcValidator.Validate(
+		p2p.GetValsetConfirmKey(3, ethAddr2.Hex(), dataRootHash.Hex()),
+		newValsetConfirm(acc2, dataRootHash),
+	)
+	assert.ErrorIs(t, err, p2p.ErrInvalidConfirmNamespace)
+
+	// the data commitment 3 is signed by the members of valset 2
+	err = dccValidator.Validate(
+		p2p.GetDataCommitmentConfirmKey(3, ethAddr2.Hex(), dataRootHash.Hex()),
+		newDataCommitmentConfirm(acc2, dataRootHash),
+	)
+	assert.NoError(t, err)
+	err = dccValidator.Validate(
+		p2p.GetDataCommitmentConfirmKey(3, ethAddr1.Hex(), dataRootHash.Hex()),
+		newDataCommitmentConfirm(acc1, dataRootHash),
+	)
+	assert.ErrorIs(t, err, p2p.ErrNotAValidator)
+	err = dccValidator.Validate(
+		p2p.GetDataCommitmentConfirmKey(3, ethAddr2.Hex(), wrongDigest.Hex()),
+		newDataCommitmentConfirm(acc2, wrongDigest),
+	)
+	assert.ErrorIs(t, err, p2p.ErrUnexpectedDigest)
+
+	// a confirm for an attestation that does not exist
+	err = dccValidator.Validate(
+		p2p.GetDataCommitmentConfirmKey(4, ethAddr2.Hex(), dataRootHash.Hex()),
+		newDataCommitmentConfirm(acc2, dataRootHash),
+	)
+	assert.ErrorIs(t, err, celestiatypes.ErrAttestationNotFound)
+
+	// the attestations are only queried once
+	assert.Equal(t, 3, querier.queries)
+}
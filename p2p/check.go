@@ -0,0 +1,140 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	recpb "github.com/libp2p/go-libp2p-record/pb"
+)
+
+// InvalidRecord a confirm record that failed the checks.
+type InvalidRecord struct {
+	// Key the datastore key of the record.
+	Key string
+	// Err the reason why the record is invalid.
+	Err error
+}
+
+// CheckResult the result of checking the confirms of a datastore.
+type CheckResult struct {
+	// Checked the number of checked confirms.
+	Checked int
+	// Invalid the confirms that failed the checks.
+	Invalid []InvalidRecord
+	// Removed the number of removed invalid confirms, when repairing.
+	Removed int
+}
+
+// CheckDHTConfirms checks that the confirms records of the DHT datastore, of all the protocol versions,
+// have a valid key and a value accepted by the namespace validators.
+// The other records, e.g. the peerstore ones, are not checked.
+// If repair is true, the invalid records are removed.
+func CheckDHTConfirms(ctx context.Context, store ds.Batching, repair bool) (CheckResult, error) {
+	return checkConfirms(ctx, store, repair, dhtRecordKey, func(confirmKey string, value []byte) ([]byte, error) {
+		record := new(recpb.Record)
+		err := record.Unmarshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dht record: %w", err)
+		}
+		if string(record.GetKey()) != confirmKey {
+			return nil, fmt.Errorf("%w: dht record key %s", ErrInvalidConfirmKey, string(record.GetKey()))
+		}
+		return record.GetValue(), nil
+	})
+}
+
+// CheckArchivedConfirms checks that the confirms of the relayer signature store have a valid key and a
+// value accepted by the namespace validators.
+// If repair is true, the invalid confirms are removed.
+func CheckArchivedConfirms(ctx context.Context, store ds.Batching, repair bool) (CheckResult, error) {
+	return checkConfirms(
+		ctx,
+		store,
+		repair,
+		func(key ds.Key) (string, bool) { return key.String(), true },
+		func(_ string, value []byte) ([]byte, error) { return value, nil },
+	)
+}
+
+// checkConfirms checks the confirms of the provided datastore.
+// The recordKey function returns the confirm key corresponding to a datastore key, or false if the
+// datastore key doesn't hold a confirm. The confirmValue function extracts the confirm from the
+// stored value.
+func checkConfirms(
+	ctx context.Context,
+	store ds.Batching,
+	repair bool,
+	recordKey func(ds.Key) (string, bool),
+	confirmValue func(confirmKey string, value []byte) ([]byte, error),
+) (CheckResult, error) {
+	results, err := store.Query(ctx, query.Query{})
+	if err != nil {
+		return CheckResult{}, err
+	}
+	entries, err := results.Rest()
+	if err != nil {
+		return CheckResult{}, err
+	}
+
+	result := CheckResult{}
+	for _, entry := range entries {
+		confirmKey, ok := recordKey(ds.RawKey(entry.Key))
+		if !ok || !isConfirmKey(confirmKey) {
+			continue
+		}
+		result.Checked++
+		err := validateConfirm(confirmKey, entry.Value, confirmValue)
+		if err != nil {
+			result.Invalid = append(result.Invalid, InvalidRecord{Key: entry.Key, Err: err})
+		}
+	}
+	if !repair || len(result.Invalid) == 0 {
+		return result, nil
+	}
+
+	batch, err := store.Batch(ctx)
+	if err != nil {
+		return result, err
+	}
+	for _, invalid := range result.Invalid {
+		err = batch.Delete(ctx, ds.RawKey(invalid.Key))
+		if err != nil {
+			return result, err
+		}
+	}
+	err = batch.Commit(ctx)
+	if err != nil {
+		return result, err
+	}
+	result.Removed = len(result.Invalid)
+	return result, nil
+}
+
+// isConfirmKey returns true if the provided record key is under one of the confirms namespaces.
+func isConfirmKey(key string) bool {
+	return strings.HasPrefix(key, "/"+ValsetConfirmNamespace+"/") ||
+		strings.HasPrefix(key, "/"+DataCommitmentConfirmNamespace+"/")
+}
+
+// validateConfirm parses the confirm key and validates the confirm using its namespace validator.
+func validateConfirm(confirmKey string, value []byte, confirmValue func(string, []byte) ([]byte, error)) error {
+	namespace, _, _, _, err := ParseKey(confirmKey)
+	if err != nil {
+		return err
+	}
+	confirm, err := confirmValue(confirmKey, value)
+	if err != nil {
+		return err
+	}
+	switch namespace {
+	case ValsetConfirmNamespace:
+		return ValsetConfirmValidator{}.Validate(confirmKey, confirm)
+	case DataCommitmentConfirmNamespace:
+		return DataCommitmentConfirmValidator{}.Validate(confirmKey, confirm)
+	default:
+		return ErrInvalidConfirmNamespace
+	}
+}
@@ -0,0 +1,106 @@
+package p2p_test
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/evm"
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	ds "github.com/ipfs/go-datastore"
+	recpb "github.com/libp2p/go-libp2p-record/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newValsetConfirm returns a valset confirm, signed by the test EVM address, for the provided digest.
+func newValsetConfirm(t *testing.T, digest common.Hash) []byte {
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	acc, err := ks.ImportECDSA(privateKey, "123")
+	require.NoError(t, err)
+	require.NoError(t, ks.Unlock(acc, "123"))
+	signature, err := evm.NewEthereumSignature(digest.Bytes(), ks, acc)
+	require.NoError(t, err)
+	value, err := types.MarshalValsetConfirm(*types.NewValsetConfirm(common.HexToAddress(evmAddress), hex.EncodeToString(signature)))
+	require.NoError(t, err)
+	return value
+}
+
+// dhtRecord returns the DHT record storing the provided value under the provided key.
+func dhtRecord(t *testing.T, key string, value []byte) []byte {
+	record := recpb.Record{Key: []byte(key), Value: value}
+	encoded, err := record.Marshal()
+	require.NoError(t, err)
+	return encoded
+}
+
+func TestCheckDHTConfirms(t *testing.T) {
+	ctx := context.Background()
+	store := ds.NewMapDatastore()
+	digest := common.HexToHash("1234")
+	confirm := newValsetConfirm(t, digest)
+
+	validKey := p2p.GetValsetConfirmKey(1, evmAddress, digest.Hex())
+	valid := dhtKey("/", validKey)
+	validVersioned := dhtKey("/dht/0.2.0", validKey)
+	wrongDigestKey := p2p.GetValsetConfirmKey(2, evmAddress, common.HexToHash("5678").Hex())
+	wrongDigest := dhtKey("/", wrongDigestKey)
+	notARecord := dhtKey("/", p2p.GetValsetConfirmKey(3, evmAddress, digest.Hex()))
+	invalidKey := dhtKey("/", "/vc/invalid")
+	peerstoreKey := ds.NewKey(p2p.PeerstoreNamespace + "/addrs/abcd")
+	require.NoError(t, store.Put(ctx, valid, dhtRecord(t, validKey, confirm)))
+	require.NoError(t, store.Put(ctx, validVersioned, dhtRecord(t, validKey, confirm)))
+	require.NoError(t, store.Put(ctx, wrongDigest, dhtRecord(t, wrongDigestKey, confirm)))
+	require.NoError(t, store.Put(ctx, notARecord, []byte("not a record")))
+	require.NoError(t, store.Put(ctx, invalidKey, dhtRecord(t, "/vc/invalid", confirm)))
+	require.NoError(t, store.Put(ctx, peerstoreKey, []byte("peer")))
+
+	result, err := p2p.CheckDHTConfirms(ctx, store, false)
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Checked)
+	invalid := make([]string, 0, len(result.Invalid))
+	for _, record := range result.Invalid {
+		invalid = append(invalid, record.Key)
+	}
+	assert.ElementsMatch(t, []string{wrongDigest.String(), notARecord.String(), invalidKey.String()}, invalid)
+	assert.Equal(t, 0, result.Removed)
+
+	result, err = p2p.CheckDHTConfirms(ctx, store, true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Removed)
+	for _, key := range []ds.Key{wrongDigest, notARecord, invalidKey} {
+		has, err := store.Has(ctx, key)
+		require.NoError(t, err)
+		assert.False(t, has, key.String())
+	}
+	for _, key := range []ds.Key{valid, validVersioned, peerstoreKey} {
+		has, err := store.Has(ctx, key)
+		require.NoError(t, err)
+		assert.True(t, has, key.String())
+	}
+}
+
+func TestCheckArchivedConfirms(t *testing.T) {
+	ctx := context.Background()
+	store := ds.NewMapDatastore()
+	digest := common.HexToHash("1234")
+
+	valid := ds.NewKey(p2p.GetValsetConfirmKey(1, evmAddress, digest.Hex()))
+	invalid := ds.NewKey(p2p.GetValsetConfirmKey(2, evmAddress, digest.Hex()))
+	require.NoError(t, store.Put(ctx, valid, newValsetConfirm(t, digest)))
+	require.NoError(t, store.Put(ctx, invalid, []byte("{}")))
+
+	result, err := p2p.CheckArchivedConfirms(ctx, store, true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Checked)
+	require.Len(t, result.Invalid, 1)
+	assert.Equal(t, invalid.String(), result.Invalid[0].Key)
+	assert.Equal(t, 1, result.Removed)
+
+	has, err := store.Has(ctx, valid)
+	require.NoError(t, err)
+	assert.True(t, has)
+}
@@ -0,0 +1,52 @@
+package p2p
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/net/connmgr"
+)
+
+const (
+	// DefaultConnMgrLowWater the default number of connections that the connection manager prunes down to.
+	DefaultConnMgrLowWater = 100
+	// DefaultConnMgrHighWater the default number of connections above which the connection manager starts pruning.
+	DefaultConnMgrHighWater = 400
+	// connMgrGracePeriod the duration during which the new connections are not pruned.
+	connMgrGracePeriod = time.Minute
+)
+
+// The connection manager tags protecting the peers whose connections should never be pruned.
+const (
+	BootstrapperProtectionTag = "qgb-bootstrapper"
+	RelayProtectionTag        = "qgb-relay"
+	AllowlistProtectionTag    = "qgb-allowlist"
+	ValidatorProtectionTag    = "qgb-validator"
+)
+
+// NewConnManager creates a connection manager that prunes the connections down to the low watermark
+// when their number exceeds the high watermark.
+// The bootstrappers, the relays and the validators should be protected using `ProtectPeers`.
+func NewConnManager(lowWater int, highWater int) (*connmgr.BasicConnMgr, error) {
+	if lowWater <= 0 || highWater < lowWater {
+		return nil, ErrInvalidConnMgrWatermarks
+	}
+	return connmgr.NewConnManager(lowWater, highWater, connmgr.WithGracePeriod(connMgrGracePeriod))
+}
+
+// ProtectPeers prevents the host connection manager from pruning the connections to the provided peers.
+func ProtectPeers(h host.Host, tag string, peers ...peer.ID) {
+	for _, id := range peers {
+		h.ConnManager().Protect(id, tag)
+	}
+}
+
+// AddrInfosIDs returns the IDs of the provided peers.
+func AddrInfosIDs(infos []peer.AddrInfo) []peer.ID {
+	ids := make([]peer.ID, 0, len(infos))
+	for _, info := range infos {
+		ids = append(ids, info.ID)
+	}
+	return ids
+}
@@ -0,0 +1,29 @@
+package p2p_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnManager(t *testing.T) {
+	_, err := p2p.NewConnManager(10, 5)
+	assert.ErrorIs(t, err, p2p.ErrInvalidConnMgrWatermarks)
+	_, err = p2p.HostConfig{ConnMgrLowWater: -1}.Options()
+	assert.ErrorIs(t, err, p2p.ErrInvalidConnMgrWatermarks)
+
+	privateKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	opts, err := p2p.HostConfig{ConnMgrLowWater: 1, ConnMgrHighWater: 2}.Options()
+	require.NoError(t, err)
+	h, err := p2p.CreateHost("/ip4/127.0.0.1/tcp/0", privateKey, opts...)
+	require.NoError(t, err)
+	defer h.Close()
+
+	p2p.ProtectPeers(h, p2p.BootstrapperProtectionTag, h.ID())
+	assert.True(t, h.ConnManager().IsProtected(h.ID(), p2p.BootstrapperProtectionTag))
+}
@@ -14,58 +14,201 @@ import (
 )
 
 const (
-	ProtocolPrefix                 = "/qgb/0.1.0" // TODO "/qgb/<version>" ?
+	// ProtocolPrefix the prefix of the protocols shared by all the DHT protocol versions, i.e. the
+	// pubsub topics and the peer authentication. It's the initial DHT protocol version prefix.
+	ProtocolPrefix                 = "/qgb/0.1.0"
 	DataCommitmentConfirmNamespace = "dcc"
 	ValsetConfirmNamespace         = "vc"
 )
 
 // QgbDHT wrapper around the `IpfsDHT` implementation.
 // Used to add helper methods to easily handle the DHT.
+// The embedded DHT speaks the preferred protocol version. The fallback versions, if any, are spoken
+// by separate DHTs sharing the same host.
 type QgbDHT struct {
 	*dht.IpfsDHT
-	logger tmlog.Logger
+	// Version the preferred protocol version.
+	Version ProtocolVersion
+	// ConfirmEncoding the encoding used when putting confirms in the DHT.
+	// Defaults to the legacy Json encoding so that older nodes can still validate the confirms.
+	// The confirms are always read in both encodings.
+	ConfirmEncoding types.ConfirmEncoding
+	fallbacks       []versionedDHT
+	logger          tmlog.Logger
+}
+
+// versionedDHT a DHT speaking a single protocol version.
+type versionedDHT struct {
+	*dht.IpfsDHT
+	version ProtocolVersion
 }
 
 // NewQgbDHT create a new IPFS DHT using a suitable configuration for the QGB.
 // If nil is passed for bootstrappers, the DHT will not try to connect to any existing peer.
-func NewQgbDHT(ctx context.Context, h host.Host, store ds.Batching, bootstrappers []peer.AddrInfo, logger tmlog.Logger) (*QgbDHT, error) {
+// The provided options, if any, are appended to the default ones.
+// The DHT only speaks the DefaultProtocolVersion.
+func NewQgbDHT(ctx context.Context, h host.Host, store ds.Batching, bootstrappers []peer.AddrInfo, logger tmlog.Logger, opts ...dht.Option) (*QgbDHT, error) {
+	return NewVersionedQgbDHT(ctx, h, store, bootstrappers, logger, ProtocolVersions{}, opts...)
+}
+
+// NewVersionedQgbDHT creates a new QGB DHT, similar to `NewQgbDHT`, speaking the provided protocol versions.
+func NewVersionedQgbDHT(
+	ctx context.Context,
+	h host.Host,
+	store ds.Batching,
+	bootstrappers []peer.AddrInfo,
+	logger tmlog.Logger,
+	versions ProtocolVersions,
+	opts ...dht.Option,
+) (*QgbDHT, error) {
 	// this value is set to 23 days, which is the unbonding period.
 	// we want to have the signatures available for this whole period.
 	providers.ProvideValidity = time.Hour * 24 * 23
 
-	router, err := dht.New(
-		ctx,
-		h,
-		dht.Datastore(store),
-		dht.Mode(dht.ModeServer),
-		dht.ProtocolPrefix(ProtocolPrefix),
-		dht.NamespacedValidator(DataCommitmentConfirmNamespace, DataCommitmentConfirmValidator{}),
-		dht.NamespacedValidator(ValsetConfirmNamespace, ValsetConfirmValidator{}),
-		dht.BootstrapPeers(bootstrappers...),
-		dht.DisableProviders(),
-	)
-	if err != nil {
-		return nil, err
+	dhts := make([]versionedDHT, 0)
+	for _, version := range versions.all() {
+		router, err := dht.New(
+			ctx,
+			h,
+			append([]dht.Option{
+				dht.Datastore(version.datastore(store)),
+				dht.Mode(dht.ModeServer),
+				dht.ProtocolPrefix(version.Prefix()),
+				dht.NamespacedValidator(DataCommitmentConfirmNamespace, DataCommitmentConfirmValidator{}),
+				dht.NamespacedValidator(ValsetConfirmNamespace, ValsetConfirmValidator{}),
+				dht.BootstrapPeers(bootstrappers...),
+				dht.DisableProviders(),
+			}, opts...)...,
+		)
+		if err != nil {
+			for _, d := range dhts {
+				_ = d.Close()
+			}
+			return nil, err
+		}
+		dhts = append(dhts, versionedDHT{IpfsDHT: router, version: version})
 	}
 
 	return &QgbDHT{
-		IpfsDHT: router,
-		logger:  logger,
+		IpfsDHT:   dhts[0].IpfsDHT,
+		Version:   dhts[0].version,
+		fallbacks: dhts[1:],
+		logger:    logger,
 	}, nil
 }
 
+// dhts returns the DHTs of all the spoken versions, starting with the preferred one.
+func (q QgbDHT) dhts() []versionedDHT {
+	return append([]versionedDHT{{IpfsDHT: q.IpfsDHT, version: q.Version}}, q.fallbacks...)
+}
+
+// Versions returns the spoken protocol versions, starting with the preferred one.
+func (q QgbDHT) Versions() []ProtocolVersion {
+	versions := make([]ProtocolVersion, 0, len(q.fallbacks)+1)
+	for _, d := range q.dhts() {
+		versions = append(versions, d.version)
+	}
+	return versions
+}
+
+// Close closes the DHTs of all the spoken versions.
+func (q QgbDHT) Close() error {
+	var firstErr error
+	for _, d := range q.dhts() {
+		if err := d.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ListPeers returns the peers in the routing tables of all the spoken versions.
+func (q QgbDHT) ListPeers() []peer.ID {
+	seen := make(map[peer.ID]struct{})
+	peers := make([]peer.ID, 0)
+	for _, d := range q.dhts() {
+		for _, id := range d.RoutingTable().ListPeers() {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				peers = append(peers, id)
+			}
+		}
+	}
+	return peers
+}
+
+// TryAddPeer adds the peer to the routing tables of the spoken versions that it supports.
+// If the peer doesn't advertise any of them, e.g. its protocols are not known yet, it's added to the
+// preferred version routing table.
+func (q QgbDHT) TryAddPeer(id peer.ID) error {
+	added := false
+	for _, d := range q.dhts() {
+		supported, err := q.Host().Peerstore().SupportsProtocols(id, d.version.DHTProtocol())
+		if err != nil {
+			return err
+		}
+		if len(supported) == 0 {
+			continue
+		}
+		_, err = d.RoutingTable().TryAddPeer(id, true, false)
+		if err != nil {
+			return err
+		}
+		added = true
+	}
+	if !added {
+		_, err := q.RoutingTable().TryAddPeer(id, true, false)
+		return err
+	}
+	return nil
+}
+
+// RemovePeer removes the peer from the routing tables of all the spoken versions.
+func (q QgbDHT) RemovePeer(id peer.ID) {
+	for _, d := range q.dhts() {
+		d.RoutingTable().RemovePeer(id)
+	}
+}
+
+// PeerProtocolVersion returns the newest supported protocol version spoken by the peer.
+// Returns false if the peer doesn't speak any, or if its protocols are not known yet.
+func (q QgbDHT) PeerProtocolVersion(id peer.ID) (ProtocolVersion, bool) {
+	for _, version := range SupportedProtocolVersions {
+		supported, err := q.Host().Peerstore().SupportsProtocols(id, version.DHTProtocol())
+		if err == nil && len(supported) != 0 {
+			return version, true
+		}
+	}
+	return ProtocolVersion{}, false
+}
+
+// PeerProtocolVersions returns the number of peers, in the routing tables, by newest protocol version spoken.
+func (q QgbDHT) PeerProtocolVersions() map[string]int {
+	versions := make(map[string]int)
+	for _, id := range q.ListPeers() {
+		version, ok := q.PeerProtocolVersion(id)
+		if !ok {
+			versions["unknown"]++
+			continue
+		}
+		versions[version.Name]++
+	}
+	return versions
+}
+
 // WaitForPeers waits for peers to be connected to the DHT.
 // Returns nil if the context is done or the peers list has more peers than the specified peersThreshold.
 // Returns error if it times out.
+// The peers are counted across all the spoken protocol versions.
 func (q QgbDHT) WaitForPeers(ctx context.Context, timeout time.Duration, rate time.Duration, peersThreshold int) error {
 	if peersThreshold < 1 {
 		return ErrPeersThresholdCannotBeNegative
 	}
 
 	// checking before entering the for loop to avoid waiting for the initial ticker duration.
-	peersLen := len(q.RoutingTable().ListPeers())
+	peersLen := len(q.ListPeers())
 	if peersLen >= peersThreshold {
-		q.logger.Info("found peers", "peers count", peersLen)
+		q.logger.Info("found peers", "peers_count", peersLen, "protocol_versions", q.PeerProtocolVersions())
 		return nil
 	}
 
@@ -78,16 +221,16 @@ func (q QgbDHT) WaitForPeers(ctx context.Context, timeout time.Duration, rate ti
 		case <-t:
 			return ErrPeersTimeout
 		case <-ticker.C:
-			peersLen := len(q.RoutingTable().ListPeers())
+			peersLen := len(q.ListPeers())
 			if peersLen >= peersThreshold {
-				q.logger.Info("found peers", "peers count", peersLen)
+				q.logger.Info("found peers", "peers_count", peersLen, "protocol_versions", q.PeerProtocolVersions())
 				return nil
 			}
 			q.logger.Info(
 				"waiting for routing table to populate",
-				"target number of peers",
+				"target_peers_count",
 				peersThreshold,
-				"current count",
+				"peers_count",
 				peersLen,
 			)
 		}
@@ -96,28 +239,22 @@ func (q QgbDHT) WaitForPeers(ctx context.Context, timeout time.Duration, rate ti
 
 // Note: The Get and Put methods do not run any validations on the data commitment confirms
 // and valset confirms. The checks are supposed to be handled by the validators under `p2p/validators.go`.
-// Same goes for the Marshal and Unmarshal methods (as long as they're using simple Json encoding).
+// Same goes for the Encode and Unmarshal methods.
 
 // PutDataCommitmentConfirm encodes a data commitment confirm then puts its value to the DHT.
 // The key can be generated using the `GetDataCommitmentConfirmKey` method.
 // Returns an error if it fails to do so.
 func (q QgbDHT) PutDataCommitmentConfirm(ctx context.Context, key string, dcc types.DataCommitmentConfirm) error {
-	encodedData, err := types.MarshalDataCommitmentConfirm(dcc)
-	if err != nil {
-		return err
-	}
-	err = q.PutValue(ctx, key, encodedData)
-	if err != nil {
-		return err
-	}
-	return nil
+	return q.putConfirm(ctx, key, func(encoding types.ConfirmEncoding) ([]byte, error) {
+		return EncodeDataCommitmentConfirm(encoding, key, dcc)
+	})
 }
 
 // GetDataCommitmentConfirm looks for a data commitment confirm referenced by its key in the DHT.
 // The key can be generated using the `GetDataCommitmentConfirmKey` method.
 // Returns an error if it fails to get the confirm.
 func (q QgbDHT) GetDataCommitmentConfirm(ctx context.Context, key string) (types.DataCommitmentConfirm, error) {
-	encodedConfirm, err := q.GetValue(ctx, key) // this is a blocking call, we should probably use timeout and channel
+	encodedConfirm, err := q.getConfirm(ctx, key) // this is a blocking call, the context should carry a deadline
 	if err != nil {
 		return types.DataCommitmentConfirm{}, err
 	}
@@ -132,22 +269,16 @@ func (q QgbDHT) GetDataCommitmentConfirm(ctx context.Context, key string) (types
 // The key can be generated using the `GetValsetConfirmKey` method.
 // Returns an error if it fails to do so.
 func (q QgbDHT) PutValsetConfirm(ctx context.Context, key string, vc types.ValsetConfirm) error {
-	encodedData, err := types.MarshalValsetConfirm(vc)
-	if err != nil {
-		return err
-	}
-	err = q.PutValue(ctx, key, encodedData)
-	if err != nil {
-		return err
-	}
-	return nil
+	return q.putConfirm(ctx, key, func(encoding types.ConfirmEncoding) ([]byte, error) {
+		return EncodeValsetConfirm(encoding, key, vc)
+	})
 }
 
 // GetValsetConfirm looks for a valset confirm referenced by its key in the DHT.
 // The key can be generated using the `GetValsetConfirmKey` method.
 // Returns an error if it fails to get the confirm.
 func (q QgbDHT) GetValsetConfirm(ctx context.Context, key string) (types.ValsetConfirm, error) {
-	encodedConfirm, err := q.GetValue(ctx, key) // this is a blocking call, we should probably use timeout and channel
+	encodedConfirm, err := q.getConfirm(ctx, key) // this is a blocking call, the context should carry a deadline
 	if err != nil {
 		return types.ValsetConfirm{}, err
 	}
@@ -157,3 +288,49 @@ func (q QgbDHT) GetValsetConfirm(ctx context.Context, key string) (types.ValsetC
 	}
 	return confirm, nil
 }
+
+// putConfirm puts the confirm using all the spoken versions. The confirm is translated, if needed, to an
+// encoding that the peers speaking each version can decode.
+// Returns an error only if the confirm couldn't be put using any version.
+func (q QgbDHT) putConfirm(ctx context.Context, key string, encode func(types.ConfirmEncoding) ([]byte, error)) error {
+	var preferredErr error
+	put := false
+	for i, d := range q.dhts() {
+		encodedData, err := encode(d.version.confirmEncoding(q.ConfirmEncoding))
+		if err != nil {
+			return err
+		}
+		err = d.PutValue(ctx, key, encodedData)
+		if err != nil {
+			if i == 0 {
+				preferredErr = err
+			}
+			q.logger.Debug("couldn't put confirm", "key", key, "protocol_version", d.version.Name, "err", err.Error())
+			continue
+		}
+		put = true
+	}
+	if !put {
+		return preferredErr
+	}
+	return nil
+}
+
+// getConfirm looks for the confirm using the preferred version, then using the fallback ones.
+// Returns the preferred version error if the confirm is not found using any version.
+func (q QgbDHT) getConfirm(ctx context.Context, key string) ([]byte, error) {
+	var preferredErr error
+	for i, d := range q.dhts() {
+		encodedConfirm, err := d.GetValue(ctx, key)
+		if err == nil {
+			return encodedConfirm, nil
+		}
+		if i == 0 {
+			preferredErr = err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, preferredErr
+}
@@ -17,4 +17,12 @@ var (
 	ErrEmptyNamespace                  = errors.New("empty namespace")
 	ErrEmptyEVMAddr                    = errors.New("empty evm address")
 	ErrEmptyDigest                     = errors.New("empty digest")
+	ErrNotAValidator                   = errors.New("evm address not part of the validator set")
+	ErrPeerNotAllowed                  = errors.New("peer not allowed to put values into the dht")
+	ErrConfirmRecordKeyMismatch        = errors.New("confirm record not matching its key nonce or digest")
+	ErrUnexpectedDigest                = errors.New("confirm digest not matching the attestation")
+	ErrInvalidSwarmKey                 = errors.New("invalid swarm key")
+	ErrRelayClientRequired             = errors.New("hole punching and auto relay require the relay client")
+	ErrUnsupportedProtocolVersion      = errors.New("unsupported protocol version")
+	ErrInvalidConnMgrWatermarks        = errors.New("the connection manager low watermark should be positive and not exceed the high watermark")
 )
@@ -0,0 +1,410 @@
+package p2p
+
+import (
+	"context"
+	"encoding/binary"
+	"strings"
+	"sync"
+	"time"
+
+	celestiatypes "github.com/celestiaorg/celestia-app/x/qgb/types"
+	dhtpb "github.com/libp2p/go-libp2p-kad-dht/pb"
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/control"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/libp2p/go-msgio"
+	"github.com/multiformats/go-multiaddr"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	// DefaultValsetRefreshInterval the default interval at which the gater refreshes the
+	// validator set used to authenticate the peers.
+	DefaultValsetRefreshInterval = time.Minute
+	// deniedPeerTTL the duration for which a peer that failed authentication is denied.
+	deniedPeerTTL = 10 * time.Minute
+	// authRequestAttempts the number of times the peer authentication is requested before disconnecting it,
+	// as the request fails when the connection it uses is replaced, e.g. after a simultaneous dial.
+	authRequestAttempts   = 3
+	authRequestRetryDelay = 100 * time.Millisecond
+)
+
+// ValsetQuerier queries the latest Celestia validator set.
+// Implemented by `rpc.AppQuerier`.
+type ValsetQuerier interface {
+	QueryLatestValset(ctx context.Context) (*celestiatypes.Valset, error)
+}
+
+var _ connmgr.ConnectionGater = &ConnectionGater{}
+
+// ConnectionGater restricts the DHT server participation, i.e. being added to the routing table and
+// putting values, to the peers that proved control of an EVM address registered in the current Celestia
+// validator set, the bootstrappers and the explicitly allowlisted peers, e.g. relayers.
+// The other peers can still query the DHT as clients, but their put requests to the protocols registered
+// using the `GateStreams` host are rejected. Peers that provide an invalid authentication are disconnected
+// and denied for some time.
+type ConnectionGater struct {
+	valsetQuerier ValsetQuerier
+	logger        tmlog.Logger
+
+	mutex *sync.RWMutex
+	// validators the EVM addresses, in lower case, of the current validator set.
+	validators map[string]struct{}
+	// allowed the bootstrappers and allowlisted peers.
+	allowed map[peer.ID]struct{}
+	// authenticated the authenticated peers along with their EVM addresses, in lower case.
+	authenticated map[peer.ID]string
+	// denied the peers that failed authentication along with the denial time.
+	denied map[peer.ID]time.Time
+	// pending the peers being authenticated, along with a channel closed once their authentication ends.
+	pending map[peer.ID]chan struct{}
+}
+
+// NewConnectionGater creates a new ConnectionGater that allows the provided peers to participate in the DHT
+// in addition to the authenticated validators.
+// The gater should be started using `Start` to refresh the validator set and authenticate the peers.
+func NewConnectionGater(valsetQuerier ValsetQuerier, allowlist []peer.ID, logger tmlog.Logger) *ConnectionGater {
+	allowed := make(map[peer.ID]struct{})
+	for _, id := range allowlist {
+		allowed[id] = struct{}{}
+	}
+	return &ConnectionGater{
+		valsetQuerier: valsetQuerier,
+		logger:        logger,
+		mutex:         &sync.RWMutex{},
+		validators:    make(map[string]struct{}),
+		allowed:       allowed,
+		authenticated: make(map[peer.ID]string),
+		denied:        make(map[peer.ID]time.Time),
+		pending:       make(map[peer.ID]chan struct{}),
+	}
+}
+
+// Allow adds the provided peers to the allowlist.
+func (g *ConnectionGater) Allow(ids ...peer.ID) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	for _, id := range ids {
+		g.allowed[id] = struct{}{}
+	}
+}
+
+// IsAllowed returns true if the peer is allowed to participate in the DHT as a server.
+func (g *ConnectionGater) IsAllowed(id peer.ID) bool {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+	if _, ok := g.allowed[id]; ok {
+		return true
+	}
+	_, ok := g.authenticated[id]
+	return ok
+}
+
+// RoutingTableFilter is used as the DHT routing table filter to only add the allowed peers.
+func (g *ConnectionGater) RoutingTableFilter(_ interface{}, id peer.ID) bool {
+	return g.IsAllowed(id)
+}
+
+func (g *ConnectionGater) isDenied(id peer.ID) bool {
+	g.mutex.RLock()
+	deniedAt, ok := g.denied[id]
+	g.mutex.RUnlock()
+	if !ok {
+		return false
+	}
+	if time.Since(deniedAt) > deniedPeerTTL {
+		g.mutex.Lock()
+		delete(g.denied, id)
+		g.mutex.Unlock()
+		return false
+	}
+	return true
+}
+
+// GateStreams returns a host wrapping the provided one, whose DHT stream handlers reject the put requests
+// of the non allowed peers by resetting their streams. The put requests of a peer being authenticated wait
+// for its authentication to end. The other requests, e.g. getting values, are accepted from any peer.
+// The DHT should be created using the returned host so that the non allowed peers can't put values into it.
+func (g *ConnectionGater) GateStreams(h host.Host) host.Host {
+	return &gatedHost{Host: h, gater: g}
+}
+
+// gatedHost a host gating the DHT requests of the peers not allowed by the gater.
+type gatedHost struct {
+	host.Host
+	gater *ConnectionGater
+}
+
+func (h *gatedHost) SetStreamHandler(pid protocol.ID, handler network.StreamHandler) {
+	h.Host.SetStreamHandler(pid, h.gater.gateStreamHandler(handler))
+}
+
+func (h *gatedHost) SetStreamHandlerMatch(pid protocol.ID, match func(protocol.ID) bool, handler network.StreamHandler) {
+	h.Host.SetStreamHandlerMatch(pid, match, h.gater.gateStreamHandler(handler))
+}
+
+func (g *ConnectionGater) gateStreamHandler(handler network.StreamHandler) network.StreamHandler {
+	return func(s network.Stream) {
+		handler(&gatedStream{
+			Stream: s,
+			gater:  g,
+			reader: msgio.NewVarintReaderSize(s, network.MessageSizeMax),
+		})
+	}
+}
+
+// gatedStream a DHT stream whose put requests are rejected if the remote peer is not allowed.
+// The DHT messages are read one at a time to check their type, then passed through unchanged.
+type gatedStream struct {
+	network.Stream
+	gater  *ConnectionGater
+	reader msgio.Reader
+	// pending the bytes of the current message not read yet, including its length prefix.
+	pending []byte
+}
+
+func (s *gatedStream) Read(p []byte) (int, error) {
+	if len(s.pending) == 0 {
+		msg, err := s.reader.ReadMsg()
+		if err != nil {
+			return 0, err
+		}
+		var req dhtpb.Message
+		// the malformed messages are passed through to be rejected by the DHT
+		if req.Unmarshal(msg) == nil && isDHTPut(req.GetType()) {
+			id := s.Conn().RemotePeer()
+			if !s.gater.waitForAuthentication(id) {
+				s.reader.ReleaseMsg(msg)
+				s.gater.logger.Debug("rejecting dht put from a non allowed peer", "peer", id.String(), "type", req.GetType().String())
+				_ = s.Stream.Reset()
+				return 0, ErrPeerNotAllowed
+			}
+		}
+		s.pending = binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(msg)), uint64(len(msg)))
+		s.pending = append(s.pending, msg...)
+		s.reader.ReleaseMsg(msg)
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// isDHTPut returns true if the DHT message type stores data in the receiving peer.
+func isDHTPut(t dhtpb.Message_MessageType) bool {
+	return t == dhtpb.Message_PUT_VALUE || t == dhtpb.Message_ADD_PROVIDER
+}
+
+// waitForAuthentication waits for the peer authentication to end, if it is pending, then returns
+// true if the peer is allowed.
+func (g *ConnectionGater) waitForAuthentication(id peer.ID) bool {
+	if g.IsAllowed(id) {
+		return true
+	}
+	g.mutex.RLock()
+	done, ok := g.pending[id]
+	g.mutex.RUnlock()
+	if !ok {
+		return false
+	}
+	select {
+	case <-done:
+	case <-time.After(authStreamTimeout):
+	}
+	return g.IsAllowed(id)
+}
+
+// InterceptPeerDial tests whether we're permitted to Dial the specified peer.
+func (g *ConnectionGater) InterceptPeerDial(id peer.ID) bool {
+	return !g.isDenied(id)
+}
+
+// InterceptAddrDial tests whether we're permitted to dial the specified multiaddr for the given peer.
+func (g *ConnectionGater) InterceptAddrDial(_ peer.ID, _ multiaddr.Multiaddr) bool {
+	return true
+}
+
+// InterceptAccept tests whether an incipient inbound connection is allowed.
+func (g *ConnectionGater) InterceptAccept(_ network.ConnMultiaddrs) bool {
+	return true
+}
+
+// InterceptSecured tests whether a given connection, now authenticated, is allowed.
+func (g *ConnectionGater) InterceptSecured(_ network.Direction, id peer.ID, _ network.ConnMultiaddrs) bool {
+	return !g.isDenied(id)
+}
+
+// InterceptUpgraded tests whether a fully capable connection is allowed.
+func (g *ConnectionGater) InterceptUpgraded(_ network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
+
+// Start refreshes the validator set and authenticates the connected peers until the context is done.
+// The authenticated peers are added to the DHT routing table. Their connections, along with the
+// allowlisted peers ones, are protected from the connection manager pruning.
+// This is a non-blocking call.
+func (g *ConnectionGater) Start(ctx context.Context, qgbDHT *QgbDHT, refreshInterval time.Duration) error {
+	err := g.refreshValset(ctx)
+	if err != nil {
+		return err
+	}
+
+	h := qgbDHT.Host()
+	g.mutex.RLock()
+	for id := range g.allowed {
+		h.ConnManager().Protect(id, AllowlistProtectionTag)
+	}
+	g.mutex.RUnlock()
+	h.Network().Notify(&network.NotifyBundle{
+		ConnectedF: func(_ network.Network, conn network.Conn) {
+			g.startAuthentication(ctx, qgbDHT, conn.RemotePeer())
+		},
+	})
+	// authenticate the peers that connected before the gater was started
+	for _, id := range h.Network().Peers() {
+		g.startAuthentication(ctx, qgbDHT, id)
+	}
+
+	go func() {
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := g.refreshValset(ctx)
+				if err != nil {
+					g.logger.Error("couldn't refresh the gater validator set", "err", err.Error())
+					continue
+				}
+				g.evictNonValidators(qgbDHT)
+			}
+		}
+	}()
+	return nil
+}
+
+// refreshValset updates the validator set used to authenticate the peers.
+func (g *ConnectionGater) refreshValset(ctx context.Context) error {
+	vs, err := g.valsetQuerier.QueryLatestValset(ctx)
+	if err != nil {
+		return err
+	}
+	validators := make(map[string]struct{}, len(vs.Members))
+	for _, member := range vs.Members {
+		validators[strings.ToLower(member.EvmAddress)] = struct{}{}
+	}
+	g.mutex.Lock()
+	g.validators = validators
+	g.mutex.Unlock()
+	g.logger.Debug("refreshed gater validator set", "nonce", vs.Nonce, "validators", len(validators))
+	return nil
+}
+
+// evictNonValidators removes and disconnects the authenticated peers whose EVM addresses are no longer
+// part of the validator set.
+func (g *ConnectionGater) evictNonValidators(qgbDHT *QgbDHT) {
+	g.mutex.Lock()
+	evicted := make([]peer.ID, 0)
+	for id, evmAddr := range g.authenticated {
+		if _, ok := g.validators[evmAddr]; !ok {
+			delete(g.authenticated, id)
+			if _, allowed := g.allowed[id]; !allowed {
+				evicted = append(evicted, id)
+			}
+		}
+	}
+	g.mutex.Unlock()
+	for _, id := range evicted {
+		g.logger.Info("peer no longer part of the validator set", "peer", id.String())
+		qgbDHT.Host().ConnManager().Unprotect(id, ValidatorProtectionTag)
+		qgbDHT.RemovePeer(id)
+		_ = qgbDHT.Host().Network().ClosePeer(id)
+	}
+}
+
+// startAuthentication authenticates the peer in the background, unless it is already allowed
+// or being authenticated.
+func (g *ConnectionGater) startAuthentication(ctx context.Context, qgbDHT *QgbDHT, id peer.ID) {
+	if g.IsAllowed(id) {
+		return
+	}
+	g.mutex.Lock()
+	if _, ok := g.pending[id]; ok {
+		g.mutex.Unlock()
+		return
+	}
+	done := make(chan struct{})
+	g.pending[id] = done
+	g.mutex.Unlock()
+
+	go func() {
+		defer func() {
+			g.mutex.Lock()
+			delete(g.pending, id)
+			g.mutex.Unlock()
+			close(done)
+		}()
+		g.authenticate(ctx, qgbDHT, id)
+	}()
+}
+
+// authenticate requests the peer authentication and verifies it against the current validator set.
+// Peers not supporting the authentication protocol, e.g. the DHT clients, stay connected without being
+// added to the routing table.
+// Peers providing an invalid authentication are disconnected and denied.
+func (g *ConnectionGater) authenticate(ctx context.Context, qgbDHT *QgbDHT, id peer.ID) {
+	h := qgbDHT.Host()
+	var auth PeerAuthentication
+	var err error
+	for attempt := 1; attempt <= authRequestAttempts; attempt++ {
+		auth, err = RequestPeerAuthentication(ctx, h, id)
+		if err == nil || h.Network().Connectedness(id) != network.Connected {
+			break
+		}
+		if attempt < authRequestAttempts {
+			time.Sleep(authRequestRetryDelay)
+		}
+	}
+	if err != nil {
+		g.logger.Debug("peer not authenticated", "peer", id.String(), "err", err.Error())
+		qgbDHT.RemovePeer(id)
+		return
+	}
+
+	err = VerifyPeerAuthentication(id, auth)
+	if err == nil {
+		g.mutex.RLock()
+		_, isValidator := g.validators[strings.ToLower(auth.EVMAddress)]
+		g.mutex.RUnlock()
+		if !isValidator {
+			err = ErrNotAValidator
+		}
+	}
+	if err != nil {
+		g.logger.Info("denying peer with invalid authentication", "peer", id.String(), "evm_address", auth.EVMAddress, "err", err.Error())
+		g.mutex.Lock()
+		g.denied[id] = time.Now()
+		g.mutex.Unlock()
+		qgbDHT.RemovePeer(id)
+		_ = h.Network().ClosePeer(id)
+		return
+	}
+
+	g.mutex.Lock()
+	g.authenticated[id] = strings.ToLower(auth.EVMAddress)
+	g.mutex.Unlock()
+	version, _ := qgbDHT.PeerProtocolVersion(id)
+	g.logger.Info("authenticated peer", "peer", id.String(), "evm_address", auth.EVMAddress, "protocol_version", version.Name)
+	// the validators connections are never pruned by the connection manager
+	h.ConnManager().Protect(id, ValidatorProtectionTag)
+
+	// the peer was filtered when it was first found, so adding it now to the routing tables
+	err = qgbDHT.TryAddPeer(id)
+	if err != nil {
+		g.logger.Debug("couldn't add authenticated peer to the routing table", "peer", id.String(), "err", err.Error())
+	}
+}
@@ -0,0 +1,198 @@
+package p2p_test
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	celestiatypes "github.com/celestiaorg/celestia-app/x/qgb/types"
+	"github.com/celestiaorg/orchestrator-relayer/evm"
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p"
+	dht "github.com/libp2p/go-libp2p-kad-dht"
+	recpb "github.com/libp2p/go-libp2p-record/pb"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+type mockValsetQuerier struct {
+	valset *celestiatypes.Valset
+}
+
+func (m mockValsetQuerier) QueryLatestValset(_ context.Context) (*celestiatypes.Valset, error) {
+	return m.valset, nil
+}
+
+func TestConnectionGater(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	querier := mockValsetQuerier{valset: &celestiatypes.Valset{
+		Nonce:   1,
+		Members: []celestiatypes.BridgeValidator{{Power: 100, EvmAddress: evmAddress}},
+	}}
+
+	// the gated DHT
+	allowlisted, err := libp2p.New()
+	require.NoError(t, err)
+	defer allowlisted.Close()
+	gater := p2p.NewConnectionGater(querier, []peer.ID{allowlisted.ID()}, tmlog.NewNopLogger())
+	h, err := libp2p.New(libp2p.ConnectionGater(gater))
+	require.NoError(t, err)
+	gatedDHT, err := p2p.NewQgbDHT(ctx, h, dssync.MutexWrap(ds.NewMapDatastore()), nil, tmlog.NewNopLogger(), dht.RoutingTableFilter(gater.RoutingTableFilter))
+	require.NoError(t, err)
+	defer gatedDHT.Close()
+	require.NoError(t, gater.Start(ctx, gatedDHT, time.Minute))
+
+	// a validator proving control of its EVM address
+	validator := newAuthenticatedHost(t, "da6ed55cb2894ac2c9c10209c09de8e8b9d109b910338d5bf3d747a7e1fc9eb9")
+	defer validator.Close()
+	// a peer proving control of an EVM address that is not part of the validator set
+	nonValidator := newAuthenticatedHost(t, "a1ed55cb2894ac2c9c10209c09de8e8b9d109b910338d5bf3d747a7e1fc9eb90")
+	defer nonValidator.Close()
+	// a peer not authenticating
+	client, err := libp2p.New()
+	require.NoError(t, err)
+	defer client.Close()
+
+	for _, p := range []host.Host{validator, nonValidator, client, allowlisted} {
+		require.NoError(t, p.Connect(ctx, peer.AddrInfo{ID: h.ID(), Addrs: h.Addrs()}))
+	}
+
+	assert.Eventually(t, func() bool {
+		return gater.IsAllowed(validator.ID())
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.True(t, gater.IsAllowed(allowlisted.ID()))
+	assert.False(t, gater.IsAllowed(client.ID()))
+	// the authenticated validator is added to the routing table
+	assert.Eventually(t, func() bool {
+		return gatedDHT.RoutingTable().Find(validator.ID()) != ""
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Empty(t, gatedDHT.RoutingTable().Find(client.ID()))
+
+	// the peer with an invalid authentication is disconnected and denied
+	assert.Eventually(t, func() bool {
+		return h.Network().Connectedness(nonValidator.ID()) != network.Connected && !gater.InterceptPeerDial(nonValidator.ID())
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.False(t, gater.IsAllowed(nonValidator.ID()))
+
+	// the client not authenticating stays connected without being added to the routing table
+	assert.Never(t, func() bool {
+		return h.Network().Connectedness(client.ID()) != network.Connected
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Empty(t, gatedDHT.RoutingTable().Find(client.ID()))
+	// the allowlisted peer is still connected
+	assert.Equal(t, network.Connected, h.Network().Connectedness(allowlisted.ID()))
+}
+
+func TestConnectionGaterStreams(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	querier := mockValsetQuerier{valset: &celestiatypes.Valset{
+		Nonce:   1,
+		Members: []celestiatypes.BridgeValidator{{Power: 100, EvmAddress: evmAddress}},
+	}}
+	gater := p2p.NewConnectionGater(querier, nil, tmlog.NewNopLogger())
+	h, err := libp2p.New(libp2p.ConnectionGater(gater))
+	require.NoError(t, err)
+	store := dssync.MutexWrap(ds.NewMapDatastore())
+	gatedDHT, err := p2p.NewQgbDHT(ctx, gater.GateStreams(h), store, nil, tmlog.NewNopLogger(), dht.RoutingTableFilter(gater.RoutingTableFilter))
+	require.NoError(t, err)
+	defer gatedDHT.Close()
+	require.NoError(t, gater.Start(ctx, gatedDHT, time.Minute))
+	gatedInfo := peer.AddrInfo{ID: h.ID(), Addrs: h.Addrs()}
+	isStored := func(key string) bool {
+		has, err := store.Has(ctx, dhtKey("/", key))
+		require.NoError(t, err)
+		return has
+	}
+
+	// the validator puts are accepted once it's authenticated
+	validator := newAuthenticatedHost(t, "da6ed55cb2894ac2c9c10209c09de8e8b9d109b910338d5bf3d747a7e1fc9eb9")
+	defer validator.Close()
+	validatorDHT, err := p2p.NewQgbDHT(ctx, validator, dssync.MutexWrap(ds.NewMapDatastore()), []peer.AddrInfo{gatedInfo}, tmlog.NewNopLogger())
+	require.NoError(t, err)
+	defer validatorDHT.Close()
+	require.Eventually(t, func() bool {
+		return gatedDHT.RoutingTable().Find(validator.ID()) != ""
+	}, 10*time.Second, 10*time.Millisecond)
+	validatorKey, validatorConfirm := newDataCommitmentConfirm(t, 10)
+	require.NoError(t, validatorDHT.PutDataCommitmentConfirm(ctx, validatorKey, validatorConfirm))
+	assert.Eventually(t, func() bool {
+		return isStored(validatorKey)
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// the client gets the values stored in the gated DHT
+	storedKey, storedConfirm := newDataCommitmentConfirm(t, 11)
+	value, err := types.MarshalDataCommitmentConfirm(storedConfirm)
+	require.NoError(t, err)
+	// the DHT only serves the records having a reception time
+	record, err := (&recpb.Record{Key: []byte(storedKey), Value: value, TimeReceived: time.Now().UTC().Format(time.RFC3339Nano)}).Marshal()
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, dhtKey("/", storedKey), record))
+	client, err := libp2p.New()
+	require.NoError(t, err)
+	defer client.Close()
+	clientDHT, err := p2p.NewQgbDHT(ctx, client, dssync.MutexWrap(ds.NewMapDatastore()), []peer.AddrInfo{gatedInfo}, tmlog.NewNopLogger(), dht.Mode(dht.ModeClient))
+	require.NoError(t, err)
+	defer clientDHT.Close()
+	require.Eventually(t, func() bool {
+		return clientDHT.RoutingTable().Find(h.ID()) != ""
+	}, 10*time.Second, 10*time.Millisecond)
+	getCtx, getCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer getCancel()
+	confirm, err := clientDHT.GetDataCommitmentConfirm(getCtx, storedKey)
+	require.NoError(t, err)
+	assert.Equal(t, storedConfirm, confirm)
+
+	// the client puts are rejected without disconnecting it
+	clientKey, clientConfirm := newDataCommitmentConfirm(t, 12)
+	_ = clientDHT.PutDataCommitmentConfirm(ctx, clientKey, clientConfirm)
+	assert.False(t, isStored(clientKey))
+	assert.Equal(t, network.Connected, h.Network().Connectedness(client.ID()))
+}
+
+// newDataCommitmentConfirm returns a data commitment confirm signed by the test private key,
+// along with its DHT key.
+func newDataCommitmentConfirm(t *testing.T, nonce uint64) (string, types.DataCommitmentConfirm) {
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	acc, err := ks.ImportECDSA(privateKey, "123")
+	require.NoError(t, err)
+	require.NoError(t, ks.Unlock(acc, "123"))
+	dataRootHash := types.DataCommitmentTupleRootSignBytes(big.NewInt(int64(nonce)), []byte{0x12, 0x34})
+	signature, err := evm.NewEthereumSignature(dataRootHash.Bytes(), ks, acc)
+	require.NoError(t, err)
+	confirm := types.DataCommitmentConfirm{
+		EthAddress: evmAddress,
+		Signature:  hex.EncodeToString(signature),
+	}
+	return p2p.GetDataCommitmentConfirmKey(nonce, evmAddress, dataRootHash.Hex()), confirm
+}
+
+func newAuthenticatedHost(t *testing.T, hexKey string) host.Host {
+	key, err := ethcrypto.HexToECDSA(hexKey)
+	require.NoError(t, err)
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	acc, err := ks.ImportECDSA(key, "123")
+	require.NoError(t, err)
+	require.NoError(t, ks.Unlock(acc, "123"))
+
+	h, err := libp2p.New()
+	require.NoError(t, err)
+	auth, err := p2p.NewPeerAuthentication(ks, acc, h.ID())
+	require.NoError(t, err)
+	require.NoError(t, p2p.SetAuthenticationHandler(h, *auth))
+	return h
+}
@@ -4,6 +4,11 @@ import (
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/core/pnet"
+	rcmgr "github.com/libp2p/go-libp2p/p2p/host/resource-manager"
 	"github.com/multiformats/go-multiaddr"
 )
 
@@ -11,7 +16,8 @@ import (
 // The listen address is a MultiAddress of the format: /ip4/0.0.0.0/tcp/0
 // Using port 0 means that it will use a random open port.
 // The private key shouldn't be nil.
-func CreateHost(listenMultiAddr string, privateKey crypto.PrivKey) (host.Host, error) {
+// The provided options, if any, are appended to the default ones.
+func CreateHost(listenMultiAddr string, privateKey crypto.PrivKey, opts ...libp2p.Option) (host.Host, error) {
 	multiAddr, err := multiaddr.NewMultiaddr(listenMultiAddr)
 	if err != nil {
 		return nil, err
@@ -22,10 +28,11 @@ func CreateHost(listenMultiAddr string, privateKey crypto.PrivKey) (host.Host, e
 	}
 
 	h, err := libp2p.New(
-		libp2p.ListenAddrs(multiAddr),
-		libp2p.Identity(privateKey),
-		libp2p.EnableNATService(),
-		// TODO investigate if more options are needed
+		append([]libp2p.Option{
+			libp2p.ListenAddrs(multiAddr),
+			libp2p.Identity(privateKey),
+			libp2p.EnableNATService(),
+		}, opts...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -33,3 +40,137 @@ func CreateHost(listenMultiAddr string, privateKey crypto.PrivKey) (host.Host, e
 
 	return h, nil
 }
+
+// HostConfig the configuration of the optional P2P host features.
+// The zero value keeps the libp2p defaults, i.e. the relay client enabled, the resource
+// manager limits scaled to the system resources and an in-memory peerstore. The connection
+// manager uses the default watermarks.
+type HostConfig struct {
+	// SwarmKey if set, the host only connects to the peers in the private network defined by
+	// this pre-shared key.
+	SwarmKey pnet.PSK
+	// DisableRelayClient disables connecting to other peers through circuit relays.
+	DisableRelayClient bool
+	// EnableRelayService makes the host act as a circuit relay v2 for the other peers.
+	// Should only be enabled on publicly reachable nodes.
+	EnableRelayService bool
+	// EnableHolePunching enables the direct connection upgrade through relays (DCUtR).
+	// Requires the relay client.
+	EnableHolePunching bool
+	// StaticRelays if set, enables AutoRelay using these relays when the host is not publicly reachable.
+	// Requires the relay client.
+	StaticRelays []peer.AddrInfo
+	// AnnounceAddrs if set, replaces the addresses announced to the other peers.
+	AnnounceAddrs []multiaddr.Multiaddr
+	// NoAnnounceAddrs the addresses that are never announced to the other peers.
+	NoAnnounceAddrs []multiaddr.Multiaddr
+	// MaxMemory the memory, in bytes, that the resource manager allows the host to use.
+	// If 0, it is scaled to the system memory.
+	MaxMemory int64
+	// MaxFileDescriptors the number of file descriptors that the resource manager allows the host to use.
+	// If 0, it is scaled to the system limit.
+	MaxFileDescriptors int
+	// ConnMgrLowWater and ConnMgrHighWater the connection manager watermarks.
+	// If 0, DefaultConnMgrLowWater and DefaultConnMgrHighWater are used respectively.
+	ConnMgrLowWater  int
+	ConnMgrHighWater int
+	// Peerstore if set, replaces the in-memory peerstore, e.g. with a persistent one created
+	// using `NewPeerstore`.
+	Peerstore peerstore.Peerstore
+	// EnableMDNS if set, the local network peers should be discovered using `StartMDNSDiscovery`
+	// once the host is created. It's not part of the libp2p options.
+	EnableMDNS bool
+}
+
+// Options returns the libp2p options corresponding to the host configuration.
+// They can be passed to `CreateHost`.
+func (cfg HostConfig) Options() ([]libp2p.Option, error) {
+	if cfg.DisableRelayClient && (cfg.EnableHolePunching || len(cfg.StaticRelays) != 0) {
+		return nil, ErrRelayClientRequired
+	}
+
+	opts := make([]libp2p.Option, 0)
+	if cfg.SwarmKey != nil {
+		opts = append(opts, PrivateNetworkOptions(cfg.SwarmKey)...)
+	}
+	if cfg.DisableRelayClient {
+		opts = append(opts, libp2p.DisableRelay())
+	}
+	if cfg.EnableRelayService {
+		opts = append(opts, libp2p.EnableRelayService())
+	}
+	if cfg.EnableHolePunching {
+		opts = append(opts, libp2p.EnableHolePunching())
+	}
+	if len(cfg.StaticRelays) != 0 {
+		opts = append(opts, libp2p.EnableAutoRelayWithStaticRelays(cfg.StaticRelays))
+	}
+	if len(cfg.AnnounceAddrs) != 0 || len(cfg.NoAnnounceAddrs) != 0 {
+		opts = append(opts, libp2p.AddrsFactory(announcedAddrsFactory(cfg.AnnounceAddrs, cfg.NoAnnounceAddrs)))
+	}
+
+	if cfg.Peerstore != nil {
+		opts = append(opts, libp2p.Peerstore(cfg.Peerstore))
+	}
+
+	lowWater, highWater := cfg.ConnMgrLowWater, cfg.ConnMgrHighWater
+	if lowWater == 0 {
+		lowWater = DefaultConnMgrLowWater
+	}
+	if highWater == 0 {
+		highWater = DefaultConnMgrHighWater
+	}
+	cm, err := NewConnManager(lowWater, highWater)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, libp2p.ConnectionManager(cm))
+
+	rm, err := NewResourceManager(cfg.MaxMemory, cfg.MaxFileDescriptors)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, libp2p.ResourceManager(rm))
+	return opts, nil
+}
+
+// announcedAddrsFactory returns the addresses factory that replaces the announced addresses, if any, then
+// filters out the ones that should not be announced.
+func announcedAddrsFactory(announce []multiaddr.Multiaddr, noAnnounce []multiaddr.Multiaddr) func([]multiaddr.Multiaddr) []multiaddr.Multiaddr {
+	return func(addrs []multiaddr.Multiaddr) []multiaddr.Multiaddr {
+		if len(announce) != 0 {
+			addrs = announce
+		}
+		filtered := make([]multiaddr.Multiaddr, 0, len(addrs))
+		for _, addr := range addrs {
+			if !containsAddr(noAnnounce, addr) {
+				filtered = append(filtered, addr)
+			}
+		}
+		return filtered
+	}
+}
+
+func containsAddr(addrs []multiaddr.Multiaddr, addr multiaddr.Multiaddr) bool {
+	for _, a := range addrs {
+		if a.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewResourceManager creates a libp2p resource manager using the default limits, including the ones
+// of the libp2p services, scaled to the provided memory, in bytes, and number of file descriptors.
+// If either is 0, the limits are scaled to the system resources instead.
+func NewResourceManager(maxMemory int64, maxFileDescriptors int) (network.ResourceManager, error) {
+	limits := rcmgr.DefaultLimits
+	libp2p.SetDefaultServiceLimits(&limits)
+	var scaled rcmgr.ConcreteLimitConfig
+	if maxMemory == 0 || maxFileDescriptors == 0 {
+		scaled = limits.AutoScale()
+	} else {
+		scaled = limits.Scale(maxMemory, maxFileDescriptors)
+	}
+	return rcmgr.NewResourceManager(rcmgr.NewFixedLimiter(scaled))
+}
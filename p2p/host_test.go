@@ -2,12 +2,14 @@ package p2p_test
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/hex"
 	"testing"
 
 	"github.com/celestiaorg/orchestrator-relayer/p2p"
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -88,3 +90,30 @@ func TestConnectHosts(t *testing.T) {
 	assert.NoError(t, host1.Close())
 	assert.NoError(t, host2.Close())
 }
+
+func TestHostConfig(t *testing.T) {
+	privateKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	announceAddr := multiaddr.StringCast("/ip4/1.2.3.4/tcp/30000")
+	noAnnounceAddr := multiaddr.StringCast("/ip4/5.6.7.8/tcp/30000")
+
+	// the relay client is needed for hole punching
+	_, err = p2p.HostConfig{DisableRelayClient: true, EnableHolePunching: true}.Options()
+	assert.ErrorIs(t, err, p2p.ErrRelayClientRequired)
+
+	opts, err := p2p.HostConfig{
+		EnableRelayService: true,
+		EnableHolePunching: true,
+		AnnounceAddrs:      []multiaddr.Multiaddr{announceAddr, noAnnounceAddr},
+		NoAnnounceAddrs:    []multiaddr.Multiaddr{noAnnounceAddr},
+		MaxMemory:          256 << 20,
+		MaxFileDescriptors: 512,
+	}.Options()
+	require.NoError(t, err)
+	h, err := p2p.CreateHost("/ip4/127.0.0.1/tcp/0", privateKey, opts...)
+	require.NoError(t, err)
+	defer h.Close()
+
+	// only the configured addresses are announced
+	assert.Equal(t, []multiaddr.Multiaddr{announceAddr}, h.Addrs())
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/celestiaorg/orchestrator-relayer/types"
 )
 
 // GetDataCommitmentConfirmKey creates a data commitment confirm in the
@@ -61,3 +63,29 @@ func ParseKey(key string) (namespace string, nonce uint64, evmAddr string, diges
 	}
 	return
 }
+
+// EncodeDataCommitmentConfirm encodes a data commitment confirm using the provided encoding.
+// The nonce and data root tuple root, required by the binary encoding, are taken from the key.
+func EncodeDataCommitmentConfirm(encoding types.ConfirmEncoding, key string, dcc types.DataCommitmentConfirm) ([]byte, error) {
+	if encoding == types.JSONConfirmEncoding {
+		return types.MarshalDataCommitmentConfirm(dcc)
+	}
+	_, nonce, _, dataRootTupleRoot, err := ParseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return types.EncodeDataCommitmentConfirm(encoding, nonce, dataRootTupleRoot, dcc)
+}
+
+// EncodeValsetConfirm encodes a valset confirm using the provided encoding.
+// The nonce and sign bytes, required by the binary encoding, are taken from the key.
+func EncodeValsetConfirm(encoding types.ConfirmEncoding, key string, vc types.ValsetConfirm) ([]byte, error) {
+	if encoding == types.JSONConfirmEncoding {
+		return types.MarshalValsetConfirm(vc)
+	}
+	_, nonce, _, signBytes, err := ParseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return types.EncodeValsetConfirm(encoding, nonce, signBytes, vc)
+}
@@ -0,0 +1,77 @@
+package p2p
+
+import (
+	"context"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/discovery/mdns"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	// MDNSServiceName the mDNS service name advertised by the QGB nodes.
+	// It's different from the libp2p default one so that only the QGB nodes are discovered.
+	MDNSServiceName = "_qgb-discovery._udp"
+	// mdnsConnectTimeout the timeout for connecting to a peer discovered using mDNS.
+	mdnsConnectTimeout = 10 * time.Second
+)
+
+var _ mdns.Notifee = &mdnsNotifee{}
+
+// mdnsNotifee connects to the peers discovered using mDNS.
+type mdnsNotifee struct {
+	ctx    context.Context
+	host   host.Host
+	logger tmlog.Logger
+}
+
+// NewMDNSNotifee creates the mDNS discovery notifee connecting the host to the discovered peers.
+// The connections are attempted until the context is done.
+func NewMDNSNotifee(ctx context.Context, h host.Host, logger tmlog.Logger) mdns.Notifee {
+	return &mdnsNotifee{
+		ctx:    ctx,
+		host:   h,
+		logger: logger,
+	}
+}
+
+// HandlePeerFound connects to the discovered peer. Once connected, the peer is added to the DHT routing
+// table if it supports the QGB DHT protocol.
+func (n *mdnsNotifee) HandlePeerFound(info peer.AddrInfo) {
+	if info.ID == n.host.ID() {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(n.ctx, mdnsConnectTimeout)
+		defer cancel()
+		err := n.host.Connect(ctx, info)
+		if err != nil {
+			n.logger.Debug("couldn't connect to peer discovered using mDNS", "peer", info.ID.String(), "err", err.Error())
+			return
+		}
+		n.logger.Info("connected to peer discovered using mDNS", "peer", info.ID.String())
+	}()
+}
+
+// StartMDNSDiscovery advertises the host on the local network using mDNS, and connects to the other QGB
+// nodes found on it, e.g. on the same LAN or docker network. This allows running local networks
+// without bootstrappers.
+// The discovery is stopped when the context is done.
+// This is a non-blocking call.
+func StartMDNSDiscovery(ctx context.Context, h host.Host, logger tmlog.Logger) error {
+	service := mdns.NewMdnsService(h, MDNSServiceName, NewMDNSNotifee(ctx, h, logger))
+	err := service.Start()
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		err := service.Close()
+		if err != nil {
+			logger.Error("couldn't stop the mDNS discovery", "err", err.Error())
+		}
+	}()
+	return nil
+}
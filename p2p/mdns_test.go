@@ -0,0 +1,57 @@
+package p2p_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	qgbtesting "github.com/celestiaorg/orchestrator-relayer/testing"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+func TestMDNSDiscovery(t *testing.T) {
+	if !qgbtesting.MulticastAvailable() {
+		t.Skip("skipping the mDNS discovery test as multicast is not available.")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the nodes are not given any bootstrapper
+	network := qgbtesting.NewMDNSDHTNetwork(ctx, 3)
+	defer network.Stop()
+
+	// all the nodes end up discovering each other
+	assert.Eventually(t, func() bool {
+		for i, dht := range network.DHTs {
+			for j, h := range network.Hosts {
+				if i != j && dht.RoutingTable().Find(h.ID()) == "" {
+					return false
+				}
+			}
+		}
+		return true
+	}, 30*time.Second, 100*time.Millisecond)
+}
+
+func TestMDNSNotifee(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the nodes are not given any bootstrapper
+	h1, _, dht1 := qgbtesting.NewTestDHT(ctx, nil)
+	defer dht1.Close()
+	h2, _, dht2 := qgbtesting.NewTestDHT(ctx, nil)
+	defer dht2.Close()
+
+	notifee := p2p.NewMDNSNotifee(ctx, h1, tmlog.NewNopLogger())
+	// the host itself is ignored
+	notifee.HandlePeerFound(peer.AddrInfo{ID: h1.ID(), Addrs: h1.Addrs()})
+	// the discovered peer is connected to, then added to the routing tables
+	notifee.HandlePeerFound(peer.AddrInfo{ID: h2.ID(), Addrs: h2.Addrs()})
+	assert.Eventually(t, func() bool {
+		return dht1.RoutingTable().Find(h2.ID()) != "" && dht2.RoutingTable().Find(h1.ID()) != ""
+	}, 10*time.Second, 10*time.Millisecond)
+}
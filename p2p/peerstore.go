@@ -0,0 +1,210 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/ipfs/go-datastore/query"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	pstore "github.com/libp2p/go-libp2p/p2p/host/peerstore"
+	"github.com/libp2p/go-libp2p/p2p/host/peerstore/pstoreds"
+	"github.com/libp2p/go-libp2p/p2p/host/peerstore/pstoremem"
+	"github.com/multiformats/go-multiaddr"
+)
+
+const (
+	// PeerstoreNamespace the datastore namespace under which the peerstore is persisted.
+	// It's separate from the DHT records namespaces.
+	PeerstoreNamespace = "/peerstore"
+	// KnownPeerAddrTTL the duration for which the addresses of the peers we were connected to are kept.
+	// This allows rejoining the network after a restart even if the bootstrappers are unreachable.
+	KnownPeerAddrTTL = 7 * 24 * time.Hour
+	// DefaultRememberPeersInterval the default interval at which the connected peers addresses
+	// are persisted with the KnownPeerAddrTTL.
+	DefaultRememberPeersInterval = time.Minute
+	// DefaultKnownPeersDialTimeout the default timeout for dialing a known peer on startup.
+	DefaultKnownPeersDialTimeout = 10 * time.Second
+)
+
+// keyBookPrefix the prefix under which the persistent libp2p keybook stores the peers keys, including
+// the host private key. Used to remove the keys persisted by the previous versions.
+var keyBookPrefix = ds.NewKey("/peers/keys")
+
+// NewPeerstore creates a peerstore whose address book is persisted in the provided datastore under
+// the PeerstoreNamespace. The keys, the protocols and the metadata of the peers are kept in memory, so that
+// the host private key is never written to the datastore.
+// It can be passed to the host using `HostConfig.Peerstore`.
+func NewPeerstore(ctx context.Context, store ds.Batching) (peerstore.Peerstore, error) {
+	peerstoreDS := namespace.Wrap(store, ds.NewKey(PeerstoreNamespace))
+	err := removePersistedKeys(ctx, peerstoreDS)
+	if err != nil {
+		return nil, err
+	}
+	addrBook, err := pstoreds.NewAddrBook(ctx, peerstoreDS, pstoreds.DefaultOpts())
+	if err != nil {
+		return nil, err
+	}
+	protoBook, err := pstoremem.NewProtoBook()
+	if err != nil {
+		_ = addrBook.Close()
+		return nil, err
+	}
+	return &addrsPersistentPeerstore{
+		Metrics:           pstore.NewMetrics(),
+		KeyBook:           pstoremem.NewKeyBook(),
+		certifiedAddrBook: addrBook,
+		ProtoBook:         protoBook,
+		PeerMetadata:      pstoremem.NewPeerMetadata(),
+	}, nil
+}
+
+// removePersistedKeys deletes the peers keys persisted in the peerstore datastore by the previous versions.
+func removePersistedKeys(ctx context.Context, store ds.Batching) error {
+	results, err := store.Query(ctx, query.Query{Prefix: keyBookPrefix.String(), KeysOnly: true})
+	if err != nil {
+		return err
+	}
+	entries, err := results.Rest()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		err := store.Delete(ctx, ds.NewKey(entry.Key))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	_ peerstore.Peerstore         = &addrsPersistentPeerstore{}
+	_ peerstore.CertifiedAddrBook = &addrsPersistentPeerstore{}
+)
+
+// certifiedAddrBook an address book also storing the signed peer records, required by the libp2p host.
+type certifiedAddrBook interface {
+	peerstore.AddrBook
+	peerstore.CertifiedAddrBook
+}
+
+// addrsPersistentPeerstore a peerstore combining a persistent address book with in-memory keys, protocols
+// and metadata books.
+type addrsPersistentPeerstore struct {
+	peerstore.Metrics
+	peerstore.KeyBook
+	certifiedAddrBook
+	peerstore.ProtoBook
+	peerstore.PeerMetadata
+}
+
+func (ps *addrsPersistentPeerstore) Close() error {
+	var errs []error
+	for _, book := range []interface{}{ps.KeyBook, ps.certifiedAddrBook, ps.ProtoBook, ps.PeerMetadata} {
+		if closer, ok := book.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (ps *addrsPersistentPeerstore) Peers() peer.IDSlice {
+	set := make(map[peer.ID]struct{})
+	for _, id := range ps.PeersWithKeys() {
+		set[id] = struct{}{}
+	}
+	for _, id := range ps.PeersWithAddrs() {
+		set[id] = struct{}{}
+	}
+	ids := make(peer.IDSlice, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (ps *addrsPersistentPeerstore) PeerInfo(id peer.ID) peer.AddrInfo {
+	return peer.AddrInfo{ID: id, Addrs: ps.Addrs(id)}
+}
+
+// RemovePeer removes the peer from all the books except the address book, similar to the libp2p peerstores.
+func (ps *addrsPersistentPeerstore) RemovePeer(id peer.ID) {
+	ps.KeyBook.RemovePeer(id)
+	ps.ProtoBook.RemovePeer(id)
+	ps.PeerMetadata.RemovePeer(id)
+	ps.Metrics.RemovePeer(id)
+}
+
+// RememberConnectedPeers persists the addresses of the connected peers with the KnownPeerAddrTTL
+// so that they outlive the connections, and the process, until the context is done.
+// Otherwise, libp2p only keeps the addresses for some minutes after the peers disconnect.
+// This is a non-blocking call.
+func RememberConnectedPeers(ctx context.Context, h host.Host, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, id := range h.Network().Peers() {
+					addrs := make([]multiaddr.Multiaddr, 0)
+					for _, addr := range h.Peerstore().Addrs(id) {
+						// the relayed addresses are not kept as the relays could be gone after a restart
+						if !isRelayed(addr) {
+							addrs = append(addrs, addr)
+						}
+					}
+					// setting the addresses TTL, instead of adding them, as they already have
+					// the connected TTL, which is reduced when the peer disconnects.
+					h.Peerstore().SetAddrs(id, addrs, KnownPeerAddrTTL)
+				}
+			}
+		}
+	}()
+}
+
+func isRelayed(addr multiaddr.Multiaddr) bool {
+	_, err := addr.ValueForProtocol(multiaddr.P_CIRCUIT)
+	return err == nil
+}
+
+// ConnectToKnownPeers dials, in parallel, the peers having addresses in the host peerstore,
+// e.g. the ones persisted before a restart.
+// Returns the number of peers that the host connected to.
+func ConnectToKnownPeers(ctx context.Context, h host.Host, timeout time.Duration) int {
+	var (
+		wg        sync.WaitGroup
+		mutex     sync.Mutex
+		connected int
+	)
+	for _, id := range h.Peerstore().PeersWithAddrs() {
+		if id == h.ID() {
+			continue
+		}
+		wg.Add(1)
+		go func(id peer.ID) {
+			defer wg.Done()
+			dialCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			if err := h.Connect(dialCtx, h.Peerstore().PeerInfo(id)); err != nil {
+				return
+			}
+			mutex.Lock()
+			connected++
+			mutex.Unlock()
+		}(id)
+	}
+	wg.Wait()
+	return connected
+}
@@ -0,0 +1,92 @@
+package p2p_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentPeerstore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dataStore := dssync.MutexWrap(ds.NewMapDatastore())
+	privateKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+
+	newHost := func(cfg p2p.HostConfig) host.Host {
+		opts, err := cfg.Options()
+		require.NoError(t, err)
+		h, err := p2p.CreateHost("/ip4/127.0.0.1/tcp/0", privateKey, opts...)
+		require.NoError(t, err)
+		return h
+	}
+	newPersistentHost := func() host.Host {
+		ps, err := p2p.NewPeerstore(ctx, dataStore)
+		require.NoError(t, err)
+		return newHost(p2p.HostConfig{Peerstore: ps})
+	}
+
+	otherKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	otherHost, err := p2p.CreateHost("/ip4/127.0.0.1/tcp/0", otherKey)
+	require.NoError(t, err)
+	defer otherHost.Close()
+
+	h1 := newPersistentHost()
+	require.NoError(t, h1.Connect(ctx, peer.AddrInfo{ID: otherHost.ID(), Addrs: otherHost.Addrs()}))
+	p2p.RememberConnectedPeers(ctx, h1, 10*time.Millisecond)
+	// waiting for the addresses TTL to be extended
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, h1.Close())
+
+	// the restarted host redials the peers known from the previous run
+	h2 := newPersistentHost()
+	defer h2.Close()
+	assert.Equal(t, 1, p2p.ConnectToKnownPeers(ctx, h2, time.Second))
+	assert.Len(t, h2.Network().ConnsToPeer(otherHost.ID()), 1)
+}
+
+func TestPeerstoreDoesNotPersistPrivateKey(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dataStore := dssync.MutexWrap(ds.NewMapDatastore())
+	privateKey, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	rawPrivateKey, err := privateKey.Raw()
+	require.NoError(t, err)
+
+	// a private key persisted by a previous version is removed
+	staleKey := ds.NewKey(p2p.PeerstoreNamespace).ChildString("/peers/keys/stale/priv")
+	require.NoError(t, dataStore.Put(ctx, staleKey, rawPrivateKey))
+
+	ps, err := p2p.NewPeerstore(ctx, dataStore)
+	require.NoError(t, err)
+	opts, err := p2p.HostConfig{Peerstore: ps}.Options()
+	require.NoError(t, err)
+	h, err := p2p.CreateHost("/ip4/127.0.0.1/tcp/0", privateKey, opts...)
+	require.NoError(t, err)
+	defer h.Close()
+	// the host private key is added to the peerstore on creation
+	assert.NotNil(t, h.Peerstore().PrivKey(h.ID()))
+
+	results, err := dataStore.Query(ctx, query.Query{})
+	require.NoError(t, err)
+	entries, err := results.Rest()
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Key, "/peers/keys")
+		assert.False(t, bytes.Contains(entry.Value, rawPrivateKey), "private key found under %s", entry.Key)
+	}
+}
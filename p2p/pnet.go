@@ -0,0 +1,67 @@
+package p2p
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/pnet"
+	"github.com/libp2p/go-libp2p/p2p/transport/tcp"
+	"github.com/libp2p/go-libp2p/p2p/transport/websocket"
+)
+
+const (
+	// SwarmKeyLength the length, in bytes, of the private network pre-shared key.
+	SwarmKeyLength = 32
+	// swarmKeyHeader the header of the libp2p V1 pre-shared key files, with the base16 encoding.
+	swarmKeyHeader = "/key/swarm/psk/1.0.0/\n/base16/\n"
+)
+
+// GenerateSwarmKey generates a new random private network pre-shared key.
+func GenerateSwarmKey() (pnet.PSK, error) {
+	psk := make([]byte, SwarmKeyLength)
+	_, err := rand.Read(psk)
+	if err != nil {
+		return nil, err
+	}
+	return psk, nil
+}
+
+// MarshalSwarmKey encodes the pre-shared key using the libp2p V1 swarm key format, which is
+// compatible with the IPFS `swarm.key` files.
+func MarshalSwarmKey(psk pnet.PSK) []byte {
+	return []byte(swarmKeyHeader + hex.EncodeToString(psk) + "\n")
+}
+
+// UnmarshalSwarmKey decodes a pre-shared key encoded using the libp2p V1 swarm key format.
+func UnmarshalSwarmKey(encoded []byte) (pnet.PSK, error) {
+	psk, err := pnet.DecodeV1PSK(bytes.NewReader(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSwarmKey, err.Error())
+	}
+	return psk, nil
+}
+
+// LoadSwarmKey reads the private network pre-shared key from the provided file.
+func LoadSwarmKey(path string) (pnet.PSK, error) {
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalSwarmKey(encoded)
+}
+
+// PrivateNetworkOptions returns the host options to join the private network defined by the
+// pre-shared key. The peers not having the same key fail to connect at the transport level.
+// Only the TCP and websocket transports are enabled, as the QUIC based ones don't support
+// private networks.
+func PrivateNetworkOptions(psk pnet.PSK) []libp2p.Option {
+	return []libp2p.Option{
+		libp2p.PrivateNetwork(psk),
+		libp2p.Transport(tcp.NewTCPTransport),
+		libp2p.Transport(websocket.New),
+	}
+}
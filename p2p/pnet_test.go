@@ -0,0 +1,62 @@
+package p2p_test
+
+import (
+	"context"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwarmKeyRoundTrip(t *testing.T) {
+	psk, err := p2p.GenerateSwarmKey()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "swarm.key")
+	require.NoError(t, os.WriteFile(path, p2p.MarshalSwarmKey(psk), 0o600))
+
+	loaded, err := p2p.LoadSwarmKey(path)
+	require.NoError(t, err)
+	assert.Equal(t, psk, loaded)
+
+	_, err = p2p.UnmarshalSwarmKey([]byte("/key/swarm/psk/1.0.0/\n/base16/\n1234\n"))
+	assert.ErrorIs(t, err, p2p.ErrInvalidSwarmKey)
+}
+
+func TestPrivateNetwork(t *testing.T) {
+	ctx := context.Background()
+	psk, err := p2p.GenerateSwarmKey()
+	require.NoError(t, err)
+	otherPSK, err := p2p.GenerateSwarmKey()
+	require.NoError(t, err)
+
+	newHost := func(opts ...libp2p.Option) host.Host {
+		key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(t, err)
+		h, err := p2p.CreateHost("/ip4/127.0.0.1/tcp/0", key, opts...)
+		require.NoError(t, err)
+		return h
+	}
+	h1 := newHost(p2p.PrivateNetworkOptions(psk)...)
+	defer h1.Close()
+	h2 := newHost(p2p.PrivateNetworkOptions(psk)...)
+	defer h2.Close()
+	h3 := newHost(p2p.PrivateNetworkOptions(otherPSK)...)
+	defer h3.Close()
+	h4 := newHost()
+	defer h4.Close()
+
+	// the peers sharing the same key can connect
+	assert.NoError(t, h2.Connect(ctx, peer.AddrInfo{ID: h1.ID(), Addrs: h1.Addrs()}))
+	// the peers using a different key, or no key, can't
+	assert.Error(t, h3.Connect(ctx, peer.AddrInfo{ID: h1.ID(), Addrs: h1.Addrs()}))
+	assert.Error(t, h4.Connect(ctx, peer.AddrInfo{ID: h1.ID(), Addrs: h1.Addrs()}))
+}
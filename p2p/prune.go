@@ -0,0 +1,208 @@
+package p2p
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	"github.com/multiformats/go-base32"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+// DefaultPruneInterval the default interval between two confirms pruning.
+const DefaultPruneInterval = 10 * time.Minute
+
+// PruneDHTConfirms deletes, from the DHT datastore, the confirms records whose nonces are lower than
+// the provided nonce. The records of all the protocol versions are pruned.
+// The other records, e.g. the peerstore ones, are left untouched.
+// Returns the number of deleted records.
+func PruneDHTConfirms(ctx context.Context, store ds.Batching, belowNonce uint64) (int, error) {
+	return pruneConfirms(ctx, store, belowNonce, dhtRecordKey)
+}
+
+// PruneArchivedConfirms deletes, from the relayer signature store, the confirms whose nonces are lower
+// than the provided nonce.
+// Returns the number of deleted confirms.
+func PruneArchivedConfirms(ctx context.Context, store ds.Batching, belowNonce uint64) (int, error) {
+	return pruneConfirms(ctx, store, belowNonce, func(key ds.Key) (string, bool) {
+		return key.String(), true
+	})
+}
+
+// pruneConfirms deletes the confirms whose nonces are lower than the provided nonce.
+// The recordKey function returns the confirm key corresponding to a datastore key, or false if the
+// datastore key doesn't hold a confirm.
+func pruneConfirms(ctx context.Context, store ds.Batching, belowNonce uint64, recordKey func(ds.Key) (string, bool)) (int, error) {
+	results, err := store.Query(ctx, query.Query{KeysOnly: true})
+	if err != nil {
+		return 0, err
+	}
+	entries, err := results.Rest()
+	if err != nil {
+		return 0, err
+	}
+
+	batch, err := store.Batch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, entry := range entries {
+		key := ds.RawKey(entry.Key)
+		confirmKey, ok := recordKey(key)
+		if !ok {
+			continue
+		}
+		namespace, nonce, _, _, err := ParseKey(confirmKey)
+		if err != nil || (namespace != ValsetConfirmNamespace && namespace != DataCommitmentConfirmNamespace) {
+			continue
+		}
+		if nonce >= belowNonce {
+			continue
+		}
+		err = batch.Delete(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+		count++
+	}
+	err = batch.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// dhtRecordKey returns the record key corresponding to a DHT datastore key.
+// The DHT stores the records under their base32 encoded keys, in the root namespace for the
+// initial protocol version, and under the "/dht/<version>" namespace for the others.
+func dhtRecordKey(key ds.Key) (string, bool) {
+	if strings.HasPrefix(key.String(), PeerstoreNamespace+"/") {
+		return "", false
+	}
+	namespaces := key.Namespaces()
+	if len(namespaces) != 1 && !(len(namespaces) == 3 && namespaces[0] == "dht") {
+		return "", false
+	}
+	decoded, err := base32.RawStdEncoding.DecodeString(key.BaseNamespace())
+	if err != nil {
+		return "", false
+	}
+	return string(decoded), true
+}
+
+// Pruner periodically deletes the confirms that are not needed anymore, so that the stores don't
+// grow indefinitely.
+// The confirms are pruned below the latest attestation nonce minus the nonce window, or below the
+// last relayed nonce, whichever is higher. However, the confirms inside the unbonding period are never pruned,
+// as the orchestrators sign them again when they're missing.
+type Pruner struct {
+	// DataStore the DHT datastore.
+	DataStore ds.Batching
+	// SignatureStore the relayer signature store. Nil if the signature archive is not pruned.
+	SignatureStore ds.Batching
+	// NonceWindow the number of nonces, below the latest one, whose confirms are kept.
+	// Zero disables the pruning based on the latest nonce.
+	NonceWindow uint64
+	// LatestNonce returns the latest attestation nonce. Required if the nonce window is set.
+	LatestNonce func(ctx context.Context) (uint64, error)
+	// LastRelayedNonce returns the last nonce relayed to the QGB contract.
+	// Nil disables the pruning based on the last relayed nonce.
+	LastRelayedNonce func(ctx context.Context) (uint64, error)
+	// UnbondingNonce returns the first nonce inside the unbonding period, whose confirms, along with the
+	// following nonces ones, are kept whatever the other settings.
+	// Nil disables this protection, and should only be used in tests.
+	UnbondingNonce func(ctx context.Context) (uint64, error)
+	Logger         tmlog.Logger
+}
+
+// Enabled returns true if the pruner has a policy to prune with.
+func (p Pruner) Enabled() bool {
+	return (p.NonceWindow != 0 && p.LatestNonce != nil) || p.LastRelayedNonce != nil
+}
+
+// PruneBelow returns the nonce below which the confirms can be pruned.
+// Returns 0 if nothing can be pruned yet.
+func (p Pruner) PruneBelow(ctx context.Context) (uint64, error) {
+	var belowNonce uint64
+	if p.NonceWindow != 0 && p.LatestNonce != nil {
+		latestNonce, err := p.LatestNonce(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if latestNonce > p.NonceWindow {
+			belowNonce = latestNonce - p.NonceWindow
+		}
+	}
+	if p.LastRelayedNonce != nil {
+		lastRelayedNonce, err := p.LastRelayedNonce(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if lastRelayedNonce > belowNonce {
+			belowNonce = lastRelayedNonce
+		}
+	}
+	if belowNonce == 0 || p.UnbondingNonce == nil {
+		return belowNonce, nil
+	}
+	unbondingNonce, err := p.UnbondingNonce(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if belowNonce > unbondingNonce {
+		belowNonce = unbondingNonce
+	}
+	return belowNonce, nil
+}
+
+// Prune deletes the confirms below the nonce defined by the pruning policy.
+func (p Pruner) Prune(ctx context.Context) error {
+	belowNonce, err := p.PruneBelow(ctx)
+	if err != nil {
+		return err
+	}
+	if belowNonce == 0 {
+		return nil
+	}
+	if p.DataStore != nil {
+		count, err := PruneDHTConfirms(ctx, p.DataStore, belowNonce)
+		if err != nil {
+			return err
+		}
+		p.Logger.Debug("pruned DHT confirms", "below_nonce", belowNonce, "count", count)
+	}
+	if p.SignatureStore != nil {
+		count, err := PruneArchivedConfirms(ctx, p.SignatureStore, belowNonce)
+		if err != nil {
+			return err
+		}
+		p.Logger.Debug("pruned archived confirms", "below_nonce", belowNonce, "count", count)
+	}
+	return nil
+}
+
+// Start prunes the confirms every interval until the context is done.
+// This is a non-blocking call.
+func (p Pruner) Start(ctx context.Context, interval time.Duration) {
+	if !p.Enabled() {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			err := p.Prune(ctx)
+			if err != nil && ctx.Err() == nil {
+				p.Logger.Error("couldn't prune the confirms", "err", err.Error())
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,173 @@
+package p2p_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/multiformats/go-base32"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+// dhtKey returns the datastore key under which the DHT stores the provided record key.
+func dhtKey(namespace string, key string) ds.Key {
+	return ds.NewKey(namespace).ChildString(base32.RawStdEncoding.EncodeToString([]byte(key)))
+}
+
+func TestPruneDHTConfirms(t *testing.T) {
+	ctx := context.Background()
+	store := ds.NewMapDatastore()
+
+	oldValset := dhtKey("/", p2p.GetValsetConfirmKey(1, evmAddress, "0x1234"))
+	oldDataCommitment := dhtKey("/", p2p.GetDataCommitmentConfirmKey(2, evmAddress, "0x1234"))
+	oldVersioned := dhtKey("/dht/0.2.0", p2p.GetDataCommitmentConfirmKey(2, evmAddress, "0x1234"))
+	recentValset := dhtKey("/", p2p.GetValsetConfirmKey(3, evmAddress, "0x1234"))
+	recentVersioned := dhtKey("/dht/0.2.0", p2p.GetDataCommitmentConfirmKey(4, evmAddress, "0x1234"))
+	peerstoreKey := ds.NewKey(p2p.PeerstoreNamespace + "/addrs/" + base32.RawStdEncoding.EncodeToString([]byte("/vc/1:a:b")))
+	otherKey := dhtKey("/", "/pk/1234")
+	for _, key := range []ds.Key{oldValset, oldDataCommitment, oldVersioned, recentValset, recentVersioned, peerstoreKey, otherKey} {
+		require.NoError(t, store.Put(ctx, key, []byte("record")))
+	}
+
+	count, err := p2p.PruneDHTConfirms(ctx, store, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	for _, key := range []ds.Key{oldValset, oldDataCommitment, oldVersioned} {
+		has, err := store.Has(ctx, key)
+		require.NoError(t, err)
+		assert.False(t, has, key.String())
+	}
+	for _, key := range []ds.Key{recentValset, recentVersioned, peerstoreKey, otherKey} {
+		has, err := store.Has(ctx, key)
+		require.NoError(t, err)
+		assert.True(t, has, key.String())
+	}
+}
+
+func TestPruneArchivedConfirms(t *testing.T) {
+	ctx := context.Background()
+	store := ds.NewMapDatastore()
+
+	oldKey := ds.NewKey(p2p.GetValsetConfirmKey(1, evmAddress, "0x1234"))
+	recentKey := ds.NewKey(p2p.GetDataCommitmentConfirmKey(5, evmAddress, "0x1234"))
+	require.NoError(t, store.Put(ctx, oldKey, []byte("confirm")))
+	require.NoError(t, store.Put(ctx, recentKey, []byte("confirm")))
+
+	count, err := p2p.PruneArchivedConfirms(ctx, store, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	has, err := store.Has(ctx, oldKey)
+	require.NoError(t, err)
+	assert.False(t, has)
+	has, err = store.Has(ctx, recentKey)
+	require.NoError(t, err)
+	assert.True(t, has)
+}
+
+func TestPrunerPruneBelow(t *testing.T) {
+	ctx := context.Background()
+	latestNonce := func(context.Context) (uint64, error) { return 100, nil }
+	lastRelayedNonce := func(context.Context) (uint64, error) { return 95, nil }
+
+	tests := []struct {
+		name     string
+		pruner   p2p.Pruner
+		enabled  bool
+		expected uint64
+	}{
+		{
+			name:     "disabled",
+			pruner:   p2p.Pruner{LatestNonce: latestNonce},
+			enabled:  false,
+			expected: 0,
+		},
+		{
+			name:     "nonce window",
+			pruner:   p2p.Pruner{NonceWindow: 10, LatestNonce: latestNonce},
+			enabled:  true,
+			expected: 90,
+		},
+		{
+			name:     "nonce window larger than the latest nonce",
+			pruner:   p2p.Pruner{NonceWindow: 1000, LatestNonce: latestNonce},
+			enabled:  true,
+			expected: 0,
+		},
+		{
+			name:     "last relayed nonce higher than the window",
+			pruner:   p2p.Pruner{NonceWindow: 10, LatestNonce: latestNonce, LastRelayedNonce: lastRelayedNonce},
+			enabled:  true,
+			expected: 95,
+		},
+		{
+			name:     "window higher than the last relayed nonce",
+			pruner:   p2p.Pruner{NonceWindow: 2, LatestNonce: latestNonce, LastRelayedNonce: lastRelayedNonce},
+			enabled:  true,
+			expected: 98,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.pruner.Logger = tmlog.NewNopLogger()
+			assert.Equal(t, tt.enabled, tt.pruner.Enabled())
+			belowNonce, err := tt.pruner.PruneBelow(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, belowNonce)
+		})
+	}
+}
+
+func TestPrunerKeepsUnbondingPeriodConfirms(t *testing.T) {
+	ctx := context.Background()
+	latestNonce := func(context.Context) (uint64, error) { return 100, nil }
+	lastRelayedNonce := func(context.Context) (uint64, error) { return 95, nil }
+	unbondingNonce := func(context.Context) (uint64, error) { return 80, nil }
+
+	// the window and the last relayed nonce are inside the unbonding period
+	pruner := p2p.Pruner{
+		NonceWindow:      10,
+		LatestNonce:      latestNonce,
+		LastRelayedNonce: lastRelayedNonce,
+		UnbondingNonce:   unbondingNonce,
+		Logger:           tmlog.NewNopLogger(),
+	}
+	belowNonce, err := pruner.PruneBelow(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(80), belowNonce)
+
+	// the window is larger than the unbonding period
+	pruner = p2p.Pruner{
+		NonceWindow:    30,
+		LatestNonce:    latestNonce,
+		UnbondingNonce: unbondingNonce,
+		Logger:         tmlog.NewNopLogger(),
+	}
+	belowNonce, err = pruner.PruneBelow(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(70), belowNonce)
+
+	// the confirms inside the unbonding period are kept in the store
+	store := ds.NewMapDatastore()
+	for _, nonce := range []uint64{10, 79, 80, 95} {
+		key := ds.NewKey(p2p.GetValsetConfirmKey(nonce, evmAddress, "0x1234"))
+		require.NoError(t, store.Put(ctx, key, []byte("confirm")))
+	}
+	pruner = p2p.Pruner{
+		SignatureStore:   store,
+		LastRelayedNonce: lastRelayedNonce,
+		UnbondingNonce:   unbondingNonce,
+		Logger:           tmlog.NewNopLogger(),
+	}
+	require.NoError(t, pruner.Prune(ctx))
+	for nonce, kept := range map[uint64]bool{10: false, 79: false, 80: true, 95: true} {
+		key := ds.NewKey(p2p.GetValsetConfirmKey(nonce, evmAddress, "0x1234"))
+		has, err := store.Has(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, kept, has, "nonce %d", nonce)
+	}
+}
@@ -0,0 +1,334 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	DataCommitmentConfirmTopic = ProtocolPrefix + "/" + DataCommitmentConfirmNamespace
+	ValsetConfirmTopic         = ProtocolPrefix + "/" + ValsetConfirmNamespace
+	// MaxCachedNonces the maximum number of nonces, per confirm type, for which the received confirms are kept
+	// in memory. When exceeded, the confirms of the lowest nonce are evicted.
+	MaxCachedNonces = 100
+)
+
+// ConfirmMessage the message gossiped on the confirms topics.
+// It contains the DHT key of the confirm along with its encoded value, so that
+// the same validators used for the DHT can be used to validate the gossiped confirms.
+type ConfirmMessage struct {
+	Key   string
+	Value []byte
+}
+
+// MarshalConfirmMessage Encodes a confirm message to Json bytes.
+func MarshalConfirmMessage(msg ConfirmMessage) ([]byte, error) {
+	encoded, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return encoded, nil
+}
+
+// UnmarshalConfirmMessage Decodes a confirm message from Json bytes.
+func UnmarshalConfirmMessage(encoded []byte) (ConfirmMessage, error) {
+	var msg ConfirmMessage
+	err := json.Unmarshal(encoded, &msg)
+	if err != nil {
+		return ConfirmMessage{}, err
+	}
+	return msg, nil
+}
+
+// QgbPubSub wrapper around the libp2p gossipsub implementation.
+// Used to propagate the confirms in near real time alongside the DHT, which stays
+// the durable storage for confirms.
+// The received confirms are cached in memory and can be queried by their DHT key.
+type QgbPubSub struct {
+	*pubsub.PubSub
+	// ConfirmEncoding the encoding used when publishing confirms.
+	// Defaults to the legacy Json encoding.
+	ConfirmEncoding     types.ConfirmEncoding
+	dataCommitmentTopic *pubsub.Topic
+	valsetTopic         *pubsub.Topic
+	logger              tmlog.Logger
+
+	mutex                  *sync.RWMutex
+	dataCommitmentConfirms *confirmsCache
+	valsetConfirms         *confirmsCache
+	// notify receives a signal every time a new confirm is cached.
+	notify chan struct{}
+	// relayCancels cancel the topics relays started using Relay.
+	relayCancels []pubsub.RelayCancelFunc
+}
+
+// NewQgbPubSub creates a new gossipsub router and joins the confirms topics.
+// The topics use the same validators as the DHT, i.e. `DataCommitmentConfirmValidator`
+// and `ValsetConfirmValidator`.
+func NewQgbPubSub(ctx context.Context, h host.Host, logger tmlog.Logger) (*QgbPubSub, error) {
+	ps, err := pubsub.NewGossipSub(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ps.RegisterTopicValidator(DataCommitmentConfirmTopic, topicValidator(DataCommitmentConfirmValidator{}))
+	if err != nil {
+		return nil, err
+	}
+	err = ps.RegisterTopicValidator(ValsetConfirmTopic, topicValidator(ValsetConfirmValidator{}))
+	if err != nil {
+		return nil, err
+	}
+
+	dcTopic, err := ps.Join(DataCommitmentConfirmTopic)
+	if err != nil {
+		return nil, err
+	}
+	vsTopic, err := ps.Join(ValsetConfirmTopic)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QgbPubSub{
+		PubSub:                 ps,
+		dataCommitmentTopic:    dcTopic,
+		valsetTopic:            vsTopic,
+		logger:                 logger,
+		mutex:                  &sync.RWMutex{},
+		dataCommitmentConfirms: newConfirmsCache(MaxCachedNonces),
+		valsetConfirms:         newConfirmsCache(MaxCachedNonces),
+		notify:                 make(chan struct{}, 1),
+	}, nil
+}
+
+// UseChainView replaces the topics validators with the chain aware ones, i.e. `ChainDataCommitmentConfirmValidator`
+// and `ChainValsetConfirmValidator`.
+func (ps *QgbPubSub) UseChainView(chain *ChainView) error {
+	err := ps.UnregisterTopicValidator(DataCommitmentConfirmTopic)
+	if err != nil {
+		return err
+	}
+	err = ps.RegisterTopicValidator(DataCommitmentConfirmTopic, topicValidator(ChainDataCommitmentConfirmValidator{ChainView: chain}))
+	if err != nil {
+		return err
+	}
+	err = ps.UnregisterTopicValidator(ValsetConfirmTopic)
+	if err != nil {
+		return err
+	}
+	return ps.RegisterTopicValidator(ValsetConfirmTopic, topicValidator(ChainValsetConfirmValidator{ChainView: chain}))
+}
+
+// topicValidator wraps a DHT confirm validator to be used as a gossipsub topic validator.
+func topicValidator(validator interface {
+	Validate(key string, value []byte) error
+},
+) pubsub.Validator {
+	return func(_ context.Context, _ peer.ID, msg *pubsub.Message) bool {
+		confirmMsg, err := UnmarshalConfirmMessage(msg.Data)
+		if err != nil {
+			return false
+		}
+		return validator.Validate(confirmMsg.Key, confirmMsg.Value) == nil
+	}
+}
+
+// PublishDataCommitmentConfirm encodes a data commitment confirm then publishes it to the data commitment
+// confirms topic.
+// The key can be generated using the `GetDataCommitmentConfirmKey` method.
+func (ps *QgbPubSub) PublishDataCommitmentConfirm(ctx context.Context, key string, dcc types.DataCommitmentConfirm) error {
+	encodedConfirm, err := EncodeDataCommitmentConfirm(ps.ConfirmEncoding, key, dcc)
+	if err != nil {
+		return err
+	}
+	encodedMsg, err := MarshalConfirmMessage(ConfirmMessage{Key: key, Value: encodedConfirm})
+	if err != nil {
+		return err
+	}
+	return ps.dataCommitmentTopic.Publish(ctx, encodedMsg)
+}
+
+// PublishValsetConfirm encodes a valset confirm then publishes it to the valset confirms topic.
+// The key can be generated using the `GetValsetConfirmKey` method.
+func (ps *QgbPubSub) PublishValsetConfirm(ctx context.Context, key string, vc types.ValsetConfirm) error {
+	encodedConfirm, err := EncodeValsetConfirm(ps.ConfirmEncoding, key, vc)
+	if err != nil {
+		return err
+	}
+	encodedMsg, err := MarshalConfirmMessage(ConfirmMessage{Key: key, Value: encodedConfirm})
+	if err != nil {
+		return err
+	}
+	return ps.valsetTopic.Publish(ctx, encodedMsg)
+}
+
+// Subscribe subscribes to the confirms topics and caches the received confirms until
+// the context is done.
+// This is a non-blocking call.
+func (ps *QgbPubSub) Subscribe(ctx context.Context) error {
+	dcSub, err := ps.dataCommitmentTopic.Subscribe()
+	if err != nil {
+		return err
+	}
+	vsSub, err := ps.valsetTopic.Subscribe()
+	if err != nil {
+		dcSub.Cancel()
+		return err
+	}
+	go ps.handleSubscription(ctx, dcSub, ps.dataCommitmentConfirms)
+	go ps.handleSubscription(ctx, vsSub, ps.valsetConfirms)
+	return nil
+}
+
+// Relay joins the confirms topics mesh to forward the confirms to the other peers, without receiving them.
+// Gossipsub only forwards messages through the peers interested in their topic, so the nodes that don't
+// subscribe, e.g. the orchestrators and the bootstrappers, should relay for the confirms to reach the
+// peers they're not directly connected to.
+// The relays are stopped when closing the pubsub.
+func (ps *QgbPubSub) Relay() error {
+	for _, topic := range []*pubsub.Topic{ps.dataCommitmentTopic, ps.valsetTopic} {
+		cancel, err := topic.Relay()
+		if err != nil {
+			return err
+		}
+		ps.relayCancels = append(ps.relayCancels, cancel)
+	}
+	return nil
+}
+
+func (ps *QgbPubSub) handleSubscription(ctx context.Context, sub *pubsub.Subscription, cache *confirmsCache) {
+	defer sub.Cancel()
+	for {
+		msg, err := sub.Next(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				ps.logger.Error("stopped receiving confirms", "topic", sub.Topic(), "err", err.Error())
+			}
+			return
+		}
+		// the message was already validated by the topic validator
+		confirmMsg, err := UnmarshalConfirmMessage(msg.Data)
+		if err != nil {
+			continue
+		}
+		_, nonce, _, _, err := ParseKey(confirmMsg.Key)
+		if err != nil {
+			continue
+		}
+		ps.mutex.Lock()
+		cache.add(nonce, confirmMsg.Key, confirmMsg.Value)
+		ps.mutex.Unlock()
+		ps.logger.Debug("received confirm", "topic", sub.Topic(), "nonce", nonce, "from", msg.ReceivedFrom.String())
+
+		// non-blocking signal
+		select {
+		case ps.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Notify returns a channel that receives a signal when new confirms are received.
+func (ps *QgbPubSub) Notify() <-chan struct{} {
+	return ps.notify
+}
+
+// GetDataCommitmentConfirm looks for a data commitment confirm, referenced by its key, in the received confirms.
+// Returns false if the confirm was not received.
+func (ps *QgbPubSub) GetDataCommitmentConfirm(key string) (types.DataCommitmentConfirm, bool) {
+	ps.mutex.RLock()
+	encodedConfirm, found := ps.dataCommitmentConfirms.get(key)
+	ps.mutex.RUnlock()
+	if !found {
+		return types.DataCommitmentConfirm{}, false
+	}
+	confirm, err := types.UnmarshalDataCommitmentConfirm(encodedConfirm)
+	if err != nil {
+		return types.DataCommitmentConfirm{}, false
+	}
+	return confirm, true
+}
+
+// GetValsetConfirm looks for a valset confirm, referenced by its key, in the received confirms.
+// Returns false if the confirm was not received.
+func (ps *QgbPubSub) GetValsetConfirm(key string) (types.ValsetConfirm, bool) {
+	ps.mutex.RLock()
+	encodedConfirm, found := ps.valsetConfirms.get(key)
+	ps.mutex.RUnlock()
+	if !found {
+		return types.ValsetConfirm{}, false
+	}
+	confirm, err := types.UnmarshalValsetConfirm(encodedConfirm)
+	if err != nil {
+		return types.ValsetConfirm{}, false
+	}
+	return confirm, true
+}
+
+// Close stops the relays and leaves the confirms topics.
+func (ps *QgbPubSub) Close() error {
+	for _, cancel := range ps.relayCancels {
+		cancel()
+	}
+	ps.relayCancels = nil
+	err := ps.dataCommitmentTopic.Close()
+	if err != nil {
+		return err
+	}
+	return ps.valsetTopic.Close()
+}
+
+// confirmsCache keeps the encoded confirms by nonce.
+// Not thread safe.
+type confirmsCache struct {
+	maxNonces int
+	nonces    map[uint64]map[string][]byte
+	keys      map[string]uint64
+}
+
+func newConfirmsCache(maxNonces int) *confirmsCache {
+	return &confirmsCache{
+		maxNonces: maxNonces,
+		nonces:    make(map[uint64]map[string][]byte),
+		keys:      make(map[string]uint64),
+	}
+}
+
+func (c *confirmsCache) add(nonce uint64, key string, value []byte) {
+	confirms, found := c.nonces[nonce]
+	if !found {
+		confirms = make(map[string][]byte)
+		c.nonces[nonce] = confirms
+	}
+	confirms[key] = value
+	c.keys[key] = nonce
+
+	if len(c.nonces) > c.maxNonces {
+		lowest := nonce
+		for n := range c.nonces {
+			if n < lowest {
+				lowest = n
+			}
+		}
+		for k := range c.nonces[lowest] {
+			delete(c.keys, k)
+		}
+		delete(c.nonces, lowest)
+	}
+}
+
+func (c *confirmsCache) get(key string) ([]byte, bool) {
+	nonce, found := c.keys[key]
+	if !found {
+		return nil, false
+	}
+	value, found := c.nonces[nonce][key]
+	return value, found
+}
@@ -0,0 +1,144 @@
+package p2p_test
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/celestiaorg/orchestrator-relayer/evm"
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	qgbtesting "github.com/celestiaorg/orchestrator-relayer/testing"
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+func TestPubSubDataCommitmentConfirm(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	network := qgbtesting.NewDHTNetwork(ctx, 2)
+	defer network.Stop()
+
+	publisher, err := p2p.NewQgbPubSub(ctx, network.Hosts[0], tmlog.NewNopLogger())
+	require.NoError(t, err)
+	subscriber, err := p2p.NewQgbPubSub(ctx, network.Hosts[1], tmlog.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, subscriber.Subscribe(ctx))
+
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	acc, err := ks.ImportECDSA(privateKey, "123")
+	require.NoError(t, err)
+	err = ks.Unlock(acc, "123")
+	require.NoError(t, err)
+
+	nonce := uint64(10)
+	commitment := "1234"
+	bCommitment, _ := hex.DecodeString(commitment)
+	dataRootHash := types.DataCommitmentTupleRootSignBytes(big.NewInt(int64(nonce)), bCommitment)
+	signature, err := evm.NewEthereumSignature(dataRootHash.Bytes(), ks, acc)
+	require.NoError(t, err)
+
+	expectedConfirm := types.DataCommitmentConfirm{
+		EthAddress: evmAddress,
+		Signature:  hex.EncodeToString(signature),
+	}
+	testKey := p2p.GetDataCommitmentConfirmKey(nonce, evmAddress, dataRootHash.Hex())
+
+	// publish until the gossipsub mesh is formed and the confirm is received
+	assert.Eventually(t, func() bool {
+		err := publisher.PublishDataCommitmentConfirm(ctx, testKey, expectedConfirm)
+		require.NoError(t, err)
+		_, found := subscriber.GetDataCommitmentConfirm(testKey)
+		return found
+	}, 10*time.Second, 100*time.Millisecond)
+
+	actualConfirm, found := subscriber.GetDataCommitmentConfirm(testKey)
+	assert.True(t, found)
+	assert.Equal(t, expectedConfirm, actualConfirm)
+}
+
+func TestPubSubInvalidValsetConfirm(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	network := qgbtesting.NewDHTNetwork(ctx, 2)
+	defer network.Stop()
+
+	publisher, err := p2p.NewQgbPubSub(ctx, network.Hosts[0], tmlog.NewNopLogger())
+	require.NoError(t, err)
+	subscriber, err := p2p.NewQgbPubSub(ctx, network.Hosts[1], tmlog.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, subscriber.Subscribe(ctx))
+
+	// a confirm whose signature wasn't created by the evm address in the key
+	invalidConfirm := types.ValsetConfirm{
+		EthAddress: evmAddress,
+		Signature:  "0x1234",
+	}
+	testKey := p2p.GetValsetConfirmKey(10, evmAddress, "0x1234")
+
+	// the publisher runs the topic validators on its own messages
+	err = publisher.PublishValsetConfirm(ctx, testKey, invalidConfirm)
+	assert.Error(t, err)
+	_, found := subscriber.GetValsetConfirm(testKey)
+	assert.False(t, found)
+}
+
+func TestPubSubRelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the publisher reaches the subscriber only through the relaying node
+	hosts := make([]host.Host, 3)
+	for i := range hosts {
+		h, err := libp2p.New()
+		require.NoError(t, err)
+		defer h.Close()
+		hosts[i] = h
+	}
+	require.NoError(t, hosts[0].Connect(ctx, peer.AddrInfo{ID: hosts[1].ID(), Addrs: hosts[1].Addrs()}))
+	require.NoError(t, hosts[2].Connect(ctx, peer.AddrInfo{ID: hosts[1].ID(), Addrs: hosts[1].Addrs()}))
+
+	publisher, err := p2p.NewQgbPubSub(ctx, hosts[0], tmlog.NewNopLogger())
+	require.NoError(t, err)
+	relay, err := p2p.NewQgbPubSub(ctx, hosts[1], tmlog.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, relay.Relay())
+	defer relay.Close()
+	subscriber, err := p2p.NewQgbPubSub(ctx, hosts[2], tmlog.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, subscriber.Subscribe(ctx))
+
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	acc, err := ks.ImportECDSA(privateKey, "123")
+	require.NoError(t, err)
+	require.NoError(t, ks.Unlock(acc, "123"))
+	nonce := uint64(10)
+	bCommitment, _ := hex.DecodeString("1234")
+	dataRootHash := types.DataCommitmentTupleRootSignBytes(big.NewInt(int64(nonce)), bCommitment)
+	signature, err := evm.NewEthereumSignature(dataRootHash.Bytes(), ks, acc)
+	require.NoError(t, err)
+	expectedConfirm := types.DataCommitmentConfirm{
+		EthAddress: evmAddress,
+		Signature:  hex.EncodeToString(signature),
+	}
+	testKey := p2p.GetDataCommitmentConfirmKey(nonce, evmAddress, dataRootHash.Hex())
+
+	// publish until the gossipsub mesh is formed and the confirm is received
+	assert.Eventually(t, func() bool {
+		err := publisher.PublishDataCommitmentConfirm(ctx, testKey, expectedConfirm)
+		require.NoError(t, err)
+		_, found := subscriber.GetDataCommitmentConfirm(testKey)
+		return found
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// the relaying node forwards the confirms without receiving them
+	_, found := relay.GetDataCommitmentConfirm(testKey)
+	assert.False(t, found)
+}
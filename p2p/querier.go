@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/routing"
@@ -14,17 +15,49 @@ import (
 	"github.com/celestiaorg/orchestrator-relayer/types"
 )
 
+const (
+	// DefaultMaxConcurrentQueries the default maximum number of confirms queried from the DHT in parallel.
+	DefaultMaxConcurrentQueries = 16
+	// DefaultConfirmQueryTimeout the default time allowed to query a single confirm from the DHT.
+	// When exceeded, the confirm is considered not found.
+	DefaultConfirmQueryTimeout = 10 * time.Second
+)
+
 // Querier used to query the DHT for confirms.
 type Querier struct {
 	QgbDHT *QgbDHT
-	logger tmlog.Logger
+	// PubSub optional gossipsub router used to receive confirms in near real time.
+	// If nil, the confirms are only queried from the DHT.
+	PubSub *QgbPubSub
+	// MaxConcurrentQueries the maximum number of confirms queried from the DHT in parallel.
+	MaxConcurrentQueries int
+	// ConfirmQueryTimeout the time allowed to query a single confirm from the DHT.
+	ConfirmQueryTimeout time.Duration
+	logger              tmlog.Logger
 }
 
 func NewQuerier(qgbDht *QgbDHT, logger tmlog.Logger) *Querier {
 	return &Querier{
-		QgbDHT: qgbDht,
-		logger: logger,
+		QgbDHT:               qgbDht,
+		MaxConcurrentQueries: DefaultMaxConcurrentQueries,
+		ConfirmQueryTimeout:  DefaultConfirmQueryTimeout,
+		logger:               logger,
+	}
+}
+
+// WithPubSub sets the gossipsub router to be used to receive confirms alongside the DHT.
+// The router should be subscribed to the confirms topics using `QgbPubSub.Subscribe`.
+func (q *Querier) WithPubSub(ps *QgbPubSub) {
+	q.PubSub = ps
+}
+
+// notify returns a channel signaling the reception of new gossiped confirms.
+// Returns a nil channel, which blocks forever, if no gossipsub router is set.
+func (q Querier) notify() <-chan struct{} {
+	if q.PubSub == nil {
+		return nil
 	}
+	return q.PubSub.Notify()
 }
 
 // QueryTwoThirdsDataCommitmentConfirms queries two thirds or more of data commitment confirms from the
@@ -52,31 +85,48 @@ func (q Querier) QueryTwoThirdsDataCommitmentConfirms(
 	majThreshHold := previousValset.TwoThirdsThreshold()
 
 	var validConfirms []types.DataCommitmentConfirm
-	queryFunc := func() error {
-		confirms, err := q.QueryDataCommitmentConfirms(ctx, previousValset, nonce, dataRootTupleRoot)
-		if err != nil {
-			return err
-		}
-
+	queryFunc := func(cachedOnly bool) error {
+		confirms := make([]types.DataCommitmentConfirm, 0)
 		currThreshold := uint64(0)
-		for _, dataCommitmentConfirm := range confirms {
+		// adds the confirm power to the current threshold, and returns true when two thirds are reached
+		addConfirm := func(dataCommitmentConfirm types.DataCommitmentConfirm) bool {
 			val, has := vals[dataCommitmentConfirm.EthAddress]
 			if !has {
-				q.logger.Debug(fmt.Sprintf(
-					"dataCommitmentConfirm signer not found in stored validator set: address %s nonce %d",
-					val.EvmAddress,
+				q.logger.Debug(
+					"data commitment confirm signer not found in stored validator set",
+					"nonce",
+					nonce,
+					"evm_address",
+					dataCommitmentConfirm.EthAddress,
+					"valset_nonce",
 					previousValset.Nonce,
-				))
-				continue
+				)
+				return false
 			}
+			confirms = append(confirms, dataCommitmentConfirm)
 			currThreshold += val.Power
+			return currThreshold >= majThreshHold
+		}
+		if cachedOnly {
+			for _, dataCommitmentConfirm := range q.gossipedDataCommitmentConfirms(previousValset, nonce, dataRootTupleRoot) {
+				if addConfirm(dataCommitmentConfirm) {
+					break
+				}
+			}
+		} else {
+			err := q.StreamDataCommitmentConfirms(ctx, previousValset, nonce, dataRootTupleRoot, addConfirm)
+			if err != nil {
+				return err
+			}
 		}
 
 		if currThreshold >= majThreshHold {
 			q.logger.Debug("found enough data commitment confirms to be relayed",
-				"majThreshHold",
+				"nonce",
+				nonce,
+				"majority_threshold",
 				majThreshHold,
-				"currThreshold",
+				"total_power",
 				currThreshold,
 			)
 			validConfirms = confirms
@@ -84,6 +134,8 @@ func (q Querier) QueryTwoThirdsDataCommitmentConfirms(
 		}
 		q.logger.Debug(
 			"found DataCommitmentConfirms",
+			"nonce",
+			nonce,
 			"total_power",
 			currThreshold,
 			"number_of_confirms",
@@ -97,7 +149,7 @@ func (q Querier) QueryTwoThirdsDataCommitmentConfirms(
 	// because the ticker waits for the period to pass to return for the first time, we will execute
 	// the query func here to get the confirms if they're already ready instead of waiting for the first
 	// duration to elapse.
-	err := queryFunc()
+	err := queryFunc(false)
 	if err != nil {
 		return nil, err
 	}
@@ -117,8 +169,18 @@ func (q Querier) QueryTwoThirdsDataCommitmentConfirms(
 				ErrNotEnoughDataCommitmentConfirms,
 				fmt.Sprintf("failure to query for majority validator set confirms: timout %s", timeout),
 			)
+		case <-q.notify():
+			// new confirms were gossiped, only checking the received ones
+			// to avoid querying the DHT on every received confirm.
+			err := queryFunc(true)
+			if err != nil {
+				return nil, err
+			}
+			if len(validConfirms) != 0 {
+				return validConfirms, nil
+			}
 		case <-ticker.C:
-			err := queryFunc()
+			err := queryFunc(false)
 			if err != nil {
 				return nil, err
 			}
@@ -156,32 +218,48 @@ func (q Querier) QueryTwoThirdsValsetConfirms(
 	majThreshHold := previousValset.TwoThirdsThreshold()
 
 	var validConfirms []types.ValsetConfirm
-	queryFunc := func() error {
-		confirms, err := q.QueryValsetConfirms(ctx, valsetNonce, previousValset, signBytes)
-		if err != nil {
-			return err
-		}
-
+	queryFunc := func(cachedOnly bool) error {
+		confirms := make([]types.ValsetConfirm, 0)
 		currThreshold := uint64(0)
-		for _, valsetConfirm := range confirms {
+		// adds the confirm power to the current threshold, and returns true when two thirds are reached
+		addConfirm := func(valsetConfirm types.ValsetConfirm) bool {
 			val, has := vals[valsetConfirm.EthAddress]
 			if !has {
 				q.logger.Debug(
-					fmt.Sprintf(
-						"valSetConfirm signer not found in stored validator set: address %s nonce %d",
-						val.EvmAddress,
-						previousValset.Nonce,
-					))
-				continue
+					"valset confirm signer not found in stored validator set",
+					"nonce",
+					valsetNonce,
+					"evm_address",
+					valsetConfirm.EthAddress,
+					"valset_nonce",
+					previousValset.Nonce,
+				)
+				return false
 			}
+			confirms = append(confirms, valsetConfirm)
 			currThreshold += val.Power
+			return currThreshold >= majThreshHold
+		}
+		if cachedOnly {
+			for _, valsetConfirm := range q.gossipedValsetConfirms(valsetNonce, previousValset, signBytes) {
+				if addConfirm(valsetConfirm) {
+					break
+				}
+			}
+		} else {
+			err := q.StreamValsetConfirms(ctx, valsetNonce, previousValset, signBytes, addConfirm)
+			if err != nil {
+				return err
+			}
 		}
 
 		if currThreshold >= majThreshHold {
 			q.logger.Debug("found enough valset confirms to be relayed",
-				"majThreshHold",
+				"nonce",
+				valsetNonce,
+				"majority_threshold",
 				majThreshHold,
-				"currThreshold",
+				"total_power",
 				currThreshold,
 			)
 			validConfirms = confirms
@@ -204,7 +282,7 @@ func (q Querier) QueryTwoThirdsValsetConfirms(
 	// because the ticker waits for the period to pass to return for the first time, we will execute
 	// the query func here to get the confirms if they're already ready instead of waiting for the first
 	// duration to elapse.
-	err := queryFunc()
+	err := queryFunc(false)
 	if err != nil {
 		return nil, err
 	}
@@ -225,8 +303,18 @@ func (q Querier) QueryTwoThirdsValsetConfirms(
 				ErrNotEnoughValsetConfirms,
 				fmt.Sprintf("failure to query for majority validator set confirms: timout %s", timeout),
 			)
+		case <-q.notify():
+			// new confirms were gossiped, only checking the received ones
+			// to avoid querying the DHT on every received confirm.
+			err := queryFunc(true)
+			if err != nil {
+				return nil, err
+			}
+			if len(validConfirms) != 0 {
+				return validConfirms, nil
+			}
 		case <-ticker.C:
-			err := queryFunc()
+			err := queryFunc(false)
 			if err != nil {
 				return nil, err
 			}
@@ -280,41 +368,265 @@ func (q Querier) QueryDataCommitmentConfirmByEVMAddress(ctx context.Context, non
 
 // QueryDataCommitmentConfirms get all the data commitment confirms in store for a certain nonce.
 // It goes over the valset members and looks if they submitted any confirms.
+// The confirms are returned in the valset members order.
+// The gossiped confirms, if any, are used before falling back to the DHT.
 func (q Querier) QueryDataCommitmentConfirms(ctx context.Context, valset celestiatypes.Valset, nonce uint64, dataRootTupleRoot string) ([]types.DataCommitmentConfirm, error) {
+	found := make([]*types.DataCommitmentConfirm, len(valset.Members))
+	err := queryConfirms(
+		ctx,
+		q,
+		dataCommitmentConfirmKeys(valset, nonce, dataRootTupleRoot),
+		q.gossipedDataCommitmentConfirm,
+		q.QgbDHT.GetDataCommitmentConfirm,
+		func(index int, confirm types.DataCommitmentConfirm) bool {
+			found[index] = &confirm
+			return false
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
 	confirms := make([]types.DataCommitmentConfirm, 0)
-	for _, member := range valset.Members {
-		confirm, err := q.QgbDHT.GetDataCommitmentConfirm(
-			ctx,
-			GetDataCommitmentConfirmKey(nonce, member.EvmAddress, dataRootTupleRoot),
-		)
-		if err == nil {
-			confirms = append(confirms, confirm)
-		} else if errors.Is(err, routing.ErrNotFound) {
-			continue
-		} else {
-			return nil, err
+	for _, confirm := range found {
+		if confirm != nil {
+			confirms = append(confirms, *confirm)
 		}
 	}
 	return confirms, nil
 }
 
+// StreamDataCommitmentConfirms queries, in parallel, the data commitment confirms of the valset members for a
+// certain nonce, and calls `onConfirm` for every found confirm as soon as it is received.
+// The query stops early, without error, when `onConfirm` returns true.
+// `onConfirm` is never called concurrently.
+// The gossiped confirms, if any, are used before falling back to the DHT.
+func (q Querier) StreamDataCommitmentConfirms(
+	ctx context.Context,
+	valset celestiatypes.Valset,
+	nonce uint64,
+	dataRootTupleRoot string,
+	onConfirm func(types.DataCommitmentConfirm) bool,
+) error {
+	return queryConfirms(
+		ctx,
+		q,
+		dataCommitmentConfirmKeys(valset, nonce, dataRootTupleRoot),
+		q.gossipedDataCommitmentConfirm,
+		q.QgbDHT.GetDataCommitmentConfirm,
+		func(_ int, confirm types.DataCommitmentConfirm) bool {
+			return onConfirm(confirm)
+		},
+	)
+}
+
 // QueryValsetConfirms get all the valset confirms in store for a certain nonce.
 // It goes over the specified valset members and looks if they submitted any confirms
 // for the provided nonce.
+// The confirms are returned in the valset members order.
+// The gossiped confirms, if any, are used before falling back to the DHT.
 func (q Querier) QueryValsetConfirms(ctx context.Context, nonce uint64, valset celestiatypes.Valset, signBytes string) ([]types.ValsetConfirm, error) {
+	found := make([]*types.ValsetConfirm, len(valset.Members))
+	err := queryConfirms(
+		ctx,
+		q,
+		valsetConfirmKeys(nonce, valset, signBytes),
+		q.gossipedValsetConfirm,
+		q.QgbDHT.GetValsetConfirm,
+		func(index int, confirm types.ValsetConfirm) bool {
+			found[index] = &confirm
+			return false
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
 	confirms := make([]types.ValsetConfirm, 0)
+	for _, confirm := range found {
+		if confirm != nil {
+			confirms = append(confirms, *confirm)
+		}
+	}
+	return confirms, nil
+}
+
+// StreamValsetConfirms queries, in parallel, the valset confirms of the specified valset members for a
+// certain nonce, and calls `onConfirm` for every found confirm as soon as it is received.
+// The query stops early, without error, when `onConfirm` returns true.
+// `onConfirm` is never called concurrently.
+// The gossiped confirms, if any, are used before falling back to the DHT.
+func (q Querier) StreamValsetConfirms(
+	ctx context.Context,
+	nonce uint64,
+	valset celestiatypes.Valset,
+	signBytes string,
+	onConfirm func(types.ValsetConfirm) bool,
+) error {
+	return queryConfirms(
+		ctx,
+		q,
+		valsetConfirmKeys(nonce, valset, signBytes),
+		q.gossipedValsetConfirm,
+		q.QgbDHT.GetValsetConfirm,
+		func(_ int, confirm types.ValsetConfirm) bool {
+			return onConfirm(confirm)
+		},
+	)
+}
+
+func dataCommitmentConfirmKeys(valset celestiatypes.Valset, nonce uint64, dataRootTupleRoot string) []string {
+	keys := make([]string, len(valset.Members))
+	for i, member := range valset.Members {
+		keys[i] = GetDataCommitmentConfirmKey(nonce, member.EvmAddress, dataRootTupleRoot)
+	}
+	return keys
+}
+
+func valsetConfirmKeys(nonce uint64, valset celestiatypes.Valset, signBytes string) []string {
+	keys := make([]string, len(valset.Members))
+	for i, member := range valset.Members {
+		keys[i] = GetValsetConfirmKey(nonce, member.EvmAddress, signBytes)
+	}
+	return keys
+}
+
+// confirmResult the result of querying a single confirm.
+type confirmResult[T any] struct {
+	index   int
+	confirm T
+	found   bool
+	err     error
+}
+
+// queryConfirms queries the confirms referenced by the provided keys using at most `MaxConcurrentQueries`
+// parallel lookups, each bounded by `ConfirmQueryTimeout`.
+// The cached confirms are looked up using `getCached` before querying the DHT using `get`.
+// `onConfirm` is called, from the caller goroutine, with the index of the key and the confirm every time
+// a confirm is found. The query stops when `onConfirm` returns true.
+// Confirms that are not found, or whose lookup timed out, are skipped.
+func queryConfirms[T any](
+	ctx context.Context,
+	q Querier,
+	keys []string,
+	getCached func(key string) (T, bool),
+	get func(ctx context.Context, key string) (T, error),
+	onConfirm func(index int, confirm T) bool,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	maxConcurrentQueries := q.MaxConcurrentQueries
+	if maxConcurrentQueries <= 0 {
+		maxConcurrentQueries = DefaultMaxConcurrentQueries
+	}
+	queryTimeout := q.ConfirmQueryTimeout
+	if queryTimeout <= 0 {
+		queryTimeout = DefaultConfirmQueryTimeout
+	}
+
+	// buffered so that the lookups never block, even if the caller stopped reading the results
+	results := make(chan confirmResult[T], len(keys))
+	semaphore := make(chan struct{}, maxConcurrentQueries)
+	wg := &sync.WaitGroup{}
+	for index, key := range keys {
+		if confirm, found := getCached(key); found {
+			results <- confirmResult[T]{index: index, confirm: confirm, found: true}
+			continue
+		}
+		wg.Add(1)
+		go func(index int, key string) {
+			defer wg.Done()
+			select {
+			case <-ctx.Done():
+				return
+			case semaphore <- struct{}{}:
+			}
+			defer func() { <-semaphore }()
+
+			keyCtx, keyCancel := context.WithTimeout(ctx, queryTimeout)
+			defer keyCancel()
+			confirm, err := get(keyCtx, key)
+			switch {
+			case err == nil:
+				results <- confirmResult[T]{index: index, confirm: confirm, found: true}
+			case ctx.Err() != nil:
+				// the query was stopped
+				return
+			case errors.Is(err, routing.ErrNotFound):
+				results <- confirmResult[T]{index: index}
+			case errors.Is(err, context.DeadlineExceeded):
+				q.logger.Debug("timed out querying confirm", "key", key, "timeout", queryTimeout.String())
+				results <- confirmResult[T]{index: index}
+			default:
+				results <- confirmResult[T]{index: index, err: err}
+			}
+		}(index, key)
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case result, ok := <-results:
+			if !ok {
+				return nil
+			}
+			if result.err != nil {
+				return result.err
+			}
+			if result.found && onConfirm(result.index, result.confirm) {
+				return nil
+			}
+		}
+	}
+}
+
+// gossipedDataCommitmentConfirm looks for a data commitment confirm in the gossiped confirms.
+func (q Querier) gossipedDataCommitmentConfirm(key string) (types.DataCommitmentConfirm, bool) {
+	if q.PubSub == nil {
+		return types.DataCommitmentConfirm{}, false
+	}
+	return q.PubSub.GetDataCommitmentConfirm(key)
+}
+
+// gossipedValsetConfirm looks for a valset confirm in the gossiped confirms.
+func (q Querier) gossipedValsetConfirm(key string) (types.ValsetConfirm, bool) {
+	if q.PubSub == nil {
+		return types.ValsetConfirm{}, false
+	}
+	return q.PubSub.GetValsetConfirm(key)
+}
+
+// gossipedDataCommitmentConfirms get the data commitment confirms for a certain nonce that were received
+// via gossipsub.
+func (q Querier) gossipedDataCommitmentConfirms(valset celestiatypes.Valset, nonce uint64, dataRootTupleRoot string) []types.DataCommitmentConfirm {
+	confirms := make([]types.DataCommitmentConfirm, 0)
+	if q.PubSub == nil {
+		return confirms
+	}
 	for _, member := range valset.Members {
-		confirm, err := q.QgbDHT.GetValsetConfirm(
-			ctx,
-			GetValsetConfirmKey(nonce, member.EvmAddress, signBytes),
-		)
-		if err == nil {
+		confirm, found := q.PubSub.GetDataCommitmentConfirm(GetDataCommitmentConfirmKey(nonce, member.EvmAddress, dataRootTupleRoot))
+		if found {
 			confirms = append(confirms, confirm)
-		} else if errors.Is(err, routing.ErrNotFound) {
-			continue
-		} else {
-			return nil, err
 		}
 	}
-	return confirms, nil
+	return confirms
+}
+
+// gossipedValsetConfirms get the valset confirms for a certain nonce that were received via gossipsub.
+func (q Querier) gossipedValsetConfirms(nonce uint64, valset celestiatypes.Valset, signBytes string) []types.ValsetConfirm {
+	confirms := make([]types.ValsetConfirm, 0)
+	if q.PubSub == nil {
+		return confirms
+	}
+	for _, member := range valset.Members {
+		confirm, found := q.PubSub.GetValsetConfirm(GetValsetConfirmKey(nonce, member.EvmAddress, signBytes))
+		if found {
+			confirms = append(confirms, confirm)
+		}
+	}
+	return confirms
 }
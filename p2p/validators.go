@@ -14,7 +14,7 @@ type ValsetConfirmValidator struct{}
 
 // Validate runs stateless checks on the provided confirm key and value.
 func (vcv ValsetConfirmValidator) Validate(key string, value []byte) error {
-	namespace, _, evmAddr, signBytes, err := ParseKey(key)
+	namespace, nonce, evmAddr, signBytes, err := ParseKey(key)
 	if err != nil {
 		return err
 	}
@@ -34,6 +34,12 @@ func (vcv ValsetConfirmValidator) Validate(key string, value []byte) error {
 		return err
 	}
 
+	// check if the binary encoded confirm is carrying the same nonce and digest as the key
+	err = validateConfirmRecord(value, nonce, signBytes)
+	if err != nil {
+		return err
+	}
+
 	// check if the evm address in the key is the same as the one in the confirm
 	if !strings.EqualFold(vsc.EthAddress, evmAddr) {
 		return ErrNotTheSameEVMAddress
@@ -91,7 +97,7 @@ type DataCommitmentConfirmValidator struct{}
 
 // Validate runs stateless checks on the provided confirm key and value.
 func (dcv DataCommitmentConfirmValidator) Validate(key string, value []byte) error {
-	namespace, _, evmAddr, dataRootTupleRoot, err := ParseKey(key)
+	namespace, nonce, evmAddr, dataRootTupleRoot, err := ParseKey(key)
 	if err != nil {
 		return err
 	}
@@ -111,6 +117,12 @@ func (dcv DataCommitmentConfirmValidator) Validate(key string, value []byte) err
 		return err
 	}
 
+	// check if the binary encoded confirm is carrying the same nonce and digest as the key
+	err = validateConfirmRecord(value, nonce, dataRootTupleRoot)
+	if err != nil {
+		return err
+	}
+
 	// check if the evm address in the key is the same as the one in the confirm
 	if !strings.EqualFold(dcc.EthAddress, evmAddr) {
 		return ErrNotTheSameEVMAddress
@@ -162,3 +174,23 @@ func (dcv DataCommitmentConfirmValidator) Select(key string, values [][]byte) (i
 	}
 	return 0, ErrNoValidValueFound
 }
+
+// validateConfirmRecord checks if the provided value, if it is a binary confirm record, carries
+// the same nonce and digest as the ones defined in its key.
+// Json encoded confirms do not carry these fields, so they're not checked.
+func validateConfirmRecord(value []byte, nonce uint64, digest string) error {
+	if !types.IsConfirmRecord(value) {
+		return nil
+	}
+	record, err := types.UnmarshalConfirmRecord(value)
+	if err != nil {
+		return err
+	}
+	if record.Nonce != nonce {
+		return ErrConfirmRecordKeyMismatch
+	}
+	if record.Digest != common.HexToHash(digest) {
+		return ErrConfirmRecordKeyMismatch
+	}
+	return nil
+}
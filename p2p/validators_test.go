@@ -48,6 +48,34 @@ func TestValsetConfirmValidate(t *testing.T) {
 			}(),
 			wantErr: false,
 		},
+		{
+			name: "valid binary encoded valset confirm",
+			key:  "/vc/b:" + evmAddress + ":" + signBytes.Hex(),
+			value: func() []byte {
+				signature, err := evm.NewEthereumSignature(signBytes.Bytes(), ks, acc)
+				require.NoError(t, err)
+				vsc, _ := types.EncodeValsetConfirm(types.BinaryConfirmEncoding, 11, signBytes.Hex(), *types.NewValsetConfirm(
+					common.HexToAddress(evmAddress),
+					hex.EncodeToString(signature),
+				))
+				return vsc
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "binary encoded valset confirm with a different nonce than the key",
+			key:  "/vc/b:" + evmAddress + ":" + signBytes.Hex(),
+			value: func() []byte {
+				signature, err := evm.NewEthereumSignature(signBytes.Bytes(), ks, acc)
+				require.NoError(t, err)
+				vsc, _ := types.EncodeValsetConfirm(types.BinaryConfirmEncoding, 12, signBytes.Hex(), *types.NewValsetConfirm(
+					common.HexToAddress(evmAddress),
+					hex.EncodeToString(signature),
+				))
+				return vsc
+			}(),
+			wantErr: true,
+		},
 		{
 			name:    "invalid key format",
 			key:     "/vc/b/0xfA906e15C9Eaf338c4110f0E21983c6b3b2d622b:0x1234000000000000000000000000000000000000000000000000000000001234",
@@ -267,6 +295,30 @@ func TestDataCommitmentConfirmValidate(t *testing.T) {
 			}(),
 			wantErr: false,
 		},
+		{
+			name: "valid binary encoded data commitment confirm",
+			key:  "/dcc/a:" + evmAddress + ":" + dataRootHash.Hex(),
+			value: func() []byte {
+				vsc, _ := types.EncodeDataCommitmentConfirm(types.BinaryConfirmEncoding, nonce, dataRootHash.Hex(), *types.NewDataCommitmentConfirm(
+					hex.EncodeToString(signature),
+					common.HexToAddress(evmAddress),
+				))
+				return vsc
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "binary encoded data commitment confirm with a different digest than the key",
+			key:  "/dcc/a:" + evmAddress + ":" + dataRootHash.Hex(),
+			value: func() []byte {
+				vsc, _ := types.EncodeDataCommitmentConfirm(types.BinaryConfirmEncoding, nonce, common.HexToHash("1234").Hex(), *types.NewDataCommitmentConfirm(
+					hex.EncodeToString(signature),
+					common.HexToAddress(evmAddress),
+				))
+				return vsc
+			}(),
+			wantErr: true,
+		},
 		{
 			name:    "invalid key format",
 			key:     "/dcc/b/0xfA906e15C9Eaf338c4110f0E21983c6b3b2d622b:0x1234000000000000000000000000000000000000000000000000000000001234",
@@ -0,0 +1,111 @@
+package p2p
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// ProtocolVersion a version of the QGB DHT protocol.
+// A node can speak multiple versions at once so that upgrades can be rolled out gradually.
+type ProtocolVersion struct {
+	// Name the version name, e.g. "0.1.0".
+	Name string
+	// MaxConfirmEncoding the newest confirm encoding that the peers speaking this version can decode.
+	// The confirms are translated to, at most, this encoding when put using this version.
+	MaxConfirmEncoding types.ConfirmEncoding
+}
+
+var (
+	// ProtocolVersionV010 the initial protocol version. Its peers only decode the Json confirms.
+	ProtocolVersionV010 = ProtocolVersion{Name: "0.1.0", MaxConfirmEncoding: types.JSONConfirmEncoding}
+	// ProtocolVersionV020 the protocol version whose peers also decode the binary confirms.
+	ProtocolVersionV020 = ProtocolVersion{Name: "0.2.0", MaxConfirmEncoding: types.BinaryConfirmEncoding}
+
+	// SupportedProtocolVersions the protocol versions supported by this binary, from the newest to the oldest.
+	SupportedProtocolVersions = []ProtocolVersion{ProtocolVersionV020, ProtocolVersionV010}
+	// DefaultProtocolVersion the protocol version preferred by default. It stays the oldest version until most of
+	// the network is able to speak the newer ones.
+	DefaultProtocolVersion = ProtocolVersionV010
+)
+
+// Prefix returns the DHT protocol prefix of the version.
+func (v ProtocolVersion) Prefix() protocol.ID {
+	return protocol.ID("/qgb/" + v.Name)
+}
+
+// DHTProtocol returns the DHT protocol ID of the version, as advertised to the other peers.
+func (v ProtocolVersion) DHTProtocol() protocol.ID {
+	return v.Prefix() + "/kad/1.0.0"
+}
+
+// String returns the version name.
+func (v ProtocolVersion) String() string {
+	return v.Name
+}
+
+// confirmEncoding returns the encoding used to put the confirms using this version, which is the provided
+// encoding if the peers speaking this version can decode it. Otherwise, the confirms are translated to the
+// newest encoding they can decode.
+func (v ProtocolVersion) confirmEncoding(encoding types.ConfirmEncoding) types.ConfirmEncoding {
+	if encoding > v.MaxConfirmEncoding {
+		return v.MaxConfirmEncoding
+	}
+	return encoding
+}
+
+// datastore returns the datastore where the records of this version are stored.
+// The records are kept separately for each version, as their encodings can differ.
+// The initial version ones are kept in the root namespace to stay compatible with the existing stores.
+func (v ProtocolVersion) datastore(store ds.Batching) ds.Batching {
+	if v == ProtocolVersionV010 {
+		return store
+	}
+	return namespace.Wrap(store, ds.NewKey("/dht/"+v.Name))
+}
+
+// ParseProtocolVersion returns the supported protocol version having the provided name.
+func ParseProtocolVersion(name string) (ProtocolVersion, error) {
+	for _, version := range SupportedProtocolVersions {
+		if version.Name == strings.TrimPrefix(strings.TrimSpace(name), "v") {
+			return version, nil
+		}
+	}
+	return ProtocolVersion{}, fmt.Errorf("%w: %s", ErrUnsupportedProtocolVersion, name)
+}
+
+// ProtocolVersions the protocol versions spoken by the QGB DHT.
+// The zero value only speaks the DefaultProtocolVersion.
+type ProtocolVersions struct {
+	// Preferred the version used first to put and get the confirms.
+	Preferred ProtocolVersion
+	// Fallbacks the versions also spoken, by order of preference. The confirms are put using all the
+	// versions, and looked up using the fallbacks when not found using the preferred version.
+	Fallbacks []ProtocolVersion
+}
+
+// all returns the preferred version followed by the fallback ones, without duplicates.
+func (pv ProtocolVersions) all() []ProtocolVersion {
+	preferred := pv.Preferred
+	if preferred.Name == "" {
+		preferred = DefaultProtocolVersion
+	}
+	versions := []ProtocolVersion{preferred}
+	for _, fallback := range pv.Fallbacks {
+		duplicate := false
+		for _, version := range versions {
+			if version == fallback {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			versions = append(versions, fallback)
+		}
+	}
+	return versions
+}
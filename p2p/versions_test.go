@@ -0,0 +1,107 @@
+package p2p_test
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/celestiaorg/orchestrator-relayer/evm"
+	"github.com/celestiaorg/orchestrator-relayer/p2p"
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+func TestParseProtocolVersion(t *testing.T) {
+	version, err := p2p.ParseProtocolVersion("0.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, p2p.ProtocolVersionV020, version)
+	version, err = p2p.ParseProtocolVersion("v0.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, p2p.ProtocolVersionV010, version)
+	_, err = p2p.ParseProtocolVersion("9.9.9")
+	assert.ErrorIs(t, err, p2p.ErrUnsupportedProtocolVersion)
+}
+
+func TestVersionedDHT(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newDHT := func(versions p2p.ProtocolVersions, bootstrappers ...*p2p.QgbDHT) *p2p.QgbDHT {
+		h, err := libp2p.New()
+		require.NoError(t, err)
+		infos := make([]peer.AddrInfo, 0)
+		for _, b := range bootstrappers {
+			infos = append(infos, peer.AddrInfo{ID: b.Host().ID(), Addrs: b.Host().Addrs()})
+		}
+		dht, err := p2p.NewVersionedQgbDHT(ctx, h, dssync.MutexWrap(ds.NewMapDatastore()), infos, tmlog.NewNopLogger(), versions)
+		require.NoError(t, err)
+		return dht
+	}
+	// a node upgraded to the 0.2.0 version, still speaking the 0.1.0 one
+	upgraded := newDHT(p2p.ProtocolVersions{
+		Preferred: p2p.ProtocolVersionV020,
+		Fallbacks: []p2p.ProtocolVersion{p2p.ProtocolVersionV010},
+	})
+	defer upgraded.Close()
+	// a node not upgraded yet
+	legacy := newDHT(p2p.ProtocolVersions{}, upgraded)
+	defer legacy.Close()
+	// a node only speaking the 0.2.0 version
+	latest := newDHT(p2p.ProtocolVersions{Preferred: p2p.ProtocolVersionV020}, upgraded)
+	defer latest.Close()
+
+	for _, dht := range []*p2p.QgbDHT{upgraded, legacy, latest} {
+		require.NoError(t, dht.WaitForPeers(ctx, 10*time.Second, time.Millisecond, 1))
+	}
+	assert.Eventually(t, func() bool { return len(upgraded.ListPeers()) == 2 }, 10*time.Second, time.Millisecond)
+	assert.Equal(t, []p2p.ProtocolVersion{p2p.ProtocolVersionV020, p2p.ProtocolVersionV010}, upgraded.Versions())
+
+	// the peers protocol versions are reported
+	version, ok := upgraded.PeerProtocolVersion(legacy.Host().ID())
+	require.True(t, ok)
+	assert.Equal(t, p2p.ProtocolVersionV010, version)
+	version, ok = upgraded.PeerProtocolVersion(latest.Host().ID())
+	require.True(t, ok)
+	assert.Equal(t, p2p.ProtocolVersionV020, version)
+	assert.Equal(t, map[string]int{"0.1.0": 1, "0.2.0": 1}, upgraded.PeerProtocolVersions())
+
+	// the upgraded node puts a binary encoded confirm
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	acc, err := ks.ImportECDSA(privateKey, "123")
+	require.NoError(t, err)
+	require.NoError(t, ks.Unlock(acc, "123"))
+	signBytes := common.HexToHash("0x1234")
+	signature, err := evm.NewEthereumSignature(signBytes.Bytes(), ks, acc)
+	require.NoError(t, err)
+	confirm := *types.NewValsetConfirm(acc.Address, hex.EncodeToString(signature))
+	key := p2p.GetValsetConfirmKey(10, evmAddress, signBytes.Hex())
+	upgraded.ConfirmEncoding = types.BinaryConfirmEncoding
+	require.NoError(t, upgraded.PutValsetConfirm(ctx, key, confirm))
+
+	// the legacy node gets it translated to the Json encoding
+	getCtx, getCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer getCancel()
+	value, err := legacy.GetValue(getCtx, key)
+	require.NoError(t, err)
+	assert.False(t, types.IsConfirmRecord(value))
+	legacyConfirm, err := legacy.GetValsetConfirm(getCtx, key)
+	require.NoError(t, err)
+	assert.Equal(t, confirm, legacyConfirm)
+
+	// the latest node gets it binary encoded
+	value, err = latest.GetValue(getCtx, key)
+	require.NoError(t, err)
+	assert.True(t, types.IsConfirmRecord(value))
+	latestConfirm, err := latest.GetValsetConfirm(getCtx, key)
+	require.NoError(t, err)
+	assert.Equal(t, confirm, latestConfirm)
+}
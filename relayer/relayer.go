@@ -2,13 +2,11 @@ package relayer
 
 import (
 	"context"
-	"fmt"
 	"math/big"
 	"strconv"
 	"time"
 
 	"github.com/ipfs/go-datastore"
-	badger "github.com/ipfs/go-ds-badger2"
 
 	"github.com/pkg/errors"
 
@@ -35,7 +33,7 @@ type Relayer struct {
 	EVMClient      *evm.Client
 	logger         tmlog.Logger
 	Retrier        *helpers.Retrier
-	SignatureStore *badger.Datastore
+	SignatureStore datastore.Batching
 }
 
 func NewRelayer(
@@ -45,7 +43,7 @@ func NewRelayer(
 	evmClient *evm.Client,
 	logger tmlog.Logger,
 	retrier *helpers.Retrier,
-	sigStore *badger.Datastore,
+	sigStore datastore.Batching,
 ) *Relayer {
 	return &Relayer{
 		TmQuerier:      tmQuerier,
@@ -253,11 +251,15 @@ func (r *Relayer) SubmitDataRootTupleRoot(
 		return nil, err
 	}
 
-	r.logger.Info(fmt.Sprintf(
-		"relaying data commitment %d-%d...",
+	r.logger.Info(
+		"relaying data commitment",
+		"nonce",
+		dataCommitment.Nonce,
+		"begin_block",
 		dataCommitment.BeginBlock,
+		"end_block",
 		dataCommitment.EndBlock,
-	))
+	)
 
 	tx, err := r.EVMClient.SubmitDataRootTupleRoot(
 		opts,
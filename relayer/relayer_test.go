@@ -47,3 +47,42 @@ func (s *RelayerTestSuite) TestProcessAttestation() {
 	require.NoError(t, err)
 	assert.True(t, has)
 }
+
+func (s *RelayerTestSuite) TestVerifyDataRootInclusionProof() {
+	t := s.T()
+	_, err := s.Node.CelestiaNetwork.WaitForHeightWithTimeout(400, 30*time.Second)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	// deploying a new contract so that the relayed nonce doesn't depend on the other tests
+	latestValset, err := s.Orchestrator.AppQuerier.QueryLatestValset(ctx)
+	require.NoError(t, err)
+	_, tx, _, err := s.Relayer.EVMClient.DeployQGBContract(s.Node.EVMChain.Auth, s.Node.EVMChain.Backend, *latestValset, latestValset.Nonce, true)
+	require.NoError(t, err)
+	_, err = s.Relayer.EVMClient.WaitForTransaction(ctx, s.Node.EVMChain.Backend, tx)
+	require.NoError(t, err)
+
+	att := types.NewDataCommitment(latestValset.Nonce+1, 10, 100, time.Now())
+	commitment, err := s.Orchestrator.TmQuerier.QueryCommitment(ctx, att.BeginBlock, att.EndBlock)
+	require.NoError(t, err)
+	dataRootTupleRoot := qgbtypes.DataCommitmentTupleRootSignBytes(big.NewInt(int64(att.Nonce)), commitment)
+	err = s.Orchestrator.ProcessDataCommitmentEvent(ctx, *att, dataRootTupleRoot)
+	require.NoError(t, err)
+	tx, err = s.Relayer.ProcessAttestation(ctx, s.Node.EVMChain.Auth, att)
+	require.NoError(t, err)
+	receipt, err := s.Relayer.EVMClient.WaitForTransaction(ctx, s.Node.EVMChain.Backend, tx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), receipt.Status)
+
+	// check if a data root inclusion proof to the relayed commitment is verified by the contract
+	height := uint64(50)
+	dataRoot, err := s.Relayer.TmQuerier.QueryDataRoot(ctx, height)
+	require.NoError(t, err)
+	proof, err := s.Relayer.TmQuerier.QueryDataRootInclusionProof(ctx, height, att.BeginBlock, att.EndBlock)
+	require.NoError(t, err)
+	inclusionProof, err := qgbtypes.NewDataRootTupleInclusionProof(att.Nonce, height, dataRoot, *proof)
+	require.NoError(t, err)
+	verified, err := s.Relayer.EVMClient.VerifyAttestation(nil, att.Nonce, inclusionProof.WrapperTuple(), inclusionProof.WrapperProof())
+	require.NoError(t, err)
+	assert.True(t, verified)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/tendermint/tendermint/crypto/merkle"
 	"github.com/tendermint/tendermint/libs/bytes"
 	tmlog "github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/rpc/client"
@@ -64,6 +65,26 @@ func (tq *TmQuerier) QueryCommitment(ctx context.Context, beginBlock uint64, end
 	return dcResp.DataCommitment, nil
 }
 
+// QueryDataRootInclusionProof queries the inclusion proof of the data root tuple of the provided height
+// to the data root tuple root of the [beginBlock, endBlock) range.
+func (tq *TmQuerier) QueryDataRootInclusionProof(ctx context.Context, height uint64, beginBlock uint64, endBlock uint64) (*merkle.Proof, error) {
+	proofResp, err := tq.clientConn.DataRootInclusionProof(ctx, height, beginBlock, endBlock)
+	if err != nil {
+		return nil, err
+	}
+	return &proofResp.Proof, nil
+}
+
+// QueryDataRoot queries the data root of the block at the provided height.
+func (tq *TmQuerier) QueryDataRoot(ctx context.Context, height uint64) (bytes.HexBytes, error) {
+	h := int64(height)
+	blockResp, err := tq.clientConn.Block(ctx, &h)
+	if err != nil {
+		return nil, err
+	}
+	return blockResp.Block.DataHash, nil
+}
+
 func (tq *TmQuerier) QueryHeight(ctx context.Context) (int64, error) {
 	status, err := tq.clientConn.Status(ctx)
 	if err != nil {
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	badger "github.com/ipfs/go-ds-badger2"
+	leveldb "github.com/ipfs/go-ds-leveldb"
+)
+
+// Backend the key-value store used for the data and signature stores.
+type Backend string
+
+const (
+	// BackendBadger stores the data using badger. This is the default backend.
+	BackendBadger Backend = "badger"
+	// BackendLevelDB stores the data using leveldb.
+	BackendLevelDB Backend = "leveldb"
+	// BackendPebble stores the data using pebble.
+	BackendPebble Backend = "pebble"
+	// BackendMemory keeps the data in memory. The data is lost when the store is closed, so it's
+	// only intended for ephemeral relayers and tests.
+	BackendMemory Backend = "memory"
+)
+
+// DefaultBackend the backend used when none is specified.
+const DefaultBackend = BackendBadger
+
+// Backends the supported backends.
+var Backends = []Backend{BackendBadger, BackendLevelDB, BackendPebble, BackendMemory}
+
+// ParseBackend parses the provided backend name.
+// Returns DefaultBackend if the name is empty.
+func ParseBackend(name string) (Backend, error) {
+	if name == "" {
+		return DefaultBackend, nil
+	}
+	for _, backend := range Backends {
+		if string(backend) == strings.ToLower(name) {
+			return backend, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s. supported backends: %v", ErrUnknownBackend, name, Backends)
+}
+
+// IsPersistent returns true if the backend stores the data on disk.
+func (b Backend) IsPersistent() bool {
+	return b != BackendMemory
+}
+
+// openDatastore opens the datastore under the provided path using the backend.
+// The badger options are only used by the badger backend.
+// The read-only datastores are opened without writing anything to disk, and should already exist.
+func openDatastore(backend Backend, path string, badgerOptions *badger.Options, readOnly bool) (datastore.Batching, error) {
+	switch backend {
+	case BackendBadger:
+		if badgerOptions == nil {
+			return nil, fmt.Errorf("badger store options needed to open the store")
+		}
+		if readOnly {
+			readOnlyOptions := *badgerOptions
+			readOnlyOptions.Options = readOnlyOptions.Options.WithReadOnly(true)
+			badgerOptions = &readOnlyOptions
+		}
+		return badger.NewDatastore(path, badgerOptions)
+	case BackendLevelDB:
+		return leveldb.NewDatastore(path, &leveldb.Options{ReadOnly: readOnly})
+	case BackendPebble:
+		if readOnly {
+			return openPebbleDatastore(path, &pebble.Options{ReadOnly: true})
+		}
+		return NewPebbleDatastore(path)
+	case BackendMemory:
+		return dssync.MutexWrap(datastore.NewMapDatastore()), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, backend)
+	}
+}
+
+// DetectBackend returns the backend that wrote the datastore under the provided path.
+// Returns false if the path doesn't contain a datastore yet.
+func DetectBackend(path string) (Backend, bool, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	hasCurrent := false
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		// pebble also writes a CURRENT file, but it's the only one writing options files
+		case strings.HasPrefix(name, "OPTIONS-"):
+			return BackendPebble, true, nil
+		case name == "MANIFEST" || name == "KEYREGISTRY" || strings.HasSuffix(name, ".vlog"):
+			return BackendBadger, true, nil
+		case name == "CURRENT":
+			hasCurrent = true
+		}
+	}
+	if hasCurrent {
+		return BackendLevelDB, true, nil
+	}
+	return "", false, nil
+}
+
+// checkBackend checks that the datastore under the provided path, if any, was written by the backend.
+func checkBackend(backend Backend, path string) error {
+	if !backend.IsPersistent() {
+		return nil
+	}
+	detected, ok, err := DetectBackend(path)
+	if err != nil {
+		return err
+	}
+	if ok && detected != backend {
+		return fmt.Errorf("%w: %s written by %s, configured backend %s", ErrBackendMismatch, path, detected, backend)
+	}
+	return nil
+}
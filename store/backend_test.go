@@ -0,0 +1,175 @@
+package store_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/store"
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+func TestBackends(t *testing.T) {
+	for _, backend := range store.Backends {
+		t.Run(string(backend), func(t *testing.T) {
+			ctx := context.Background()
+			logger := tmlog.NewNopLogger()
+			path := t.TempDir()
+
+			initOptions := store.InitOptions{
+				NeedDataStore:      true,
+				NeedSignatureStore: true,
+				Backend:            backend,
+			}
+			options := store.OpenOptions{
+				HasDataStore:      true,
+				Backend:           backend,
+				BadgerOptions:     store.DefaultBadgerOptions(path),
+				HasSignatureStore: true,
+			}
+			require.NoError(t, store.Init(logger, path, initOptions))
+			assert.True(t, store.IsInit(logger, path, initOptions))
+			s, err := store.OpenStore(logger, path, options)
+			require.NoError(t, err)
+
+			// the store is locked while the datastores are opened
+			_, err = store.OpenStore(logger, path, options)
+			assert.ErrorIs(t, err, store.ErrOpened)
+
+			for _, ds := range []datastore.Batching{s.DataStore, s.SignatureStore} {
+				batch, err := ds.Batch(ctx)
+				require.NoError(t, err)
+				require.NoError(t, batch.Put(ctx, datastore.NewKey("/vc/1"), []byte("1")))
+				require.NoError(t, batch.Put(ctx, datastore.NewKey("/vc/2"), []byte("2")))
+				require.NoError(t, batch.Put(ctx, datastore.NewKey("/dcc/1"), []byte("3")))
+				require.NoError(t, batch.Commit(ctx))
+
+				value, err := ds.Get(ctx, datastore.NewKey("/vc/2"))
+				require.NoError(t, err)
+				assert.Equal(t, []byte("2"), value)
+				_, err = ds.Get(ctx, datastore.NewKey("/vc/3"))
+				assert.ErrorIs(t, err, datastore.ErrNotFound)
+
+				results, err := ds.Query(ctx, query.Query{Prefix: "/vc", Orders: []query.Order{query.OrderByKey{}}})
+				require.NoError(t, err)
+				entries, err := results.Rest()
+				require.NoError(t, err)
+				require.Len(t, entries, 2)
+				assert.Equal(t, "/vc/1", entries[0].Key)
+				assert.Equal(t, "/vc/2", entries[1].Key)
+
+				require.NoError(t, ds.Delete(ctx, datastore.NewKey("/vc/1")))
+				has, err := ds.Has(ctx, datastore.NewKey("/vc/1"))
+				require.NoError(t, err)
+				assert.False(t, has)
+			}
+			require.NoError(t, s.Close(logger, options))
+
+			// the data is kept by the persistent backends
+			s, err = store.OpenStore(logger, path, options)
+			require.NoError(t, err)
+			defer s.Close(logger, options) //nolint: errcheck
+			has, err := s.DataStore.Has(ctx, datastore.NewKey("/vc/2"))
+			require.NoError(t, err)
+			assert.Equal(t, backend.IsPersistent(), has)
+		})
+	}
+}
+
+func TestBackendsReadOnly(t *testing.T) {
+	for _, backend := range []store.Backend{store.BackendBadger, store.BackendLevelDB, store.BackendPebble} {
+		t.Run(string(backend), func(t *testing.T) {
+			ctx := context.Background()
+			logger := tmlog.NewNopLogger()
+			path := t.TempDir()
+
+			options := store.OpenOptions{
+				HasDataStore:  true,
+				Backend:       backend,
+				BadgerOptions: store.DefaultBadgerOptions(path),
+			}
+			require.NoError(t, store.Init(logger, path, store.InitOptions{NeedDataStore: true, Backend: backend}))
+			s, err := store.OpenStore(logger, path, options)
+			require.NoError(t, err)
+			require.NoError(t, s.DataStore.Put(ctx, datastore.NewKey("/vc/1"), []byte("1")))
+			require.NoError(t, s.Close(logger, options))
+			files := storeFiles(t, filepath.Join(path, store.DataPath))
+
+			// the read-only store is readable, but not writable
+			readOnlyOptions := options
+			readOnlyOptions.ReadOnly = true
+			s, err = store.OpenStore(logger, path, readOnlyOptions)
+			require.NoError(t, err)
+			value, err := s.DataStore.Get(ctx, datastore.NewKey("/vc/1"))
+			require.NoError(t, err)
+			assert.Equal(t, []byte("1"), value)
+			assert.Error(t, s.DataStore.Put(ctx, datastore.NewKey("/vc/2"), []byte("2")))
+			require.NoError(t, s.Close(logger, readOnlyOptions))
+
+			// nothing was written to disk
+			assert.Equal(t, files, storeFiles(t, filepath.Join(path, store.DataPath)))
+		})
+	}
+}
+
+// storeFiles returns the size and modification time of the files under the provided directory, except
+// the lock files, which are taken even when opening read-only.
+func storeFiles(t *testing.T, dir string) map[string]string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, entry := range entries {
+		if entry.Name() == "LOCK" {
+			continue
+		}
+		info, err := entry.Info()
+		require.NoError(t, err)
+		files[entry.Name()] = fmt.Sprintf("%d %s", info.Size(), info.ModTime())
+	}
+	return files
+}
+
+func TestBackendMismatch(t *testing.T) {
+	logger := tmlog.NewNopLogger()
+	path := t.TempDir()
+
+	initOptions := store.InitOptions{NeedDataStore: true}
+	options := store.OpenOptions{
+		HasDataStore:  true,
+		BadgerOptions: store.DefaultBadgerOptions(path),
+	}
+	require.NoError(t, store.Init(logger, path, initOptions))
+	s, err := store.OpenStore(logger, path, options)
+	require.NoError(t, err)
+	require.NoError(t, s.Close(logger, options))
+
+	// the badger store can't be opened using another backend
+	options.Backend = store.BackendPebble
+	_, err = store.OpenStore(logger, path, options)
+	assert.ErrorIs(t, err, store.ErrBackendMismatch)
+
+	// the lock is released on failure
+	options.Backend = store.BackendBadger
+	s, err = store.OpenStore(logger, path, options)
+	require.NoError(t, err)
+	require.NoError(t, s.Close(logger, options))
+}
+
+func TestParseBackend(t *testing.T) {
+	backend, err := store.ParseBackend("")
+	require.NoError(t, err)
+	assert.Equal(t, store.DefaultBackend, backend)
+
+	backend, err = store.ParseBackend("LevelDB")
+	require.NoError(t, err)
+	assert.Equal(t, store.BackendLevelDB, backend)
+
+	_, err = store.ParseBackend("rocksdb")
+	assert.ErrorIs(t, err, store.ErrUnknownBackend)
+}
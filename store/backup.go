@@ -0,0 +1,419 @@
+package store
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/celestiaorg/orchestrator-relayer/store/fslock"
+	badger2 "github.com/dgraph-io/badger/v2"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	// dataBackupEntry the backup archive entry containing the data store badger backup.
+	dataBackupEntry = "data.backup"
+	// signatureBackupEntry the backup archive entry containing the signature store badger backup.
+	signatureBackupEntry = "signatures.backup"
+	// keystoreBackupDir the backup archive directory containing the keystores.
+	keystoreBackupDir = "keystore"
+	// badgerLockFile the lock file taken by badger on its directory.
+	badgerLockFile = "LOCK"
+	// snapshotAttempts the number of times copying a badger store is attempted, as the running
+	// process can compact it while it's being copied.
+	snapshotAttempts = 3
+	// loadMaxPendingWrites the number of pending writes when loading a badger backup.
+	loadMaxPendingWrites = 256
+)
+
+// Backup writes a snapshot of the store under the provided path to the provided writer, as a gzipped
+// tar archive. The archive contains the metadata, the keystores, and a badger backup of the data
+// and signature stores if they exist. Only the stores using the badger backend can be backed up.
+// The store can be in use by a running process: the badger stores are copied then backed up from
+// the copy, which badger recovers to a consistent state.
+func Backup(logger tmlog.Logger, path string, w io.Writer) error {
+	path, err := storePath(path)
+	if err != nil {
+		return err
+	}
+	if !Exists(path) {
+		return ErrNotInited
+	}
+
+	tmpDir, err := os.MkdirTemp("", "qgb-backup-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	// the metadata is written first so that it's checked before restoring anything
+	if Exists(metadataPath(path)) {
+		err = addFileToArchive(tw, metadataPath(path), MetadataPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, db := range []struct {
+		path  string
+		entry string
+	}{
+		{path: dataPath(path), entry: dataBackupEntry},
+		{path: signaturePath(path), entry: signatureBackupEntry},
+	} {
+		if !Exists(db.path) {
+			continue
+		}
+		// only the badger stores can be backed up
+		err = checkBackend(BackendBadger, db.path)
+		if err != nil {
+			return err
+		}
+		logger.Info("backing up badger store", "path", db.path)
+		backupFile := filepath.Join(tmpDir, db.entry)
+		err = backupBadger(db.path, filepath.Join(tmpDir, db.entry+".snapshot"), backupFile)
+		if err != nil {
+			return fmt.Errorf("couldn't backup badger store %s: %w", db.path, err)
+		}
+		err = addFileToArchive(tw, backupFile, db.entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	keystorePath := filepath.Join(path, keystoreBackupDir)
+	if Exists(keystorePath) {
+		logger.Info("backing up keystores", "path", keystorePath)
+		err = filepath.WalkDir(keystorePath, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(path, file)
+			if err != nil {
+				return err
+			}
+			// the directories are archived too so that the empty keystores are restored
+			return addFileToArchive(tw, file, filepath.ToSlash(rel))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// backupBadger writes a badger backup of the store under the provided path to the backup file.
+// The store is copied to the snapshot path first, so that it can be read while being used.
+func backupBadger(path string, snapshotPath string, backupFile string) error {
+	var db *badger2.DB
+	var err error
+	for attempt := 0; attempt < snapshotAttempts; attempt++ {
+		err = os.RemoveAll(snapshotPath)
+		if err != nil {
+			return err
+		}
+		err = copyBadgerDir(path, snapshotPath)
+		if err != nil {
+			continue
+		}
+		db, err = badger2.Open(badger2.DefaultOptions(snapshotPath).WithTruncate(true).WithLogger(nil))
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(backupFile)
+	if err != nil {
+		db.Close()
+		return err
+	}
+	_, err = db.Backup(out, 0)
+	if err != nil {
+		out.Close()
+		db.Close()
+		return err
+	}
+	err = out.Close()
+	if err != nil {
+		db.Close()
+		return err
+	}
+	return db.Close()
+}
+
+// copyBadgerDir copies the badger store files to the destination directory, except for the lock file.
+// The manifest is copied first so that the tables it references are copied after it.
+func copyBadgerDir(path string, destination string) error {
+	err := os.MkdirAll(destination, perms)
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return err
+	}
+	names := []string{badger2.ManifestFilename}
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == badgerLockFile || entry.Name() == badger2.ManifestFilename {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	for _, name := range names {
+		err = copyFile(filepath.Join(path, name), filepath.Join(destination, name), 0o600)
+		if err != nil && !(errors.Is(err, os.ErrNotExist) && name == badger2.ManifestFilename) {
+			return err
+		}
+	}
+	return nil
+}
+
+// addFileToArchive adds the provided file to the archive under the provided name.
+func addFileToArchive(tw *tar.Writer, file string, name string) error {
+	in, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+	}
+	err = tw.WriteHeader(header)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return nil
+	}
+	_, err = io.Copy(tw, in)
+	return err
+}
+
+// Restore restores the backup archive, written by Backup, into a new store under the provided path.
+// The backup is restored into a temporary directory, then moved into place once complete, so that
+// a failed restore doesn't leave a partial store behind.
+// Returns ErrStoreExists if a store already exists under the path, and ErrNewerStoreVersion if
+// the backup was taken from a store written by a newer binary.
+func Restore(logger tmlog.Logger, path string, r io.Reader) error {
+	path, err := storePath(path)
+	if err != nil {
+		return err
+	}
+	if Exists(metadataPath(path)) || hasLegacyLayout(path) {
+		return ErrStoreExists
+	}
+
+	err = initRoot(path)
+	if err != nil {
+		return err
+	}
+	flock, err := fslock.Lock(lockPath(path))
+	if err != nil {
+		if errors.Is(err, fslock.ErrLocked) {
+			return ErrOpened
+		}
+		return err
+	}
+	defer flock.Unlock() //nolint: errcheck
+
+	// the temporary directory is created under the store path so that it can be renamed into place
+	tmpPath, err := os.MkdirTemp(path, ".restore-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpPath)
+
+	hasMetadata, err := restoreArchive(logger, tmpPath, r)
+	if err != nil {
+		return err
+	}
+	err = moveRestoredEntries(tmpPath, path)
+	if err != nil {
+		return err
+	}
+
+	// the backups taken from legacy stores are migrated when opened
+	if !hasMetadata {
+		logger.Info("the backup has no metadata, the store will be migrated when opened", "path", path)
+	}
+	logger.Info("store restored", "path", path)
+	return nil
+}
+
+// restoreArchive extracts the backup archive into the provided path.
+// Returns true if the backup contains the store metadata.
+func restoreArchive(logger tmlog.Logger, path string, r io.Reader) (bool, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", ErrInvalidBackup, err.Error())
+	}
+	tr := tar.NewReader(gr)
+	hasMetadata := false
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return false, fmt.Errorf("%w: %s", ErrInvalidBackup, err.Error())
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeDir {
+			continue
+		}
+		switch name := strings.TrimSuffix(header.Name, "/"); {
+		case header.Typeflag == tar.TypeDir:
+			if !isKeystoreEntry(name) {
+				return false, fmt.Errorf("%w: unexpected directory %s", ErrInvalidBackup, name)
+			}
+			err = initDir(filepath.Join(path, filepath.FromSlash(name)))
+			if err != nil {
+				return false, err
+			}
+		case name == MetadataPath:
+			var md Metadata
+			err = json.NewDecoder(tr).Decode(&md)
+			if err != nil {
+				return false, fmt.Errorf("%w: %s", ErrInvalidBackup, err.Error())
+			}
+			if md.Version > CurrentVersion {
+				return false, fmt.Errorf("%w: backup version %d, supported version %d", ErrNewerStoreVersion, md.Version, CurrentVersion)
+			}
+			err = writeMetadata(path, md)
+			if err != nil {
+				return false, err
+			}
+			hasMetadata = true
+		case name == dataBackupEntry:
+			logger.Info("restoring data store", "path", dataPath(path))
+			err = restoreBadger(dataPath(path), tr)
+			if err != nil {
+				return false, err
+			}
+		case name == signatureBackupEntry:
+			logger.Info("restoring signature store", "path", signaturePath(path))
+			err = restoreBadger(signaturePath(path), tr)
+			if err != nil {
+				return false, err
+			}
+		case isKeystoreEntry(name):
+			err = restoreFile(filepath.Join(path, filepath.FromSlash(name)), tr, header.FileInfo().Mode().Perm())
+			if err != nil {
+				return false, err
+			}
+		default:
+			return false, fmt.Errorf("%w: unexpected entry %s", ErrInvalidBackup, name)
+		}
+	}
+	return hasMetadata, nil
+}
+
+// moveRestoredEntries moves the restored store entries into the store path. The directories already
+// existing under the store path, e.g. the keystore one containing a swarm key, are merged.
+// The metadata is moved last so that the store is only considered as initialized once complete.
+func moveRestoredEntries(from string, to string) error {
+	err := moveEntries(from, to, MetadataPath)
+	if err != nil {
+		return err
+	}
+	if Exists(metadataPath(from)) {
+		return os.Rename(metadataPath(from), metadataPath(to))
+	}
+	return nil
+}
+
+// moveEntries moves the entries of the from directory into the to directory, except the skipped one.
+// Returns ErrStoreExists if a file already exists under the to directory.
+func moveEntries(from string, to string, skip string) error {
+	entries, err := os.ReadDir(from)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() == skip {
+			continue
+		}
+		source := filepath.Join(from, entry.Name())
+		target := filepath.Join(to, entry.Name())
+		info, err := os.Stat(target)
+		switch {
+		case err == nil && entry.IsDir() && info.IsDir():
+			err = moveEntries(source, target, "")
+		case err == nil:
+			err = fmt.Errorf("%w: %s already exists", ErrStoreExists, target)
+		case os.IsNotExist(err):
+			err = os.Rename(source, target)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isKeystoreEntry returns true if the backup archive entry is part of the keystores.
+func isKeystoreEntry(name string) bool {
+	return (name == keystoreBackupDir || strings.HasPrefix(name, keystoreBackupDir+"/")) && !strings.Contains(name, "..")
+}
+
+// restoreBadger loads the provided badger backup into a new badger store under the provided path.
+func restoreBadger(path string, r io.Reader) error {
+	err := initDir(path)
+	if err != nil {
+		return err
+	}
+	db, err := badger2.Open(DefaultBadgerOptions(path).Options.WithLogger(nil))
+	if err != nil {
+		return err
+	}
+	err = db.Load(r, loadMaxPendingWrites)
+	if err != nil {
+		db.Close()
+		return fmt.Errorf("%w: %s", ErrInvalidBackup, err.Error())
+	}
+	return db.Close()
+}
+
+// restoreFile writes the provided contents to the file, creating its parent directories.
+func restoreFile(file string, r io.Reader, perm fs.FileMode) error {
+	err := initDir(filepath.Dir(file))
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, r)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,216 @@
+package store_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/store"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ipfs/go-datastore"
+	badger "github.com/ipfs/go-ds-badger2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+	logger := tmlog.NewNopLogger()
+	path := t.TempDir()
+
+	initOptions := store.InitOptions{
+		NeedDataStore:      true,
+		NeedSignatureStore: true,
+		NeedEVMKeyStore:    true,
+		NeedP2PKeyStore:    true,
+	}
+	options := store.OpenOptions{
+		HasDataStore:      true,
+		BadgerOptions:     store.DefaultBadgerOptions(path),
+		HasSignatureStore: true,
+		HasEVMKeyStore:    true,
+		HasP2PKeyStore:    true,
+	}
+	require.NoError(t, store.Init(logger, path, initOptions))
+	s, err := store.OpenStore(logger, path, options)
+	require.NoError(t, err)
+
+	dataKey := datastore.NewKey("/data")
+	signatureKey := datastore.NewKey("/vc/1:0x1234:0x5678")
+	require.NoError(t, s.DataStore.Put(ctx, dataKey, []byte("data")))
+	require.NoError(t, s.SignatureStore.Put(ctx, signatureKey, []byte("signature")))
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	account, err := s.EVMKeyStore.ImportECDSA(privateKey, "123")
+	require.NoError(t, err)
+
+	// the backup is taken while the store is in use
+	var archive bytes.Buffer
+	err = store.Backup(logger, path, &archive)
+	require.NoError(t, err)
+	require.NoError(t, s.Close(logger, options))
+
+	// the backup can't be restored over an existing store
+	err = store.Restore(logger, path, bytes.NewReader(archive.Bytes()))
+	assert.ErrorIs(t, err, store.ErrStoreExists)
+
+	restorePath := t.TempDir()
+	err = store.Restore(logger, restorePath, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	assert.True(t, store.IsInit(logger, restorePath, initOptions))
+
+	md, err := store.ReadMetadata(restorePath)
+	require.NoError(t, err)
+	assert.Equal(t, store.CurrentVersion, md.Version)
+
+	restoredOptions := options
+	restoredOptions.BadgerOptions = store.DefaultBadgerOptions(restorePath)
+	restored, err := store.OpenStore(logger, restorePath, restoredOptions)
+	require.NoError(t, err)
+	defer restored.Close(logger, restoredOptions) //nolint: errcheck
+
+	value, err := restored.DataStore.Get(ctx, dataKey)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), value)
+	value, err = restored.SignatureStore.Get(ctx, signatureKey)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("signature"), value)
+	assert.True(t, restored.EVMKeyStore.HasAddress(account.Address))
+}
+
+// TestBackupWhileCompacting checks that the keys written before the backup are restored when the store
+// is written to and compacted by the process using it during the backup.
+func TestBackupWhileCompacting(t *testing.T) {
+	ctx := context.Background()
+	logger := tmlog.NewNopLogger()
+	path := t.TempDir()
+
+	initOptions := store.InitOptions{NeedDataStore: true}
+	// small tables and value log files so that the store is flushed and compacted often
+	badgerOptions := store.DefaultBadgerOptions(path)
+	badgerOptions.Options = badgerOptions.Options.
+		WithMaxTableSize(1 << 16).
+		WithLevelOneSize(1 << 18).
+		WithNumLevelZeroTables(1).
+		WithNumLevelZeroTablesStall(2).
+		WithValueLogFileSize(1 << 20).
+		WithValueThreshold(64)
+	options := store.OpenOptions{HasDataStore: true, BadgerOptions: badgerOptions}
+	require.NoError(t, store.Init(logger, path, initOptions))
+	s, err := store.OpenStore(logger, path, options)
+	require.NoError(t, err)
+	db := s.DataStore.(*badger.Datastore).DB
+
+	value := bytes.Repeat([]byte("v"), 1024)
+	acknowledged := 2000
+	for i := 0; i < acknowledged; i++ {
+		require.NoError(t, s.DataStore.Put(ctx, datastore.NewKey(fmt.Sprintf("/acknowledged/%d", i)), value))
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	// writing new keys and overwriting the acknowledged ones, to leave stale values to collect
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			assert.NoError(t, s.DataStore.Put(ctx, datastore.NewKey(fmt.Sprintf("/new/%d", i)), value))
+			assert.NoError(t, s.DataStore.Put(ctx, datastore.NewKey(fmt.Sprintf("/acknowledged/%d", i%acknowledged)), value))
+		}
+	}()
+	// compacting the tables and collecting the value log
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			assert.NoError(t, db.Flatten(1))
+			_ = db.RunValueLogGC(0.1)
+		}
+	}()
+
+	var archives []bytes.Buffer
+	for i := 0; i < 3; i++ {
+		var archive bytes.Buffer
+		require.NoError(t, store.Backup(logger, path, &archive))
+		archives = append(archives, archive)
+	}
+	close(done)
+	wg.Wait()
+	require.NoError(t, s.Close(logger, options))
+
+	for _, archive := range archives {
+		restorePath := t.TempDir()
+		require.NoError(t, store.Restore(logger, restorePath, bytes.NewReader(archive.Bytes())))
+		restoredOptions := store.OpenOptions{HasDataStore: true, BadgerOptions: store.DefaultBadgerOptions(restorePath)}
+		restored, err := store.OpenStore(logger, restorePath, restoredOptions)
+		require.NoError(t, err)
+		for i := 0; i < acknowledged; i++ {
+			restoredValue, err := restored.DataStore.Get(ctx, datastore.NewKey(fmt.Sprintf("/acknowledged/%d", i)))
+			require.NoError(t, err, i)
+			require.Equal(t, value, restoredValue)
+		}
+		require.NoError(t, restored.Close(logger, restoredOptions))
+	}
+}
+
+func TestRestoreInvalidBackup(t *testing.T) {
+	logger := tmlog.NewNopLogger()
+	err := store.Restore(logger, t.TempDir(), bytes.NewReader([]byte("not an archive")))
+	assert.ErrorIs(t, err, store.ErrInvalidBackup)
+}
+
+func TestRestoreFailureLeavesNoPartialStore(t *testing.T) {
+	ctx := context.Background()
+	logger := tmlog.NewNopLogger()
+	path := t.TempDir()
+
+	initOptions := store.InitOptions{NeedDataStore: true, NeedSignatureStore: true}
+	options := store.OpenOptions{
+		HasDataStore:      true,
+		BadgerOptions:     store.DefaultBadgerOptions(path),
+		HasSignatureStore: true,
+	}
+	require.NoError(t, store.Init(logger, path, initOptions))
+	s, err := store.OpenStore(logger, path, options)
+	require.NoError(t, err)
+	dataKey := datastore.NewKey("/data")
+	require.NoError(t, s.DataStore.Put(ctx, dataKey, []byte("data")))
+	var archive bytes.Buffer
+	require.NoError(t, store.Backup(logger, path, &archive))
+	require.NoError(t, s.Close(logger, options))
+
+	// restoring a truncated backup fails after some entries were restored
+	restorePath := t.TempDir()
+	truncated := archive.Bytes()[:archive.Len()*3/4]
+	err = store.Restore(logger, restorePath, bytes.NewReader(truncated))
+	require.Error(t, err)
+	assert.False(t, store.IsInit(logger, restorePath, initOptions))
+	for _, entry := range []string{store.MetadataPath, store.DataPath, store.SignaturePath} {
+		assert.NoFileExists(t, filepath.Join(restorePath, entry))
+		assert.NoDirExists(t, filepath.Join(restorePath, entry))
+	}
+
+	// the restore can be retried
+	err = store.Restore(logger, restorePath, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	assert.True(t, store.IsInit(logger, restorePath, initOptions))
+	entries, err := os.ReadDir(restorePath)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), ".restore-")
+	}
+}
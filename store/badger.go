@@ -1,16 +1,46 @@
 package store
 
 import (
+	"time"
+
 	badger2 "github.com/dgraph-io/badger/v2"
 	badger "github.com/ipfs/go-ds-badger2"
 )
 
+const (
+	// DefaultGCInterval the default interval between two value log garbage collections.
+	DefaultGCInterval = time.Hour
+	// DefaultGCDiscardRatio the default fraction of a value log file that should be stale
+	// for it to be rewritten by the garbage collection.
+	DefaultGCDiscardRatio = 0.5
+	// DefaultGCSleep the default pause between two value log files rewrites during a garbage collection.
+	DefaultGCSleep = 10 * time.Second
+)
+
+// GCConfig the badger value log garbage collection config.
+type GCConfig struct {
+	// Interval the interval between two garbage collections. Zero disables the garbage collection.
+	Interval time.Duration
+	// DiscardRatio the fraction of a value log file that should be stale for it to be rewritten.
+	DiscardRatio float64
+	// Sleep the pause between two value log files rewrites.
+	Sleep time.Duration
+}
+
+// DefaultGCConfig returns the default value log garbage collection config.
+func DefaultGCConfig() GCConfig {
+	return GCConfig{
+		Interval:     DefaultGCInterval,
+		DiscardRatio: DefaultGCDiscardRatio,
+		Sleep:        DefaultGCSleep,
+	}
+}
+
 // DefaultBadgerOptions creates the default options for badger.
-// For our purposes, we don't want the store to perform any garbage collection or
-// expire newly added keys after a certain period, because:
-// 1. the data in the store will be light.
-// 2. we want to keep the data, i.e. confirms, for the longest time possible to be able
-// to retrieve them if needed.
+// For our purposes, we don't want the store to expire newly added keys after a certain
+// period, because we want to keep the data, i.e. confirms, for the longest time possible
+// to be able to retrieve them if needed. The old confirms are removed explicitly by pruning instead.
+// The value log garbage collection is disabled. Use `BadgerOptionsWithGC` to enable it.
 func DefaultBadgerOptions(path string) *badger.Options {
 	return &badger.Options{
 		GcDiscardRatio: 0,
@@ -20,3 +50,14 @@ func DefaultBadgerOptions(path string) *badger.Options {
 		Options:        badger2.DefaultOptions(path),
 	}
 }
+
+// BadgerOptionsWithGC creates the default options for badger, with the value log garbage collection
+// running in the background using the provided config.
+// Without it, the space of the deleted or overwritten values is never reclaimed.
+func BadgerOptionsWithGC(path string, gc GCConfig) *badger.Options {
+	opts := DefaultBadgerOptions(path)
+	opts.GcInterval = gc.Interval
+	opts.GcDiscardRatio = gc.DiscardRatio
+	opts.GcSleep = gc.Sleep
+	return opts
+}
@@ -0,0 +1,142 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/celestiaorg/orchestrator-relayer/store/fslock"
+	badger2 "github.com/dgraph-io/badger/v2"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+// DatabaseCheck the result of checking a badger database.
+type DatabaseCheck struct {
+	// Path the database directory.
+	Path string
+	// Err the error returned when opening or verifying the database. Nil if the database is valid.
+	Err error
+	// Repaired true if the database value log was truncated to repair it.
+	Repaired bool
+}
+
+// CheckReport the result of checking the store.
+type CheckReport struct {
+	// OrphanedLocks the lock files left by the processes that didn't shut down cleanly.
+	OrphanedLocks []string
+	// Databases the checked badger databases.
+	Databases []DatabaseCheck
+}
+
+// Healthy returns true if all the databases are valid, possibly after being repaired.
+func (r CheckReport) Healthy() bool {
+	for _, db := range r.Databases {
+		if db.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Check verifies the badger databases of the store under the provided path, and reports the orphaned
+// lock files left after an unclean shutdown.
+// If repair is true, the corrupted value logs are truncated, losing the writes that were not fully
+// persisted, and the orphaned lock files are removed. Otherwise, the databases are opened read-only
+// and the orphaned lock files are kept.
+// The store should not be in use, otherwise ErrOpened is returned.
+func Check(logger tmlog.Logger, path string, repair bool) (CheckReport, error) {
+	path, err := storePath(path)
+	if err != nil {
+		return CheckReport{}, err
+	}
+	if !Exists(path) {
+		return CheckReport{}, ErrNotInited
+	}
+
+	report := CheckReport{}
+	// the store lock file is removed when the store is closed, so it's orphaned if it exists
+	// while nobody holds it
+	hasLockFile := Exists(lockPath(path))
+	flock, err := fslock.Lock(lockPath(path))
+	if err != nil {
+		if errors.Is(err, fslock.ErrLocked) {
+			return CheckReport{}, ErrOpened
+		}
+		return CheckReport{}, err
+	}
+	if hasLockFile && !repair {
+		defer flock.Release() //nolint: errcheck
+	} else {
+		defer flock.Unlock() //nolint: errcheck
+	}
+	if hasLockFile {
+		report.OrphanedLocks = append(report.OrphanedLocks, lockPath(path))
+	}
+
+	for _, dbPath := range []string{dataPath(path), signaturePath(path)} {
+		if !Exists(dbPath) {
+			continue
+		}
+		backend, ok, err := DetectBackend(dbPath)
+		if err != nil {
+			return report, err
+		}
+		if !ok {
+			continue
+		}
+		if backend != BackendBadger {
+			logger.Info("skipping the verification of a non badger database", "path", dbPath, "backend", backend)
+			continue
+		}
+
+		// the badger lock file is removed when the database is closed, and the database can't be
+		// opened by another process while the store is locked
+		badgerLock := filepath.Join(dbPath, badgerLockFile)
+		if Exists(badgerLock) {
+			report.OrphanedLocks = append(report.OrphanedLocks, badgerLock)
+			if repair {
+				err = os.Remove(badgerLock)
+				if err != nil {
+					return report, err
+				}
+			}
+		}
+
+		logger.Info("verifying badger database", "path", dbPath)
+		report.Databases = append(report.Databases, checkBadger(dbPath, repair))
+	}
+	return report, nil
+}
+
+// checkBadger opens the badger database under the provided path and verifies its tables checksums.
+// If repair is true, the database value log is truncated if needed.
+func checkBadger(path string, repair bool) DatabaseCheck {
+	check := DatabaseCheck{Path: path}
+	opts := badger2.DefaultOptions(path).WithLogger(nil)
+	// the read-only mode refuses the databases that were not properly closed, as it can't replay
+	// their value log
+	db, err := badger2.Open(opts.WithReadOnly(!repair))
+	if err != nil && repair && isTruncateNeeded(err) {
+		db, err = badger2.Open(opts.WithTruncate(true))
+		check.Repaired = err == nil
+	}
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	err = db.VerifyChecksum()
+	if err != nil {
+		db.Close()
+		check.Err = err
+		return check
+	}
+	check.Err = db.Close()
+	return check
+}
+
+// isTruncateNeeded returns true if badger failed to open the database because its value log should
+// be truncated. Badger formats the error without wrapping it.
+func isTruncateNeeded(err error) bool {
+	return strings.Contains(err.Error(), badger2.ErrTruncateNeeded.Error())
+}
@@ -0,0 +1,77 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/store"
+	"github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	logger := tmlog.NewNopLogger()
+	path := t.TempDir()
+
+	initOptions := store.InitOptions{NeedDataStore: true, NeedSignatureStore: true}
+	options := store.OpenOptions{
+		HasDataStore:      true,
+		BadgerOptions:     store.DefaultBadgerOptions(path),
+		HasSignatureStore: true,
+	}
+	require.NoError(t, store.Init(logger, path, initOptions))
+	s, err := store.OpenStore(logger, path, options)
+	require.NoError(t, err)
+	require.NoError(t, s.DataStore.Put(ctx, datastore.NewKey("/data"), []byte("data")))
+
+	// the store can't be checked while in use
+	_, err = store.Check(logger, path, false)
+	assert.ErrorIs(t, err, store.ErrOpened)
+	require.NoError(t, s.Close(logger, options))
+
+	report, err := store.Check(logger, path, false)
+	require.NoError(t, err)
+	assert.True(t, report.Healthy())
+	assert.Empty(t, report.OrphanedLocks)
+	assert.Len(t, report.Databases, 2)
+
+	// simulate an unclean shutdown: the lock files are left, and the value log has a partial write
+	lockFile := filepath.Join(path, "lock")
+	badgerLockFile := filepath.Join(path, store.DataPath, "LOCK")
+	require.NoError(t, os.WriteFile(lockFile, []byte("1234"), 0o600))
+	require.NoError(t, os.WriteFile(badgerLockFile, []byte("1234"), 0o600))
+	vlog := filepath.Join(path, store.DataPath, "000000.vlog")
+	f, err := os.OpenFile(vlog, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("partial write"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	report, err = store.Check(logger, path, false)
+	require.NoError(t, err)
+	assert.False(t, report.Healthy())
+	assert.ElementsMatch(t, []string{lockFile, badgerLockFile}, report.OrphanedLocks)
+	// the orphaned lock files are only removed when repairing
+	assert.True(t, store.Exists(badgerLockFile))
+	assert.True(t, store.Exists(lockFile))
+
+	report, err = store.Check(logger, path, true)
+	require.NoError(t, err)
+	assert.True(t, report.Healthy())
+	assert.True(t, report.Databases[0].Repaired)
+	assert.False(t, store.Exists(badgerLockFile))
+	assert.False(t, store.Exists(lockFile))
+
+	// the repaired store keeps the persisted data
+	s, err = store.OpenStore(logger, path, options)
+	require.NoError(t, err)
+	defer s.Close(logger, options) //nolint: errcheck
+	value, err := s.DataStore.Get(ctx, datastore.NewKey("/data"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), value)
+}
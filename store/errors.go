@@ -7,4 +7,32 @@ var (
 	ErrOpened = errors.New("store is in use")
 	// ErrNotInited is thrown on attempt to open Store without initialization.
 	ErrNotInited = errors.New("store is not initialized")
+	// ErrNewerStoreVersion is thrown on attempt to open a Store written by a newer binary.
+	ErrNewerStoreVersion = errors.New("store written by a newer binary")
+	// ErrOutdatedStoreVersion is thrown on attempt to open read-only a Store that needs to be migrated.
+	ErrOutdatedStoreVersion = errors.New("store written by an older binary")
+	// ErrMissingMigration is thrown when no migration upgrades the Store from its version.
+	ErrMissingMigration = errors.New("missing store migration")
+	// ErrStoreExists is thrown on attempt to restore a backup over an existing Store.
+	ErrStoreExists = errors.New("store already exists")
+	// ErrInvalidBackup is thrown on attempt to restore a malformed backup archive.
+	ErrInvalidBackup = errors.New("invalid store backup")
+	// ErrP2PKeyExists is thrown on attempt to put a P2P key under an existing name.
+	ErrP2PKeyExists = errors.New("p2p key already exists")
+	// ErrP2PKeyNotFound is thrown on attempt to get a non existing P2P key.
+	ErrP2PKeyNotFound = errors.New("p2p key not found")
+	// ErrPlaintextP2PKey is thrown on attempt to get a plaintext P2P key that was not migrated.
+	ErrPlaintextP2PKey = errors.New("p2p key not encrypted. run the `keys p2p migrate` command to encrypt it")
+	// ErrInvalidP2PPassphrase is thrown when the passphrase doesn't decrypt a P2P key.
+	ErrInvalidP2PPassphrase = errors.New("invalid p2p key passphrase")
+	// ErrInvalidP2PKeyFile is thrown on attempt to decrypt a malformed P2P key file.
+	ErrInvalidP2PKeyFile = errors.New("invalid p2p key file")
+	// ErrInvalidP2PKeyName is thrown on attempt to use a P2P key name that can't be a file name.
+	ErrInvalidP2PKeyName = errors.New("invalid p2p key name")
+	// ErrUnknownBackend is thrown on attempt to use an unsupported datastore backend.
+	ErrUnknownBackend = errors.New("unknown store backend")
+	// ErrBackendMismatch is thrown on attempt to open a datastore using a different backend than the one that wrote it.
+	ErrBackendMismatch = errors.New("store backend mismatch")
+	// ErrStoreCheckFailed is thrown when the store check finds corrupted databases or invalid records.
+	ErrStoreCheckFailed = errors.New("store check failed")
 )
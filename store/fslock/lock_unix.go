@@ -30,6 +30,15 @@ func (l *Locker) lock() (err error) {
 }
 
 func (l *Locker) unlock() error {
+	err := l.release()
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(l.path)
+}
+
+func (l *Locker) release() error {
 	err := syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN|syscall.LOCK_NB)
 	if err != nil {
 		return fmt.Errorf("fslock: unflocking error: %w", err)
@@ -42,5 +51,5 @@ func (l *Locker) unlock() error {
 		return fmt.Errorf("fslock: while closing file: %w", err)
 	}
 
-	return os.Remove(l.path)
+	return nil
 }
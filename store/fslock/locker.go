@@ -47,3 +47,12 @@ func (l *Locker) Unlock() error {
 
 	return l.unlock()
 }
+
+// Release frees up the lock, keeping the lock file.
+func (l *Locker) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+
+	return l.release()
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/celestiaorg/orchestrator-relayer/store/fslock"
 	"github.com/mitchellh/go-homedir"
@@ -19,6 +20,8 @@ const (
 	EVMKeyStorePath = "keystore/evm"
 	// P2PKeyStorePath the subdir for the path containing the p2p keystore.
 	P2PKeyStorePath = "keystore/p2p"
+	// SwarmKeyPath the subpath for the private network pre-shared key file.
+	SwarmKeyPath = "keystore/swarm.key"
 )
 
 // storePath clean up the store path.
@@ -33,6 +36,9 @@ type InitOptions struct {
 	NeedSignatureStore bool
 	NeedEVMKeyStore    bool
 	NeedP2PKeyStore    bool
+	// Backend the backend used for the data and signature stores. Defaults to DefaultBackend.
+	// The in-memory backend doesn't need their directories.
+	Backend Backend
 }
 
 // Init initializes the qgb file system in the directory under
@@ -46,6 +52,10 @@ func Init(log tmlog.Logger, path string, options InitOptions) error {
 	}
 	log.Info("initializing qgb store", "path", path)
 
+	// the stores created before the metadata was introduced are left without it so that
+	// they are migrated when opened.
+	isNew := !Exists(metadataPath(path)) && !hasLegacyLayout(path)
+
 	err = initRoot(path)
 	if err != nil {
 		return err
@@ -59,7 +69,8 @@ func Init(log tmlog.Logger, path string, options InitOptions) error {
 		return err
 	}
 
-	if options.NeedDataStore {
+	persistent := options.Backend.IsPersistent()
+	if options.NeedDataStore && persistent {
 		err = initDir(dataPath(path))
 		if err != nil {
 			return err
@@ -68,7 +79,7 @@ func Init(log tmlog.Logger, path string, options InitOptions) error {
 		log.Info("data dir initialized", "path", dataPath(path))
 	}
 
-	if options.NeedSignatureStore {
+	if options.NeedSignatureStore && persistent {
 		err = initDir(signaturePath(path))
 		if err != nil {
 			return err
@@ -95,6 +106,14 @@ func Init(log tmlog.Logger, path string, options InitOptions) error {
 		log.Info("evm keystore dir initialized", "path", evmKeyStorePath(path))
 	}
 
+	if isNew {
+		now := time.Now().UTC()
+		err = writeMetadata(path, Metadata{Version: CurrentVersion, CreatedAt: now, UpdatedAt: now})
+		if err != nil {
+			return err
+		}
+	}
+
 	err = flock.Unlock()
 	if err != nil {
 		return err
@@ -119,14 +138,17 @@ func IsInit(logger tmlog.Logger, path string, options InitOptions) bool {
 		return false
 	}
 
+	// the in-memory backend doesn't need the data and signature stores directories
+	persistent := options.Backend.IsPersistent()
+
 	// check if the data store exists if it's needed
-	if options.NeedDataStore && !Exists(dataPath(path)) {
+	if options.NeedDataStore && persistent && !Exists(dataPath(path)) {
 		logger.Info("data path not initialized", "path", path)
 		return false
 	}
 
 	// check if the signature store exists if it's needed
-	if options.NeedSignatureStore && !Exists(signaturePath(path)) {
+	if options.NeedSignatureStore && persistent && !Exists(signaturePath(path)) {
 		logger.Info("signature path not initialized", "path", path)
 		return false
 	}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// MetadataPath the subpath for the store metadata file.
+	MetadataPath = "metadata.json"
+	// CurrentVersion the store layout version written by this binary.
+	// Bumping it requires adding the corresponding migration to `Migrations`.
+	CurrentVersion uint64 = 1
+	// LegacyVersion the version of the stores created before the metadata was introduced.
+	LegacyVersion uint64 = 0
+)
+
+// Metadata the store metadata, recording the store layout version.
+type Metadata struct {
+	// Version the store layout version.
+	Version uint64 `json:"version"`
+	// CreatedAt the time the store was initialized, or the first time it was migrated for the legacy stores.
+	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt the time the store was last migrated.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReadMetadata reads the metadata of the store under the provided path.
+// The stores created before the metadata was introduced are reported with the LegacyVersion.
+func ReadMetadata(path string) (Metadata, error) {
+	path, err := storePath(path)
+	if err != nil {
+		return Metadata{}, err
+	}
+	encoded, err := os.ReadFile(metadataPath(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Metadata{Version: LegacyVersion}, nil
+		}
+		return Metadata{}, err
+	}
+	var md Metadata
+	err = json.Unmarshal(encoded, &md)
+	if err != nil {
+		return Metadata{}, err
+	}
+	return md, nil
+}
+
+// writeMetadata atomically replaces the metadata of the store under the provided path.
+func writeMetadata(path string, md Metadata) error {
+	encoded, err := json.MarshalIndent(md, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := metadataPath(path) + ".tmp"
+	err = os.WriteFile(tmp, encoded, 0o600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, metadataPath(path))
+}
+
+// hasLegacyLayout returns true if the store under the provided path has some contents but no metadata,
+// i.e. was created before the metadata was introduced.
+func hasLegacyLayout(path string) bool {
+	if Exists(metadataPath(path)) {
+		return false
+	}
+	for _, dir := range []string{dataPath(path), signaturePath(path), evmKeyStorePath(path), p2pKeyStorePath(path)} {
+		if Exists(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// metadataPath returns the metadata file path relative to the base directory.
+func metadataPath(base string) string {
+	return filepath.Join(base, MetadataPath)
+}
@@ -0,0 +1,147 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+// BackupsPath the subdir for the store backups taken before the migrations.
+const BackupsPath = "backups"
+
+// Migration upgrades the store layout from a version to the next one.
+type Migration struct {
+	// From the version migrated from. The store is at version From+1 once migrated.
+	From uint64
+	// Description what the migration changes.
+	Description string
+	// Migrate upgrades the store under the provided path in place. The store is locked while migrating.
+	// Nil if only the metadata version needs to be updated, in which case no backup is taken.
+	Migrate func(logger tmlog.Logger, path string) error
+}
+
+// Migrations the store migrations, ordered by version.
+var Migrations = []Migration{
+	{
+		From:        LegacyVersion,
+		Description: "record the store version in the metadata",
+	},
+}
+
+// Migrate upgrades the store under the provided path to the target version using the provided migrations.
+// If any migration changes the store layout, the store is backed up first under the BackupsPath subdir.
+// Returns ErrNewerStoreVersion if the store was written by a newer binary.
+// The store should be locked while migrating.
+func Migrate(logger tmlog.Logger, path string, migrations []Migration, targetVersion uint64) error {
+	md, err := ReadMetadata(path)
+	if err != nil {
+		return err
+	}
+	if md.Version > targetVersion {
+		return fmt.Errorf("%w: store version %d, supported version %d", ErrNewerStoreVersion, md.Version, targetVersion)
+	}
+	if md.Version == targetVersion {
+		return nil
+	}
+
+	// select the migrations to run
+	pending := make([]Migration, 0)
+	needsBackup := false
+	for version := md.Version; version < targetVersion; version++ {
+		found := false
+		for _, migration := range migrations {
+			if migration.From == version {
+				pending = append(pending, migration)
+				needsBackup = needsBackup || migration.Migrate != nil
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: from version %d", ErrMissingMigration, version)
+		}
+	}
+
+	if needsBackup {
+		backupPath := filepath.Join(path, BackupsPath, fmt.Sprintf("v%d-%d", md.Version, time.Now().Unix()))
+		logger.Info("backing up store before migrating it", "path", path, "backup", backupPath)
+		err = copyStore(path, backupPath)
+		if err != nil {
+			return fmt.Errorf("couldn't backup store before migrating it: %w", err)
+		}
+	}
+
+	if md.CreatedAt.IsZero() {
+		md.CreatedAt = time.Now().UTC()
+	}
+	for _, migration := range pending {
+		logger.Info("migrating store", "path", path, "from_version", migration.From, "to_version", migration.From+1, "description", migration.Description)
+		if migration.Migrate != nil {
+			err = migration.Migrate(logger, path)
+			if err != nil {
+				return fmt.Errorf("couldn't migrate store from version %d: %w", migration.From, err)
+			}
+		}
+		// the metadata is updated after each migration so that a failed one can be resumed
+		md.Version = migration.From + 1
+		md.UpdatedAt = time.Now().UTC()
+		err = writeMetadata(path, md)
+		if err != nil {
+			return err
+		}
+	}
+	logger.Info("store migrated", "path", path, "version", md.Version)
+	return nil
+}
+
+// copyStore copies the store contents to the destination directory, except for the lock file
+// and the previous backups.
+func copyStore(path string, destination string) error {
+	return filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(path, file)
+		if err != nil {
+			return err
+		}
+		if rel == BackupsPath || rel == "lock" {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destination, rel)
+		if entry.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		return copyFile(file, target, info.Mode().Perm())
+	})
+}
+
+func copyFile(source string, destination string, perm fs.FileMode) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(destination, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,96 @@
+package store_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+func TestStoreVersion(t *testing.T) {
+	logger := tmlog.NewNopLogger()
+	options := store.OpenOptions{HasEVMKeyStore: true, HasP2PKeyStore: true}
+	initOptions := store.InitOptions{NeedEVMKeyStore: true, NeedP2PKeyStore: true}
+
+	// the new stores are initialized with the current version
+	path := t.TempDir()
+	require.NoError(t, store.Init(logger, path, initOptions))
+	md, err := store.ReadMetadata(path)
+	require.NoError(t, err)
+	assert.Equal(t, store.CurrentVersion, md.Version)
+
+	// the legacy stores are migrated when opened
+	legacyPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(legacyPath, store.EVMKeyStorePath), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(legacyPath, store.P2PKeyStorePath), 0o755))
+	require.NoError(t, store.Init(logger, legacyPath, initOptions))
+	md, err = store.ReadMetadata(legacyPath)
+	require.NoError(t, err)
+	assert.Equal(t, store.LegacyVersion, md.Version)
+	// the legacy stores are not migrated when opened read-only
+	readOnlyOptions := options
+	readOnlyOptions.ReadOnly = true
+	_, err = store.OpenStore(logger, legacyPath, readOnlyOptions)
+	assert.ErrorIs(t, err, store.ErrOutdatedStoreVersion)
+	md, err = store.ReadMetadata(legacyPath)
+	require.NoError(t, err)
+	assert.Equal(t, store.LegacyVersion, md.Version)
+	_, err = store.OpenStore(logger, legacyPath, options)
+	require.NoError(t, err)
+	md, err = store.ReadMetadata(legacyPath)
+	require.NoError(t, err)
+	assert.Equal(t, store.CurrentVersion, md.Version)
+
+	// the stores written by a newer binary are refused
+	encoded, err := json.Marshal(store.Metadata{Version: store.CurrentVersion + 1})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(path, store.MetadataPath), encoded, 0o600))
+	_, err = store.OpenStore(logger, path, options)
+	assert.ErrorIs(t, err, store.ErrNewerStoreVersion)
+}
+
+func TestMigrate(t *testing.T) {
+	logger := tmlog.NewNopLogger()
+	path := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(path, store.DataPath), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(path, store.DataPath, "record"), []byte("v0"), 0o600))
+
+	migrated := make([]uint64, 0)
+	migrations := []store.Migration{
+		{From: 0, Description: "metadata only"},
+		{From: 1, Description: "rewrite the record", Migrate: func(_ tmlog.Logger, path string) error {
+			migrated = append(migrated, 1)
+			return os.WriteFile(filepath.Join(path, store.DataPath, "record"), []byte("v2"), 0o600)
+		}},
+	}
+
+	// no migration from version 2
+	err := store.Migrate(logger, path, migrations, 3)
+	assert.ErrorIs(t, err, store.ErrMissingMigration)
+
+	require.NoError(t, store.Migrate(logger, path, migrations, 2))
+	assert.Equal(t, []uint64{1}, migrated)
+	md, err := store.ReadMetadata(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), md.Version)
+	record, err := os.ReadFile(filepath.Join(path, store.DataPath, "record"))
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(record))
+
+	// the store was backed up before being migrated
+	backups, err := os.ReadDir(filepath.Join(path, store.BackupsPath))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	record, err = os.ReadFile(filepath.Join(path, store.BackupsPath, backups[0].Name(), store.DataPath, "record"))
+	require.NoError(t, err)
+	assert.Equal(t, "v0", string(record))
+
+	// already migrated stores are left untouched
+	require.NoError(t, store.Migrate(logger, path, migrations, 2))
+	assert.Equal(t, []uint64{1}, migrated)
+}
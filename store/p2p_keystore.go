@@ -0,0 +1,402 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	keystore2 "github.com/ipfs/boxo/keystore"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// p2pKeyFileExt the extension of the encrypted P2P key files.
+	p2pKeyFileExt = ".json"
+	// p2pKeyFileVersion the version of the encrypted P2P key files format.
+	p2pKeyFileVersion = 1
+	// p2pKeyPerms the encrypted P2P key files permissions, only readable by the owner.
+	p2pKeyPerms = 0o600
+
+	p2pKeyKDF      = "scrypt"
+	p2pKeyCipher   = "aes-256-gcm"
+	scryptR        = 8
+	scryptDKLen    = 32
+	scryptSaltSize = 32
+	// maxScryptP the highest scrypt parallelization parameter accepted when decrypting a key file.
+	maxScryptP = 8
+
+	// legacyKeyFilePrefix the prefix of the plaintext key files, followed by their base32 encoded names.
+	legacyKeyFilePrefix = "key_"
+)
+
+// legacyKeyCodec the encoding of the plaintext key files names.
+var legacyKeyCodec = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// encryptedP2PKey the encrypted P2P key file contents.
+type encryptedP2PKey struct {
+	Version int                `json:"version"`
+	Name    string             `json:"name"`
+	Crypto  encryptedP2PCrypto `json:"crypto"`
+}
+
+type encryptedP2PCrypto struct {
+	KDF        string          `json:"kdf"`
+	KDFParams  p2pScryptParams `json:"kdfparams"`
+	Cipher     string          `json:"cipher"`
+	Nonce      string          `json:"nonce"`
+	CipherText string          `json:"ciphertext"`
+}
+
+type p2pScryptParams struct {
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+}
+
+// P2PKeyStore a keystore for the P2P private keys, encrypting them using a passphrase.
+// The encryption key is derived from the passphrase using scrypt, and the private keys are
+// encrypted using AES-GCM.
+// The plaintext keys written by older binaries, in the same directory, are not returned until
+// migrated using MigratePlaintextKeys.
+type P2PKeyStore struct {
+	dir     string
+	scryptN int
+	scryptP int
+	// legacy the keystore of the plaintext keys written by older binaries.
+	legacy *keystore2.FSKeystore
+}
+
+// NewP2PKeyStore creates a new P2P keystore under the provided directory, using the provided
+// scrypt parameters for the new keys, e.g. keystore.StandardScryptN and keystore.StandardScryptP.
+func NewP2PKeyStore(dir string, scryptN int, scryptP int) (*P2PKeyStore, error) {
+	err := initDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	legacy, err := keystore2.NewFSKeystore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &P2PKeyStore{dir: dir, scryptN: scryptN, scryptP: scryptP, legacy: legacy}, nil
+}
+
+// Has returns true if an encrypted key exists under the provided name.
+func (ks *P2PKeyStore) Has(name string) (bool, error) {
+	err := validateP2PKeyName(name)
+	if err != nil {
+		return false, err
+	}
+	return Exists(ks.keyPath(name)), nil
+}
+
+// Put encrypts the provided key using the passphrase, and stores it under the provided name.
+// Returns ErrP2PKeyExists if a key, encrypted or not, already exists under the name.
+func (ks *P2PKeyStore) Put(name string, key crypto.PrivKey, passphrase string) error {
+	err := validateP2PKeyName(name)
+	if err != nil {
+		return err
+	}
+	has, err := ks.Has(name)
+	if err != nil {
+		return err
+	}
+	hasPlaintext, err := ks.legacy.Has(name)
+	if err != nil {
+		return err
+	}
+	if has || hasPlaintext {
+		return fmt.Errorf("%w: %s", ErrP2PKeyExists, name)
+	}
+	encoded, err := encryptP2PKey(name, key, passphrase, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(ks.keyPath(name), os.O_CREATE|os.O_WRONLY|os.O_EXCL, p2pKeyPerms)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(encoded)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Get decrypts the key stored under the provided name using the passphrase.
+// Returns ErrP2PKeyNotFound if no key exists under the name, ErrPlaintextP2PKey if it's a plaintext key
+// that should be migrated first, and ErrInvalidP2PPassphrase if the passphrase doesn't decrypt it.
+func (ks *P2PKeyStore) Get(name string, passphrase string) (crypto.PrivKey, error) {
+	err := validateP2PKeyName(name)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := os.ReadFile(ks.keyPath(name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			hasPlaintext, err := ks.legacy.Has(name)
+			if err != nil {
+				return nil, err
+			}
+			if hasPlaintext {
+				return nil, fmt.Errorf("%w: %s", ErrPlaintextP2PKey, name)
+			}
+			return nil, fmt.Errorf("%w: %s", ErrP2PKeyNotFound, name)
+		}
+		return nil, err
+	}
+	return decryptP2PKey(name, encoded, passphrase)
+}
+
+// Export decrypts the key stored under the provided name using the passphrase, and returns it
+// encrypted using the new passphrase, in the same format as the key files.
+func (ks *P2PKeyStore) Export(name string, passphrase string, newPassphrase string) ([]byte, error) {
+	key, err := ks.Get(name, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return encryptP2PKey(name, key, newPassphrase, ks.scryptN, ks.scryptP)
+}
+
+// Import decrypts the key file written by Export using the passphrase, and stores the key under the
+// provided name, encrypted using the new passphrase. The key file could have been exported under a
+// different name.
+func (ks *P2PKeyStore) Import(name string, encoded []byte, passphrase string, newPassphrase string) (crypto.PrivKey, error) {
+	var encrypted encryptedP2PKey
+	err := json.Unmarshal(encoded, &encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidP2PKeyFile, err.Error())
+	}
+	// the key is authenticated along with the name it was exported under
+	key, err := decryptP2PKey(encrypted.Name, encoded, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	err = ks.Put(name, key, newPassphrase)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Delete deletes the key stored under the provided name, encrypted or not.
+func (ks *P2PKeyStore) Delete(name string) error {
+	has, err := ks.Has(name)
+	if err != nil {
+		return err
+	}
+	if has {
+		return os.Remove(ks.keyPath(name))
+	}
+	hasPlaintext, err := ks.legacy.Has(name)
+	if err != nil {
+		return err
+	}
+	if hasPlaintext {
+		return ks.legacy.Delete(name)
+	}
+	return fmt.Errorf("%w: %s", ErrP2PKeyNotFound, name)
+}
+
+// List returns the names of the encrypted keys, sorted.
+func (ks *P2PKeyStore) List() ([]string, error) {
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), p2pKeyFileExt) {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), p2pKeyFileExt))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ListPlaintext returns the names of the plaintext keys written by older binaries, sorted.
+// The key files are listed directly, as the legacy keystore listing complains about the encrypted ones.
+func (ks *P2PKeyStore) ListPlaintext() ([]string, error) {
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), legacyKeyFilePrefix) {
+			continue
+		}
+		name, err := legacyKeyCodec.DecodeString(strings.ToUpper(strings.TrimPrefix(entry.Name(), legacyKeyFilePrefix)))
+		if err != nil {
+			continue
+		}
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// MigratePlaintextKeys encrypts the plaintext keys written by older binaries using the passphrase,
+// then deletes the plaintext ones.
+// Returns the names of the migrated keys.
+func (ks *P2PKeyStore) MigratePlaintextKeys(passphrase string) ([]string, error) {
+	names, err := ks.ListPlaintext()
+	if err != nil {
+		return nil, err
+	}
+	migrated := make([]string, 0, len(names))
+	for _, name := range names {
+		key, err := ks.legacy.Get(name)
+		if err != nil {
+			return migrated, err
+		}
+		has, err := ks.Has(name)
+		if err != nil {
+			return migrated, err
+		}
+		if has {
+			return migrated, fmt.Errorf("%w: %s", ErrP2PKeyExists, name)
+		}
+		encoded, err := encryptP2PKey(name, key, passphrase, ks.scryptN, ks.scryptP)
+		if err != nil {
+			return migrated, err
+		}
+		err = os.WriteFile(ks.keyPath(name), encoded, p2pKeyPerms)
+		if err != nil {
+			return migrated, err
+		}
+		// the plaintext key is only deleted once its encrypted version is written
+		err = ks.legacy.Delete(name)
+		if err != nil {
+			return migrated, err
+		}
+		migrated = append(migrated, name)
+	}
+	return migrated, nil
+}
+
+// keyPath returns the path of the encrypted key file stored under the provided name.
+func (ks *P2PKeyStore) keyPath(name string) string {
+	return filepath.Join(ks.dir, name+p2pKeyFileExt)
+}
+
+// validateP2PKeyName checks that the key name can be used as a file name.
+func validateP2PKeyName(name string) error {
+	if name == "" || strings.Contains(name, "/") || strings.Contains(name, string(filepath.Separator)) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("%w: %q", ErrInvalidP2PKeyName, name)
+	}
+	return nil
+}
+
+// encryptP2PKey encrypts the provided key using the passphrase. The key name is authenticated
+// along with the key, so that the key files can't be swapped.
+func encryptP2PKey(name string, key crypto.PrivKey, passphrase string, scryptN int, scryptP int) ([]byte, error) {
+	plaintext, err := crypto.MarshalPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, scryptSaltSize)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := gcm.Seal(nil, nonce, plaintext, []byte(name))
+	return json.MarshalIndent(encryptedP2PKey{
+		Version: p2pKeyFileVersion,
+		Name:    name,
+		Crypto: encryptedP2PCrypto{
+			KDF: p2pKeyKDF,
+			KDFParams: p2pScryptParams{
+				N:     scryptN,
+				R:     scryptR,
+				P:     scryptP,
+				DKLen: scryptDKLen,
+				Salt:  hex.EncodeToString(salt),
+			},
+			Cipher:     p2pKeyCipher,
+			Nonce:      hex.EncodeToString(nonce),
+			CipherText: hex.EncodeToString(ciphertext),
+		},
+	}, "", "  ")
+}
+
+// decryptP2PKey decrypts the key stored under the provided name using the passphrase.
+func decryptP2PKey(name string, encoded []byte, passphrase string) (crypto.PrivKey, error) {
+	var encrypted encryptedP2PKey
+	err := json.Unmarshal(encoded, &encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidP2PKeyFile, err.Error())
+	}
+	if encrypted.Version != p2pKeyFileVersion || encrypted.Crypto.KDF != p2pKeyKDF || encrypted.Crypto.Cipher != p2pKeyCipher {
+		return nil, fmt.Errorf("%w: unsupported version %d, kdf %s or cipher %s", ErrInvalidP2PKeyFile, encrypted.Version, encrypted.Crypto.KDF, encrypted.Crypto.Cipher)
+	}
+	params := encrypted.Crypto.KDFParams
+	// the parameters are checked before deriving the key so that a crafted key file can't exhaust the
+	// memory or the CPU
+	if params.N > keystore.StandardScryptN || params.R != scryptR || params.P < 1 || params.P > maxScryptP || params.DKLen != scryptDKLen {
+		return nil, fmt.Errorf("%w: unsupported scrypt parameters n %d, r %d, p %d, dklen %d", ErrInvalidP2PKeyFile, params.N, params.R, params.P, params.DKLen)
+	}
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidP2PKeyFile, err.Error())
+	}
+	nonce, err := hex.DecodeString(encrypted.Crypto.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidP2PKeyFile, err.Error())
+	}
+	ciphertext, err := hex.DecodeString(encrypted.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidP2PKeyFile, err.Error())
+	}
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidP2PKeyFile, err.Error())
+	}
+	gcm, err := newGCM(derivedKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidP2PKeyFile, err.Error())
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("%w: invalid nonce size", ErrInvalidP2PKeyFile)
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidP2PPassphrase, name)
+	}
+	return crypto.UnmarshalPrivateKey(plaintext)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,182 @@
+package store_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/store"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	keystore2 "github.com/ipfs/boxo/keystore"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestP2PKeyStore(t *testing.T, dir string) *store.P2PKeyStore {
+	ks, err := store.NewP2PKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	return ks
+}
+
+func TestP2PKeyStore(t *testing.T) {
+	dir := t.TempDir()
+	ks := newTestP2PKeyStore(t, dir)
+
+	priv, _, err := crypto.GenerateEd25519Key(nil)
+	require.NoError(t, err)
+
+	_, err = ks.Get("key", "123")
+	assert.ErrorIs(t, err, store.ErrP2PKeyNotFound)
+
+	require.NoError(t, ks.Put("key", priv, "123"))
+	err = ks.Put("key", priv, "123")
+	assert.ErrorIs(t, err, store.ErrP2PKeyExists)
+
+	has, err := ks.Has("key")
+	require.NoError(t, err)
+	assert.True(t, has)
+	names, err := ks.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key"}, names)
+
+	got, err := ks.Get("key", "123")
+	require.NoError(t, err)
+	assert.True(t, priv.Equals(got))
+
+	_, err = ks.Get("key", "wrong")
+	assert.ErrorIs(t, err, store.ErrInvalidP2PPassphrase)
+
+	// the key file doesn't contain the plaintext key
+	raw, err := crypto.MarshalPrivateKey(priv)
+	require.NoError(t, err)
+	encoded, err := os.ReadFile(filepath.Join(dir, "key.json"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), string(raw))
+
+	// the key files can't be swapped
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.json"), encoded, 0o600))
+	_, err = ks.Get("other", "123")
+	assert.ErrorIs(t, err, store.ErrInvalidP2PPassphrase)
+
+	err = ks.Put("../key", priv, "123")
+	assert.ErrorIs(t, err, store.ErrInvalidP2PKeyName)
+
+	require.NoError(t, ks.Delete("key"))
+	has, err = ks.Has("key")
+	require.NoError(t, err)
+	assert.False(t, has)
+}
+
+func TestP2PKeyStoreMigratePlaintextKeys(t *testing.T) {
+	dir := t.TempDir()
+	legacy, err := keystore2.NewFSKeystore(dir)
+	require.NoError(t, err)
+	priv, _, err := crypto.GenerateEd25519Key(nil)
+	require.NoError(t, err)
+	require.NoError(t, legacy.Put("legacy", priv))
+
+	ks := newTestP2PKeyStore(t, dir)
+	names, err := ks.ListPlaintext()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"legacy"}, names)
+
+	// the plaintext keys should be migrated before being used
+	_, err = ks.Get("legacy", "123")
+	assert.ErrorIs(t, err, store.ErrPlaintextP2PKey)
+	err = ks.Put("legacy", priv, "123")
+	assert.ErrorIs(t, err, store.ErrP2PKeyExists)
+
+	migrated, err := ks.MigratePlaintextKeys("123")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"legacy"}, migrated)
+
+	names, err = ks.ListPlaintext()
+	require.NoError(t, err)
+	assert.Empty(t, names)
+	got, err := ks.Get("legacy", "123")
+	require.NoError(t, err)
+	assert.True(t, priv.Equals(got))
+}
+
+func TestP2PKeyStoreExport(t *testing.T) {
+	ks := newTestP2PKeyStore(t, t.TempDir())
+	priv, _, err := crypto.GenerateEd25519Key(nil)
+	require.NoError(t, err)
+	require.NoError(t, ks.Put("key", priv, "123"))
+
+	_, err = ks.Export("key", "wrong", "456")
+	assert.ErrorIs(t, err, store.ErrInvalidP2PPassphrase)
+
+	exported, err := ks.Export("key", "123", "456")
+	require.NoError(t, err)
+
+	// the exported key can be used as a key file of another keystore
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "key.json"), exported, 0o600))
+	other := newTestP2PKeyStore(t, dir)
+	_, err = other.Get("key", "123")
+	assert.ErrorIs(t, err, store.ErrInvalidP2PPassphrase)
+	got, err := other.Get("key", "456")
+	require.NoError(t, err)
+	assert.True(t, priv.Equals(got))
+}
+
+func TestP2PKeyStoreImport(t *testing.T) {
+	ks := newTestP2PKeyStore(t, t.TempDir())
+	priv, _, err := crypto.GenerateEd25519Key(nil)
+	require.NoError(t, err)
+	require.NoError(t, ks.Put("key", priv, "123"))
+	exported, err := ks.Export("key", "123", "456")
+	require.NoError(t, err)
+
+	other := newTestP2PKeyStore(t, t.TempDir())
+	_, err = other.Import("imported", exported, "123", "789")
+	assert.ErrorIs(t, err, store.ErrInvalidP2PPassphrase)
+	_, err = other.Import("imported", []byte("not a key file"), "456", "789")
+	assert.ErrorIs(t, err, store.ErrInvalidP2PKeyFile)
+
+	// the key can be imported under a different name and passphrase
+	imported, err := other.Import("imported", exported, "456", "789")
+	require.NoError(t, err)
+	assert.True(t, priv.Equals(imported))
+	got, err := other.Get("imported", "789")
+	require.NoError(t, err)
+	assert.True(t, priv.Equals(got))
+
+	_, err = other.Import("imported", exported, "456", "789")
+	assert.ErrorIs(t, err, store.ErrP2PKeyExists)
+}
+
+func TestP2PKeyStoreRejectsCostlyScryptParams(t *testing.T) {
+	ks := newTestP2PKeyStore(t, t.TempDir())
+	priv, _, err := crypto.GenerateEd25519Key(nil)
+	require.NoError(t, err)
+	require.NoError(t, ks.Put("key", priv, "123"))
+	exported, err := ks.Export("key", "123", "456")
+	require.NoError(t, err)
+
+	for name, params := range map[string]map[string]int{
+		"n":      {"n": 1 << 30},
+		"r":      {"r": 1 << 10},
+		"p":      {"p": 1 << 20},
+		"zero p": {"p": 0},
+		"dklen":  {"dklen": 1 << 20},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var keyFile map[string]interface{}
+			require.NoError(t, json.Unmarshal(exported, &keyFile))
+			kdfParams := keyFile["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})
+			for param, value := range params {
+				kdfParams[param] = value
+			}
+			crafted, err := json.Marshal(keyFile)
+			require.NoError(t, err)
+
+			// the key file is rejected before deriving the key
+			_, err = newTestP2PKeyStore(t, t.TempDir()).Import("imported", crafted, "456", "789")
+			assert.ErrorIs(t, err, store.ErrInvalidP2PKeyFile)
+		})
+	}
+}
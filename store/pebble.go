@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+)
+
+// PebbleDatastore a datastore backed by pebble.
+type PebbleDatastore struct {
+	db *pebble.DB
+}
+
+var _ datastore.Batching = &PebbleDatastore{}
+
+// NewPebbleDatastore opens the pebble datastore under the provided path, creating it if needed.
+func NewPebbleDatastore(path string) (*PebbleDatastore, error) {
+	return openPebbleDatastore(path, &pebble.Options{})
+}
+
+// openPebbleDatastore opens the pebble datastore under the provided path using the provided options.
+func openPebbleDatastore(path string, options *pebble.Options) (*PebbleDatastore, error) {
+	db, err := pebble.Open(path, options)
+	if err != nil {
+		return nil, err
+	}
+	return &PebbleDatastore{db: db}, nil
+}
+
+func (d *PebbleDatastore) Get(_ context.Context, key datastore.Key) ([]byte, error) {
+	value, closer, err := d.db.Get(key.Bytes())
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			return nil, datastore.ErrNotFound
+		}
+		return nil, err
+	}
+	defer closer.Close()
+	// the value is only valid until the closer is closed
+	buf := make([]byte, len(value))
+	copy(buf, value)
+	return buf, nil
+}
+
+func (d *PebbleDatastore) Has(ctx context.Context, key datastore.Key) (bool, error) {
+	return datastore.GetBackedHas(ctx, d, key)
+}
+
+func (d *PebbleDatastore) GetSize(ctx context.Context, key datastore.Key) (int, error) {
+	return datastore.GetBackedSize(ctx, d, key)
+}
+
+func (d *PebbleDatastore) Put(_ context.Context, key datastore.Key, value []byte) error {
+	return d.db.Set(key.Bytes(), value, pebble.Sync)
+}
+
+func (d *PebbleDatastore) Delete(_ context.Context, key datastore.Key) error {
+	return d.db.Delete(key.Bytes(), pebble.Sync)
+}
+
+func (d *PebbleDatastore) Sync(_ context.Context, _ datastore.Key) error {
+	return d.db.Flush()
+}
+
+// Query iterates over the keys under the query prefix, and applies the rest of the query naively.
+func (d *PebbleDatastore) Query(_ context.Context, q dsq.Query) (dsq.Results, error) {
+	// the original query is kept so that the results return it
+	qNaive := q
+	opts := &pebble.IterOptions{}
+	prefix := datastore.NewKey(q.Prefix).String()
+	if prefix != "/" {
+		opts.LowerBound = []byte(prefix + "/")
+		opts.UpperBound = prefixUpperBound(opts.LowerBound)
+		qNaive.Prefix = ""
+	}
+	iter := d.db.NewIter(opts)
+	next := iter.First
+	if len(q.Orders) > 0 {
+		switch q.Orders[0].(type) {
+		case dsq.OrderByKey, *dsq.OrderByKey:
+			qNaive.Orders = nil
+		default:
+		}
+	}
+	r := dsq.ResultsFromIterator(q, dsq.Iterator{
+		Next: func() (dsq.Result, bool) {
+			if !next() {
+				return dsq.Result{}, false
+			}
+			next = iter.Next
+			entry := dsq.Entry{Key: string(iter.Key()), Size: len(iter.Value())}
+			if !q.KeysOnly {
+				entry.Value = make([]byte, len(iter.Value()))
+				copy(entry.Value, iter.Value())
+			}
+			return dsq.Result{Entry: entry}, true
+		},
+		Close: iter.Close,
+	})
+	return dsq.NaiveQueryApply(qNaive, r), nil
+}
+
+func (d *PebbleDatastore) Batch(_ context.Context) (datastore.Batch, error) {
+	return &pebbleBatch{batch: d.db.NewBatch()}, nil
+}
+
+func (d *PebbleDatastore) Close() error {
+	return d.db.Close()
+}
+
+// pebbleBatch a batch of writes committed atomically.
+type pebbleBatch struct {
+	batch *pebble.Batch
+}
+
+func (b *pebbleBatch) Put(_ context.Context, key datastore.Key, value []byte) error {
+	return b.batch.Set(key.Bytes(), value, nil)
+}
+
+func (b *pebbleBatch) Delete(_ context.Context, key datastore.Key) error {
+	return b.batch.Delete(key.Bytes(), nil)
+}
+
+func (b *pebbleBatch) Commit(_ context.Context) error {
+	return b.batch.Commit(pebble.Sync)
+}
+
+// prefixUpperBound returns the smallest key greater than all the keys starting with the prefix.
+func prefixUpperBound(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
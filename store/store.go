@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"path/filepath"
 
-	keystore2 "github.com/ipfs/boxo/keystore"
-
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 
 	"github.com/celestiaorg/orchestrator-relayer/store/fslock"
@@ -21,32 +19,43 @@ type Store struct {
 	DataStore datastore.Batching
 
 	// SignatureStore provides a signature store - a KV store for all orchestrator signatures to be stored on disk.
-	SignatureStore *badger.Datastore
+	SignatureStore datastore.Batching
 
 	// EVMKeyStore provides a keystore for EVM private keys.
 	EVMKeyStore *keystore.KeyStore
 
 	// P2PKeyStore provides a keystore for P2P private keys.
-	P2PKeyStore *keystore2.FSKeystore
+	P2PKeyStore *P2PKeyStore
 
 	// Path the path to the qgb storage root.
 	Path string
 
 	// storeLock protects directory when the data store is open.
 	storeLock *fslock.Locker
+	// keepLockFile true if the lock file existed before opening the store read-only.
+	keepLockFile bool
 }
 
 // OpenOptions contains the options used to create the store
 type OpenOptions struct {
-	HasDataStore      bool
+	HasDataStore bool
+	// Backend the backend used for the data and signature stores. Defaults to DefaultBackend.
+	Backend Backend
+	// BadgerOptions the badger options, only needed when using the badger backend.
 	BadgerOptions     *badger.Options
 	HasSignatureStore bool
 	HasEVMKeyStore    bool
 	HasP2PKeyStore    bool
+	// ReadOnly opens the store without modifying it: the stores written by older binaries are refused
+	// with ErrOutdatedStoreVersion instead of being migrated, the data and signature stores are opened
+	// read-only, and an orphaned lock file is kept when closing the store.
+	ReadOnly bool
 }
 
 // OpenStore creates new FS Store under the given 'path'.
 // To be opened, the Store must be initialized first, otherwise ErrNotInited is thrown.
+// The Stores written by older binaries are migrated to the CurrentVersion, unless opened ReadOnly,
+// and the ones written by newer binaries are refused with ErrNewerStoreVersion.
 // OpenStore takes a file Lock on directory, hence only one Store can be opened at a time under the
 // given 'path', otherwise ErrOpened is thrown.
 // The store is locked only in the case of also opening the data store, however, in the case
@@ -57,18 +66,38 @@ func OpenStore(logger tmlog.Logger, path string, options OpenOptions) (*Store, e
 		return nil, err
 	}
 
+	backend := options.Backend
+	if backend == "" {
+		backend = DefaultBackend
+	}
+
 	ok := IsInit(logger, path, InitOptions{
 		NeedDataStore:      options.HasDataStore,
 		NeedEVMKeyStore:    options.HasEVMKeyStore,
 		NeedP2PKeyStore:    options.HasP2PKeyStore,
 		NeedSignatureStore: options.HasSignatureStore,
+		Backend:            backend,
 	})
 	if !ok {
 		return nil, ErrNotInited
 	}
 
+	md, err := ReadMetadata(path)
+	if err != nil {
+		return nil, err
+	}
+	if md.Version > CurrentVersion {
+		return nil, fmt.Errorf("%w: store version %d, supported version %d", ErrNewerStoreVersion, md.Version, CurrentVersion)
+	}
+	if options.ReadOnly && md.Version < CurrentVersion {
+		return nil, fmt.Errorf("%w: store version %d, supported version %d", ErrOutdatedStoreVersion, md.Version, CurrentVersion)
+	}
+
 	var flock *fslock.Locker
-	if options.HasDataStore || options.HasSignatureStore {
+	// the lock file is removed when the store is closed, so it's orphaned if it exists while nobody holds it
+	keepLockFile := options.ReadOnly && Exists(lockPath(path))
+	needsLock := options.HasDataStore || options.HasSignatureStore
+	if needsLock || md.Version < CurrentVersion {
 		flock, err = fslock.Lock(lockPath(path))
 		if err != nil {
 			if errors.Is(err, fslock.ErrLocked) {
@@ -76,27 +105,47 @@ func OpenStore(logger tmlog.Logger, path string, options OpenOptions) (*Store, e
 			}
 			return nil, err
 		}
-		if options.BadgerOptions == nil {
+		if needsLock && backend == BackendBadger && options.BadgerOptions == nil {
 			flock.Unlock() //nolint: errcheck
 			return nil, fmt.Errorf("badger store options needed to open the store")
 		}
 	}
 
-	var ds *badger.Datastore
+	// upgrade the stores written by older binaries
+	if md.Version < CurrentVersion {
+		err = Migrate(logger, path, Migrations, CurrentVersion)
+		if err != nil {
+			flock.Unlock() //nolint: errcheck
+			return nil, err
+		}
+		// the store is only locked for the migration when opening the keystores
+		if !needsLock {
+			err = flock.Unlock()
+			if err != nil {
+				return nil, err
+			}
+			flock = nil
+		}
+	}
+
+	var ds datastore.Batching
 	if options.HasDataStore {
-		ds, err = badger.NewDatastore(dataPath(path), options.BadgerOptions)
+		ds, err = openBackendDatastore(backend, dataPath(path), options.BadgerOptions, options.ReadOnly)
 		if err != nil {
 			flock.Unlock() //nolint: errcheck
-			return nil, fmt.Errorf("can't open Badger Datastore: %w", err)
+			return nil, fmt.Errorf("can't open %s Datastore: %w", backend, err)
 		}
 	}
 
-	var sigStore *badger.Datastore
+	var sigStore datastore.Batching
 	if options.HasSignatureStore {
-		sigStore, err = badger.NewDatastore(signaturePath(path), options.BadgerOptions)
+		sigStore, err = openBackendDatastore(backend, signaturePath(path), options.BadgerOptions, options.ReadOnly)
 		if err != nil {
+			if ds != nil {
+				ds.Close()
+			}
 			flock.Unlock() //nolint: errcheck
-			return nil, fmt.Errorf("can't open Badger SignatureStore: %w", err)
+			return nil, fmt.Errorf("can't open %s SignatureStore: %w", backend, err)
 		}
 	}
 
@@ -105,19 +154,20 @@ func OpenStore(logger tmlog.Logger, path string, options OpenOptions) (*Store, e
 		evmKs = keystore.NewKeyStore(evmKeyStorePath(path), keystore.StandardScryptN, keystore.StandardScryptP)
 	}
 
-	var p2pKs *keystore2.FSKeystore
+	var p2pKs *P2PKeyStore
 	if options.HasP2PKeyStore {
-		p2pKs, err = keystore2.NewFSKeystore(p2pKeyStorePath(path))
+		p2pKs, err = NewP2PKeyStore(p2pKeyStorePath(path), keystore.StandardScryptN, keystore.StandardScryptP)
 		if err != nil {
 			logger.Error("couldn't open p2p keystore", "path", p2pKeyStorePath(path))
 			return nil, err
 		}
 	}
 
-	logger.Info("successfully opened store", "path", path)
+	logger.Info("successfully opened store", "path", path, "backend", backend)
 
 	return &Store{
 		storeLock:      flock,
+		keepLockFile:   keepLockFile,
 		Path:           path,
 		DataStore:      ds,
 		SignatureStore: sigStore,
@@ -126,10 +176,25 @@ func OpenStore(logger tmlog.Logger, path string, options OpenOptions) (*Store, e
 	}, nil
 }
 
+// openBackendDatastore checks that the datastore under the provided path was written by the backend,
+// then opens it.
+func openBackendDatastore(backend Backend, path string, badgerOptions *badger.Options, readOnly bool) (datastore.Batching, error) {
+	err := checkBackend(backend, path)
+	if err != nil {
+		return nil, err
+	}
+	return openDatastore(backend, path, badgerOptions, readOnly)
+}
+
 // Close closes an opened store and removes the lock file.
 func (s Store) Close(logger tmlog.Logger, options OpenOptions) error {
 	if options.HasDataStore || options.HasSignatureStore {
-		err := s.storeLock.Unlock()
+		var err error
+		if s.keepLockFile {
+			err = s.storeLock.Release()
+		} else {
+			err = s.storeLock.Unlock()
+		}
 		if err != nil {
 			logger.Info("couldn't unlock store", "path", s.Path, "err", err.Error())
 			return err
@@ -177,3 +242,14 @@ func evmKeyStorePath(base string) string {
 func p2pKeyStorePath(base string) string {
 	return filepath.Join(base, P2PKeyStorePath)
 }
+
+// swarmKeyPath returns the private network pre-shared key file path relative to the base directory.
+func swarmKeyPath(base string) string {
+	return filepath.Join(base, SwarmKeyPath)
+}
+
+// SwarmKeyPath returns the path to the private network pre-shared key file of the store.
+// The file is not guaranteed to exist.
+func (s Store) SwarmKeyPath() string {
+	return swarmKeyPath(s.Path)
+}
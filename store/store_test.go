@@ -1,10 +1,13 @@
 package store_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/celestiaorg/orchestrator-relayer/store"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	tmlog "github.com/tendermint/tendermint/libs/log"
 )
 
@@ -42,4 +45,22 @@ func TestStore(t *testing.T) {
 
 	err = s.Close(logger, options)
 	assert.NoError(t, err)
+
+	// the orphaned lock file is kept when opening the store read-only
+	lockFile := filepath.Join(path, "lock")
+	require.NoError(t, os.WriteFile(lockFile, []byte("1234"), 0o600))
+	readOnlyOptions := options
+	readOnlyOptions.ReadOnly = true
+	readOnlyOptions.BadgerOptions = store.DefaultBadgerOptions(path)
+	readOnlyOptions.BadgerOptions.Options = readOnlyOptions.BadgerOptions.Options.WithReadOnly(true)
+	s, err = store.OpenStore(logger, path, readOnlyOptions)
+	require.NoError(t, err)
+	require.NoError(t, s.Close(logger, readOnlyOptions))
+	assert.True(t, store.Exists(lockFile))
+
+	// and removed otherwise
+	s, err = store.OpenStore(logger, path, options)
+	require.NoError(t, err)
+	require.NoError(t, s.Close(logger, options))
+	assert.False(t, store.Exists(lockFile))
 }
@@ -2,6 +2,7 @@ package testing
 
 import (
 	"context"
+	"net"
 	"time"
 
 	tmlog "github.com/tendermint/tendermint/libs/log"
@@ -58,6 +59,51 @@ func NewDHTNetwork(ctx context.Context, count int) *DHTNetwork {
 	}
 }
 
+// MulticastAvailable returns true if the machine has a non loopback network interface that is up and
+// supports multicast, which is required by the mDNS discovery.
+func MulticastAvailable() bool {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return false
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp != 0 && iface.Flags&net.FlagMulticast != 0 && iface.Flags&net.FlagLoopback == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// NewMDNSDHTNetwork creates a new DHT test network running in-memory, similar to `NewDHTNetwork`.
+// However, the nodes are not given any bootstrapper, and discover each other using mDNS instead.
+// The discovery is stopped when the context is done.
+func NewMDNSDHTNetwork(ctx context.Context, count int) *DHTNetwork {
+	if count <= 1 {
+		panic("can't create a test network with a negative nodes count or only 1 DHT node")
+	}
+	hosts := make([]host.Host, count)
+	stores := make([]ds.Batching, count)
+	dhts := make([]*p2p.QgbDHT, count)
+	for i := 0; i < count; i++ {
+		hosts[i], stores[i], dhts[i] = NewTestDHT(ctx, nil)
+		err := p2p.StartMDNSDiscovery(ctx, hosts[i], tmlog.NewNopLogger())
+		if err != nil {
+			panic(err)
+		}
+	}
+	// to give time for the nodes to discover each other
+	err := WaitForPeerTableToUpdate(ctx, dhts, time.Minute)
+	if err != nil {
+		panic(err)
+	}
+	return &DHTNetwork{
+		Context: ctx,
+		Hosts:   hosts,
+		Stores:  stores,
+		DHTs:    dhts,
+	}
+}
+
 // NewTestDHT creates a test DHT not connected to any peers.
 func NewTestDHT(ctx context.Context, bootstrappers []peer.AddrInfo) (host.Host, ds.Batching, *p2p.QgbDHT) {
 	h, err := libp2p.New()
@@ -84,7 +130,7 @@ func WaitForPeerTableToUpdate(ctx context.Context, dhts []*p2p.QgbDHT, timeout t
 		case <-ticker.C:
 			allPeersConnected := func() bool {
 				for _, dht := range dhts {
-					if len(dht.RoutingTable().ListPeers()) == 0 {
+					if len(dht.ListPeers()) == 0 {
 						return false
 					}
 				}
@@ -0,0 +1,229 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// ConfirmEncoding the encoding used to store and propagate the confirms.
+type ConfirmEncoding uint8
+
+const (
+	// JSONConfirmEncoding the legacy, unversioned, Json encoding of the confirms.
+	JSONConfirmEncoding ConfirmEncoding = iota
+	// BinaryConfirmEncoding the versioned binary encoding defined by the ConfirmRecord.
+	BinaryConfirmEncoding
+)
+
+// String returns the name of the confirm encoding.
+func (e ConfirmEncoding) String() string {
+	switch e {
+	case JSONConfirmEncoding:
+		return "json"
+	case BinaryConfirmEncoding:
+		return "binary"
+	default:
+		return fmt.Sprintf("unknown(%d)", e)
+	}
+}
+
+// ParseConfirmEncoding parses a confirm encoding from its name.
+func ParseConfirmEncoding(name string) (ConfirmEncoding, error) {
+	switch strings.ToLower(name) {
+	case "json":
+		return JSONConfirmEncoding, nil
+	case "binary":
+		return BinaryConfirmEncoding, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownConfirmEncoding, name)
+	}
+}
+
+// ConfirmRecordVersion the current version of the confirm record schema.
+// It should only be bumped for breaking changes. New fields can be added, using new tags,
+// without bumping the version as the unknown fields are skipped when decoding.
+const ConfirmRecordVersion = uint64(1)
+
+// confirmRecordMagic prefixes the binary encoded confirm records.
+// It starts with a zero byte so that it can never be confused with a Json encoded confirm.
+var confirmRecordMagic = []byte{0x00, 'q', 'g', 'b'}
+
+// confirm record fields tags.
+const (
+	confirmRecordNonceTag     = uint64(1)
+	confirmRecordDigestTag    = uint64(2)
+	confirmRecordSignatureTag = uint64(3)
+	confirmRecordSignerTag    = uint64(4)
+)
+
+// ConfirmRecord the versioned binary representation of a confirm.
+// Unlike the Json encoded confirms, it carries the nonce and digest it signs, so that
+// the value is meaningful on its own.
+// Format: <magic><version uvarint>(<tag uvarint><length uvarint><value>)*
+type ConfirmRecord struct {
+	// Version the schema version of the record.
+	Version uint64
+	// Nonce the attestation nonce.
+	Nonce uint64
+	// Digest the signed digest: the sign bytes for valsets, and the data root tuple root
+	// for data commitments.
+	Digest ethcmn.Hash
+	// Signature the EVM signature over the digest.
+	Signature []byte
+	// Signer the EVM address of the signer.
+	Signer ethcmn.Address
+}
+
+// IsConfirmRecord returns true if the encoded value is a binary confirm record.
+func IsConfirmRecord(encoded []byte) bool {
+	return bytes.HasPrefix(encoded, confirmRecordMagic)
+}
+
+// MarshalConfirmRecord encodes a confirm record to binary.
+// The record is always encoded using the current ConfirmRecordVersion.
+func MarshalConfirmRecord(record ConfirmRecord) []byte {
+	buf := make([]byte, 0, 128)
+	buf = append(buf, confirmRecordMagic...)
+	buf = binary.AppendUvarint(buf, ConfirmRecordVersion)
+	buf = appendConfirmRecordField(buf, confirmRecordNonceTag, binary.AppendUvarint(nil, record.Nonce))
+	buf = appendConfirmRecordField(buf, confirmRecordDigestTag, record.Digest.Bytes())
+	buf = appendConfirmRecordField(buf, confirmRecordSignatureTag, record.Signature)
+	buf = appendConfirmRecordField(buf, confirmRecordSignerTag, record.Signer.Bytes())
+	return buf
+}
+
+func appendConfirmRecordField(buf []byte, tag uint64, value []byte) []byte {
+	buf = binary.AppendUvarint(buf, tag)
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+// UnmarshalConfirmRecord decodes a binary confirm record.
+// Unknown fields are skipped to allow adding new fields without breaking older nodes.
+// Returns an error if the record was encoded using a newer schema version.
+func UnmarshalConfirmRecord(encoded []byte) (ConfirmRecord, error) {
+	if !IsConfirmRecord(encoded) {
+		return ConfirmRecord{}, fmt.Errorf("%w: missing magic prefix", ErrInvalidConfirmRecord)
+	}
+	buf := encoded[len(confirmRecordMagic):]
+	version, n := binary.Uvarint(buf)
+	if n <= 0 {
+		return ConfirmRecord{}, fmt.Errorf("%w: invalid version", ErrInvalidConfirmRecord)
+	}
+	if version == 0 || version > ConfirmRecordVersion {
+		return ConfirmRecord{}, fmt.Errorf("%w: %d", ErrUnsupportedConfirmRecordVersion, version)
+	}
+	buf = buf[n:]
+
+	record := ConfirmRecord{Version: version}
+	var hasNonce, hasDigest, hasSignature, hasSigner bool
+	for len(buf) > 0 {
+		tag, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return ConfirmRecord{}, fmt.Errorf("%w: invalid field tag", ErrInvalidConfirmRecord)
+		}
+		buf = buf[n:]
+		length, n := binary.Uvarint(buf)
+		if n <= 0 || length > uint64(len(buf[n:])) {
+			return ConfirmRecord{}, fmt.Errorf("%w: invalid field length", ErrInvalidConfirmRecord)
+		}
+		buf = buf[n:]
+		value := buf[:length]
+		buf = buf[length:]
+
+		switch tag {
+		case confirmRecordNonceTag:
+			nonce, n := binary.Uvarint(value)
+			if n <= 0 || n != len(value) {
+				return ConfirmRecord{}, fmt.Errorf("%w: invalid nonce", ErrInvalidConfirmRecord)
+			}
+			record.Nonce = nonce
+			hasNonce = true
+		case confirmRecordDigestTag:
+			if len(value) != ethcmn.HashLength {
+				return ConfirmRecord{}, fmt.Errorf("%w: invalid digest length %d", ErrInvalidConfirmRecord, len(value))
+			}
+			record.Digest = ethcmn.BytesToHash(value)
+			hasDigest = true
+		case confirmRecordSignatureTag:
+			record.Signature = append([]byte{}, value...)
+			hasSignature = true
+		case confirmRecordSignerTag:
+			if len(value) != ethcmn.AddressLength {
+				return ConfirmRecord{}, fmt.Errorf("%w: invalid signer length %d", ErrInvalidConfirmRecord, len(value))
+			}
+			record.Signer = ethcmn.BytesToAddress(value)
+			hasSigner = true
+		default:
+			// unknown field added by a newer node, skipping it.
+		}
+	}
+	if !hasNonce || !hasDigest || !hasSignature || !hasSigner {
+		return ConfirmRecord{}, fmt.Errorf("%w: missing fields", ErrInvalidConfirmRecord)
+	}
+	return record, nil
+}
+
+// newConfirmRecord creates a confirm record from the confirm fields.
+func newConfirmRecord(nonce uint64, digest string, signature string, signer string) (ConfirmRecord, error) {
+	if !ethcmn.IsHexAddress(signer) {
+		return ConfirmRecord{}, fmt.Errorf("%w: invalid signer %s", ErrInvalidConfirmRecord, signer)
+	}
+	bDigest, err := hex.DecodeString(strings.TrimPrefix(digest, "0x"))
+	if err != nil {
+		return ConfirmRecord{}, err
+	}
+	if len(bDigest) != ethcmn.HashLength {
+		return ConfirmRecord{}, fmt.Errorf("%w: invalid digest length %d", ErrInvalidConfirmRecord, len(bDigest))
+	}
+	bSignature, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil {
+		return ConfirmRecord{}, err
+	}
+	return ConfirmRecord{
+		Version:   ConfirmRecordVersion,
+		Nonce:     nonce,
+		Digest:    ethcmn.BytesToHash(bDigest),
+		Signature: bSignature,
+		Signer:    ethcmn.HexToAddress(signer),
+	}, nil
+}
+
+// EncodeValsetConfirm encodes a valset confirm using the provided encoding.
+// The nonce and sign bytes are only used by the binary encoding.
+func EncodeValsetConfirm(encoding ConfirmEncoding, nonce uint64, signBytes string, vc ValsetConfirm) ([]byte, error) {
+	switch encoding {
+	case JSONConfirmEncoding:
+		return MarshalValsetConfirm(vc)
+	case BinaryConfirmEncoding:
+		record, err := newConfirmRecord(nonce, signBytes, vc.Signature, vc.EthAddress)
+		if err != nil {
+			return nil, err
+		}
+		return MarshalConfirmRecord(record), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownConfirmEncoding, encoding)
+	}
+}
+
+// EncodeDataCommitmentConfirm encodes a data commitment confirm using the provided encoding.
+// The nonce and data root tuple root are only used by the binary encoding.
+func EncodeDataCommitmentConfirm(encoding ConfirmEncoding, nonce uint64, dataRootTupleRoot string, dcc DataCommitmentConfirm) ([]byte, error) {
+	switch encoding {
+	case JSONConfirmEncoding:
+		return MarshalDataCommitmentConfirm(dcc)
+	case BinaryConfirmEncoding:
+		record, err := newConfirmRecord(nonce, dataRootTupleRoot, dcc.Signature, dcc.EthAddress)
+		if err != nil {
+			return nil, err
+		}
+		return MarshalConfirmRecord(record), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownConfirmEncoding, encoding)
+	}
+}
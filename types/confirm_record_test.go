@@ -0,0 +1,94 @@
+package types_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfirmRecord = types.ConfirmRecord{
+	Version:   types.ConfirmRecordVersion,
+	Nonce:     10,
+	Digest:    ethcmn.HexToHash("0x1234"),
+	Signature: []byte{0x01, 0x02, 0x03},
+	Signer:    ethcmn.HexToAddress("0x966e6f22781EF6a6A82BBB4DB3df8E225DfD9488"),
+}
+
+func TestConfirmRecordRoundTrip(t *testing.T) {
+	encoded := types.MarshalConfirmRecord(testConfirmRecord)
+	assert.True(t, types.IsConfirmRecord(encoded))
+
+	decoded, err := types.UnmarshalConfirmRecord(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, testConfirmRecord, decoded)
+}
+
+func TestUnmarshalConfirmRecordSkipsUnknownFields(t *testing.T) {
+	encoded := types.MarshalConfirmRecord(testConfirmRecord)
+	// field added by a newer node
+	encoded = binary.AppendUvarint(encoded, 100)
+	encoded = binary.AppendUvarint(encoded, 2)
+	encoded = append(encoded, 0xaa, 0xbb)
+
+	decoded, err := types.UnmarshalConfirmRecord(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, testConfirmRecord, decoded)
+}
+
+func TestUnmarshalConfirmRecordNewerVersion(t *testing.T) {
+	encoded := types.MarshalConfirmRecord(testConfirmRecord)
+	// the version directly follows the 4 bytes magic
+	encoded[4] = byte(types.ConfirmRecordVersion + 1)
+
+	_, err := types.UnmarshalConfirmRecord(encoded)
+	assert.ErrorIs(t, err, types.ErrUnsupportedConfirmRecordVersion)
+}
+
+func TestUnmarshalConfirmRecordInvalid(t *testing.T) {
+	encoded := types.MarshalConfirmRecord(testConfirmRecord)
+
+	_, err := types.UnmarshalConfirmRecord(encoded[:len(encoded)-1])
+	assert.ErrorIs(t, err, types.ErrInvalidConfirmRecord)
+
+	_, err = types.UnmarshalConfirmRecord([]byte(`{"EthAddress":"eth_address","Signature":"signature"}`))
+	assert.ErrorIs(t, err, types.ErrInvalidConfirmRecord)
+}
+
+func TestUnmarshalBinaryEncodedConfirms(t *testing.T) {
+	vc := types.ValsetConfirm{
+		EthAddress: testConfirmRecord.Signer.Hex(),
+		Signature:  "010203",
+	}
+	encodedVC, err := types.EncodeValsetConfirm(types.BinaryConfirmEncoding, testConfirmRecord.Nonce, testConfirmRecord.Digest.Hex(), vc)
+	require.NoError(t, err)
+	decodedVC, err := types.UnmarshalValsetConfirm(encodedVC)
+	require.NoError(t, err)
+	assert.Equal(t, vc, decodedVC)
+
+	dcc := types.DataCommitmentConfirm{
+		EthAddress: testConfirmRecord.Signer.Hex(),
+		Signature:  "010203",
+	}
+	encodedDCC, err := types.EncodeDataCommitmentConfirm(types.BinaryConfirmEncoding, testConfirmRecord.Nonce, testConfirmRecord.Digest.Hex(), dcc)
+	require.NoError(t, err)
+	decodedDCC, err := types.UnmarshalDataCommitmentConfirm(encodedDCC)
+	require.NoError(t, err)
+	assert.Equal(t, dcc, decodedDCC)
+}
+
+func TestParseConfirmEncoding(t *testing.T) {
+	encoding, err := types.ParseConfirmEncoding("json")
+	require.NoError(t, err)
+	assert.Equal(t, types.JSONConfirmEncoding, encoding)
+
+	encoding, err = types.ParseConfirmEncoding("binary")
+	require.NoError(t, err)
+	assert.Equal(t, types.BinaryConfirmEncoding, encoding)
+
+	_, err = types.ParseConfirmEncoding("protobuf")
+	assert.ErrorIs(t, err, types.ErrUnknownConfirmEncoding)
+}
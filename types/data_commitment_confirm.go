@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -40,8 +41,19 @@ func MarshalDataCommitmentConfirm(dcc DataCommitmentConfirm) ([]byte, error) {
 	return encoded, nil
 }
 
-// UnmarshalDataCommitmentConfirm Decodes a data commitment confirm from Json bytes.
+// UnmarshalDataCommitmentConfirm Decodes a data commitment confirm from Json bytes or from a binary ConfirmRecord.
+// Both formats are accepted to allow migrating to the binary encoding.
 func UnmarshalDataCommitmentConfirm(encoded []byte) (DataCommitmentConfirm, error) {
+	if IsConfirmRecord(encoded) {
+		record, err := UnmarshalConfirmRecord(encoded)
+		if err != nil {
+			return DataCommitmentConfirm{}, err
+		}
+		return DataCommitmentConfirm{
+			Signature:  hex.EncodeToString(record.Signature),
+			EthAddress: record.Signer.Hex(),
+		}, nil
+	}
 	var dataCommitmentConfirm DataCommitmentConfirm
 	err := json.Unmarshal(encoded, &dataCommitmentConfirm)
 	if err != nil {
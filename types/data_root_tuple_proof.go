@@ -0,0 +1,88 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+
+	wrapper "github.com/celestiaorg/quantum-gravity-bridge/wrappers/QuantumGravityBridge.sol"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/tendermint/tendermint/crypto/merkle"
+)
+
+// DataRootTupleInclusionProof contains the parameters expected by the QGB contract
+// `verifyAttestation` method to verify that a data root tuple was committed to.
+// The field names follow the contract ABI.
+type DataRootTupleInclusionProof struct {
+	// TupleRootNonce the nonce of the data root tuple root committing to the tuple.
+	TupleRootNonce uint64 `json:"tupleRootNonce"`
+	// Tuple the data root tuple of the proven height.
+	Tuple DataRootTuple `json:"tuple"`
+	// Proof the binary Merkle proof of the tuple to the data root tuple root.
+	Proof BinaryMerkleProof `json:"proof"`
+}
+
+// DataRootTuple the JSON representation of the contract DataRootTuple struct.
+type DataRootTuple struct {
+	Height   uint64 `json:"height"`
+	DataRoot string `json:"dataRoot"`
+}
+
+// BinaryMerkleProof the JSON representation of the contract BinaryMerkleProof struct.
+type BinaryMerkleProof struct {
+	SideNodes []string `json:"sideNodes"`
+	Key       uint64   `json:"key"`
+	NumLeaves uint64   `json:"numLeaves"`
+}
+
+// NewDataRootTupleInclusionProof creates a new DataRootTupleInclusionProof from the data root
+// of the provided height and its Merkle inclusion proof to the tuple root having the provided nonce.
+func NewDataRootTupleInclusionProof(
+	tupleRootNonce uint64,
+	height uint64,
+	dataRoot []byte,
+	proof merkle.Proof,
+) (*DataRootTupleInclusionProof, error) {
+	if len(dataRoot) != 32 {
+		return nil, fmt.Errorf("%w: data root should be 32 bytes, got %d", ErrInvalid, len(dataRoot))
+	}
+	sideNodes := make([]string, len(proof.Aunts))
+	for i, aunt := range proof.Aunts {
+		if len(aunt) != 32 {
+			return nil, fmt.Errorf("%w: proof side node should be 32 bytes, got %d", ErrInvalid, len(aunt))
+		}
+		sideNodes[i] = ethcmn.BytesToHash(aunt).Hex()
+	}
+	return &DataRootTupleInclusionProof{
+		TupleRootNonce: tupleRootNonce,
+		Tuple: DataRootTuple{
+			Height:   height,
+			DataRoot: ethcmn.BytesToHash(dataRoot).Hex(),
+		},
+		Proof: BinaryMerkleProof{
+			SideNodes: sideNodes,
+			Key:       uint64(proof.Index),
+			NumLeaves: uint64(proof.Total),
+		},
+	}, nil
+}
+
+// WrapperTuple returns the data root tuple as expected by the QGB contract wrapper.
+func (p DataRootTupleInclusionProof) WrapperTuple() wrapper.DataRootTuple {
+	return wrapper.DataRootTuple{
+		Height:   big.NewInt(0).SetUint64(p.Tuple.Height),
+		DataRoot: ethcmn.HexToHash(p.Tuple.DataRoot),
+	}
+}
+
+// WrapperProof returns the binary Merkle proof as expected by the QGB contract wrapper.
+func (p DataRootTupleInclusionProof) WrapperProof() wrapper.BinaryMerkleProof {
+	sideNodes := make([][32]byte, len(p.Proof.SideNodes))
+	for i, node := range p.Proof.SideNodes {
+		sideNodes[i] = ethcmn.HexToHash(node)
+	}
+	return wrapper.BinaryMerkleProof{
+		SideNodes: sideNodes,
+		Key:       big.NewInt(0).SetUint64(p.Proof.Key),
+		NumLeaves: big.NewInt(0).SetUint64(p.Proof.NumLeaves),
+	}
+}
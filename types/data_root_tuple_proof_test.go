@@ -0,0 +1,49 @@
+package types_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/celestiaorg/orchestrator-relayer/types"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/merkle"
+)
+
+func TestNewDataRootTupleInclusionProof(t *testing.T) {
+	dataRoot := bytes.Repeat([]byte{1}, 32)
+	proof := merkle.Proof{
+		Total:    4,
+		Index:    2,
+		LeafHash: bytes.Repeat([]byte{2}, 32),
+		Aunts:    [][]byte{bytes.Repeat([]byte{3}, 32), bytes.Repeat([]byte{4}, 32)},
+	}
+
+	result, err := types.NewDataRootTupleInclusionProof(10, 102, dataRoot, proof)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), result.TupleRootNonce)
+	assert.Equal(t, uint64(102), result.Tuple.Height)
+	assert.Equal(t, ethcmn.BytesToHash(dataRoot).Hex(), result.Tuple.DataRoot)
+	assert.Equal(t, uint64(2), result.Proof.Key)
+	assert.Equal(t, uint64(4), result.Proof.NumLeaves)
+
+	wrapperTuple := result.WrapperTuple()
+	assert.Equal(t, uint64(102), wrapperTuple.Height.Uint64())
+	assert.Equal(t, dataRoot, wrapperTuple.DataRoot[:])
+
+	wrapperProof := result.WrapperProof()
+	require.Len(t, wrapperProof.SideNodes, 2)
+	assert.Equal(t, proof.Aunts[0], wrapperProof.SideNodes[0][:])
+	assert.Equal(t, proof.Aunts[1], wrapperProof.SideNodes[1][:])
+	assert.Equal(t, uint64(2), wrapperProof.Key.Uint64())
+	assert.Equal(t, uint64(4), wrapperProof.NumLeaves.Uint64())
+}
+
+func TestNewDataRootTupleInclusionProofInvalidInput(t *testing.T) {
+	_, err := types.NewDataRootTupleInclusionProof(10, 102, []byte{1}, merkle.Proof{})
+	assert.ErrorIs(t, err, types.ErrInvalid)
+
+	_, err = types.NewDataRootTupleInclusionProof(10, 102, bytes.Repeat([]byte{1}, 32), merkle.Proof{Aunts: [][]byte{{1, 2}}})
+	assert.ErrorIs(t, err, types.ErrInvalid)
+}
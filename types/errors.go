@@ -12,4 +12,7 @@ var (
 	ErrAttestationNotFound                 = errors.New("attestation not found")
 	ErrUnmarshalValset                     = errors.New("couldn't unmarshal valset")
 	ErrAttestationNotValsetRequest         = errors.New("attestation is not a valset request")
+	ErrUnknownConfirmEncoding              = errors.New("unknown confirm encoding")
+	ErrInvalidConfirmRecord                = errors.New("invalid confirm record")
+	ErrUnsupportedConfirmRecordVersion     = errors.New("unsupported confirm record version")
 )
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/hex"
 	"encoding/json"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -43,8 +44,19 @@ func MarshalValsetConfirm(vs ValsetConfirm) ([]byte, error) {
 	return encoded, nil
 }
 
-// UnmarshalValsetConfirm Decodes a valset confirm from Json bytes.
+// UnmarshalValsetConfirm Decodes a valset confirm from Json bytes or from a binary ConfirmRecord.
+// Both formats are accepted to allow migrating to the binary encoding.
 func UnmarshalValsetConfirm(encoded []byte) (ValsetConfirm, error) {
+	if IsConfirmRecord(encoded) {
+		record, err := UnmarshalConfirmRecord(encoded)
+		if err != nil {
+			return ValsetConfirm{}, err
+		}
+		return ValsetConfirm{
+			EthAddress: record.Signer.Hex(),
+			Signature:  hex.EncodeToString(record.Signature),
+		}, nil
+	}
 	var valsetConfirm ValsetConfirm
 	err := json.Unmarshal(encoded, &valsetConfirm)
 	if err != nil {